@@ -78,6 +78,29 @@ func initConfig() {
 	}
 
 	viper.SetDefault("screenshot_storage", "/tmp/actor")
+	viper.SetDefault("screenshot_retention.interval", "1h")
+	viper.SetDefault("s3.presign_expiry", "1h")
+	viper.SetDefault("screenshot_analysis.threshold", 6)
+	viper.SetDefault("firmware.storage", "/tmp/actor/firmware")
+	viper.SetDefault("firmware.verify_timeout", "30m")
+	viper.SetDefault("firmware.verify_interval", "1m")
+	viper.SetDefault("firmware.max_upload_bytes", 1<<30)
+	viper.SetDefault("blades.scan_concurrency", 10)
+	viper.SetDefault("registry.concurrency", 10)
+	viper.SetDefault("discovery.timeout", "2s")
+	viper.SetDefault("discovery.concurrency", 20)
+	viper.SetDefault("discovery.rate", 50)
+	viper.SetDefault("discovery.max_addresses", 4096)
+	viper.SetDefault("certificates.timeout", "10s")
+	viper.SetDefault("certificates.concurrency", 20)
+	viper.SetDefault("certificates.expiry_warning", "720h")
+	// state is durable, tmpfiles cleanup or a reboot would wipe it under /tmp
+	viper.SetDefault("rotation.state_file", "/var/lib/actor/rotations.json")
+	viper.SetDefault("scheduler.state_file", "/var/lib/actor/schedules.json")
+	viper.SetDefault("scheduler.retention", "24h")
+	viper.SetDefault("webhooks.max_retries", 3)
+	viper.SetDefault("webhooks.backoff", "1s")
+	viper.SetDefault("webhooks.timeout", "10s")
 	viper.AutomaticEnv() // read in environment variables that match
 
 	// If a config file hasn't been found, bail out
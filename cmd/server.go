@@ -20,6 +20,17 @@ import (
 
 	"github.com/bmc-toolbox/actor/internal"
 	"github.com/bmc-toolbox/actor/internal/actions"
+	"github.com/bmc-toolbox/actor/internal/bmcconfig"
+	"github.com/bmc-toolbox/actor/internal/credentials"
+	"github.com/bmc-toolbox/actor/internal/discovery"
+	"github.com/bmc-toolbox/actor/internal/firmware"
+	"github.com/bmc-toolbox/actor/internal/providers"
+	"github.com/bmc-toolbox/actor/internal/registry"
+	"github.com/bmc-toolbox/actor/internal/rotation"
+	"github.com/bmc-toolbox/actor/internal/safeguard"
+	"github.com/bmc-toolbox/actor/internal/scheduler"
+	"github.com/bmc-toolbox/actor/internal/screenshot"
+	"github.com/bmc-toolbox/actor/internal/webhook"
 	"github.com/bmc-toolbox/actor/routes"
 	"github.com/bmc-toolbox/actor/server"
 	metrics "github.com/bmc-toolbox/gin-go-metrics"
@@ -55,6 +66,7 @@ var serverCmd = &cobra.Command{
 			Address:           viper.GetString("bind_to"),
 			IsDebug:           viper.GetBool("debug"),
 			ScreenshotStorage: viper.GetString("screenshot_storage"),
+			FirmwareStorage:   viper.GetString("firmware.storage"),
 		}
 
 		middlewares := []gin.HandlerFunc{
@@ -89,24 +101,239 @@ func createAPIs() *server.APIs {
 		bmcPassword = string(bmcPassBytes)
 	}
 
-	hostExecutorFactory := internal.NewHostExecutorFactory(bmcUsername, bmcPassword, viper.GetBool("s3.enabled"))
-	hostAPI := routes.NewHostAPI(actions.NewPlanMaker(sleepExecutorFactory, hostExecutorFactory))
+	credentialStore := credentials.NewFileStore(viper.GetString("credentials.directory"))
+	keyring := credentials.NewKeyring(credentialStore, bmcPassword)
 
-	chassisExecutorFactory := internal.NewChassisExecutorFactory(bmcUsername, bmcPassword)
-	chassisAPI := routes.NewChassisAPI(actions.NewPlanMaker(sleepExecutorFactory, chassisExecutorFactory))
+	var fleet *registry.Registry
+	if file := viper.GetString("registry.file"); file != "" {
+		var err error
+		if fleet, err = registry.New(file); err != nil {
+			log.Fatal(err)
+		}
+		keyring.WithRefs(fleet)
+	}
+
+	workflows := actions.Workflows{}
+	if err := viper.UnmarshalKey("workflows", &workflows); err != nil {
+		log.Fatalf("failed to parse workflows: %s", err)
+	}
+	if err := workflows.Validate(); err != nil {
+		log.Fatal(err)
+	}
 
-	bladeByPosExecutorFactory := internal.NewBladeByPosExecutorFactory(bmcUsername, bmcPassword)
-	bladeByPosAPI := routes.NewBladeByPosAPI(actions.NewPlanMaker(sleepExecutorFactory, bladeByPosExecutorFactory))
+	screenshotStore, err := newScreenshotStore()
+	if err != nil {
+		log.Fatal(err)
+	}
 
-	bladeBySerialExecutorFactory := internal.NewBladeBySerialExecutorFactory(bmcUsername, bmcPassword)
-	bladeBySerialAPI := routes.NewBladeBySerialAPI(actions.NewPlanMaker(sleepExecutorFactory, bladeBySerialExecutorFactory))
+	analyzer, err := screenshot.NewAnalyzer(
+		viper.GetString("screenshot_analysis.references"),
+		viper.GetInt("screenshot_analysis.threshold"),
+	)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	artifacts, err := firmware.NewArtifacts(
+		viper.GetString("firmware.storage"),
+		viper.GetString("firmware.url"),
+		viper.GetStringSlice("firmware.sources"),
+	)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	allowList := firmware.AllowList{}
+	if err := viper.UnmarshalKey("firmware.allowlist", &allowList); err != nil {
+		log.Fatalf("failed to parse firmware allow-list: %s", err)
+	}
+
+	firmwareUpdater := firmware.NewUpdater(
+		artifacts,
+		allowList,
+		internal.NewFirmwareConnector(bmcUsername, keyring),
+		viper.GetDuration("firmware.verify_timeout"),
+		viper.GetDuration("firmware.verify_interval"),
+	)
+	chassisFirmwareUpdater := firmware.NewUpdater(
+		artifacts,
+		allowList,
+		internal.NewChassisFirmwareConnector(bmcUsername, keyring),
+		viper.GetDuration("firmware.verify_timeout"),
+		viper.GetDuration("firmware.verify_interval"),
+	)
+
+	hostExecutorFactory := internal.NewHostExecutorFactory(bmcUsername, keyring, screenshotStore, analyzer)
+	solExecutorFactory := internal.NewSolExecutorFactory(bmcUsername, keyring, screenshotStore)
+	selExecutorFactory := internal.NewSelExecutorFactory(bmcUsername, keyring)
+	firmwareExecutorFactory := internal.NewFirmwareExecutorFactory(firmwareUpdater)
+	chassisFirmwareExecutorFactory := internal.NewFirmwareExecutorFactory(chassisFirmwareUpdater)
+	chassisExecutorFactory := internal.NewChassisExecutorFactory(bmcUsername, keyring)
+	bladeIndex := providers.NewBladeIndex()
+	bladeByPosExecutorFactory := internal.NewBladeByPosExecutorFactory(bmcUsername, keyring, screenshotStore, analyzer, bladeIndex)
+	bladeBySerialExecutorFactory := internal.NewBladeBySerialExecutorFactory(bmcUsername, keyring, screenshotStore, analyzer, bladeIndex)
+
+	safeguardConfig := safeguard.Config{}
+	if err := viper.UnmarshalKey("safeguard", &safeguardConfig); err != nil {
+		log.Fatalf("failed to parse safeguard: %s", err)
+	}
+	guard := safeguard.New(safeguardConfig)
+
+	planMakers := &actions.PlanMakers{
+		Host:          actions.NewPlanMaker(sleepExecutorFactory, solExecutorFactory, selExecutorFactory, firmwareExecutorFactory, hostExecutorFactory).WithWorkflows(actions.TargetHost, workflows).WithGuard(guard),
+		Chassis:       actions.NewPlanMaker(sleepExecutorFactory, chassisFirmwareExecutorFactory, chassisExecutorFactory).WithWorkflows(actions.TargetChassis, workflows).WithGuard(guard),
+		BladeByPos:    actions.NewPlanMaker(sleepExecutorFactory, bladeByPosExecutorFactory).WithWorkflows(actions.TargetBlade, workflows).WithGuard(guard),
+		BladeBySerial: actions.NewPlanMaker(sleepExecutorFactory, bladeBySerialExecutorFactory).WithWorkflows(actions.TargetBlade, workflows).WithGuard(guard),
+	}
+
+	notifier := webhook.New(webhook.Config{
+		URLs:            viper.GetStringSlice("webhooks.urls"),
+		Secret:          viper.GetString("webhooks.secret"),
+		AllowPerRequest: viper.GetBool("webhooks.allow_per_request"),
+		MaxRetries:      viper.GetInt("webhooks.max_retries"),
+		Backoff:         viper.GetDuration("webhooks.backoff"),
+		Timeout:         viper.GetDuration("webhooks.timeout"),
+		DeadLetterFile:  viper.GetString("webhooks.dead_letter_file"),
+	})
+
+	authorizer := routes.NewAuthorizer(viper.GetStringSlice("authorization.tokens"))
+
+	apis := &server.APIs{
+		HostAPI:          routes.NewHostAPI(planMakers.Host, notifier, authorizer),
+		ChassisAPI:       routes.NewChassisAPI(planMakers.Chassis, notifier, authorizer),
+		BladeByPosAPI:    routes.NewBladeByPosAPI(planMakers.BladeByPos, notifier, authorizer),
+		BladeBySerialAPI: routes.NewBladeBySerialAPI(planMakers.BladeBySerial, notifier, authorizer),
+		ScreenshotAPI:    routes.NewScreenshotAPI(screenshotStore),
+		SelAPI:           routes.NewSelAPI(internal.NewSelReader(bmcUsername, keyring)),
+		SensorAPI:        routes.NewSensorAPI(internal.NewSensorReader(bmcUsername, keyring)),
+		FirmwareAPI:      routes.NewFirmwareAPI(internal.NewFirmwareReader(bmcUsername, keyring, firmwareUpdater, chassisFirmwareUpdater), artifacts, authorizer, viper.GetInt64("firmware.max_upload_bytes")),
+		UserAPI:          routes.NewUserAPI(internal.NewUserManager(bmcUsername, keyring, credentialStore), authorizer),
+		ConfigAPI: routes.NewConfigAPI(
+			internal.NewConfigManager(
+				bmcUsername,
+				keyring,
+				bmcconfig.NewApplier(credentialStore, bmcUsername),
+				bmcconfig.NewProfiles(viper.GetString("config_profiles.directory")),
+			),
+			authorizer,
+		),
+		BladeAPI: routes.NewBladeAPI(internal.NewBladeLocator(
+			bmcUsername,
+			keyring,
+			bladeIndex,
+			bladeChassis(fleet),
+			viper.GetInt("blades.scan_concurrency"),
+		)),
+		CertificateAPI: routes.NewCertificateAPI(
+			internal.NewCertificateManager(
+				bmcUsername,
+				keyring,
+				viper.GetDuration("certificates.timeout"),
+				viper.GetInt("certificates.concurrency"),
+			),
+			authorizer,
+			viper.GetDuration("certificates.expiry_warning"),
+		),
+	}
 
-	return &server.APIs{
-		HostAPI:          hostAPI,
-		ChassisAPI:       chassisAPI,
-		BladeByPosAPI:    bladeByPosAPI,
-		BladeBySerialAPI: bladeBySerialAPI,
+	retention := screenshot.Retention{
+		MaxAge:        viper.GetDuration("screenshot_retention.max_age"),
+		MaxPerHost:    viper.GetInt("screenshot_retention.max_per_host"),
+		MaxTotalBytes: viper.GetInt64("screenshot_retention.max_total_bytes"),
 	}
+	if retention.IsEnabled() {
+		screenshot.NewJanitor(screenshotStore, retention, viper.GetDuration("screenshot_retention.interval")).Start()
+	}
+
+	rotator, err := rotation.New(
+		internal.NewRotationProvider(bmcUsername),
+		keyring,
+		credentialStore,
+		viper.GetString("rotation.state_file"),
+	)
+	if err != nil {
+		log.Fatal(err)
+	}
+	apis.RotationAPI = routes.NewRotationAPI(rotator, authorizer)
+
+	apis.SafeguardAPI = routes.NewSafeguardAPI(guard, authorizer)
+
+	// the scanner can't register the BMCs found without the registry
+	var registrar discovery.Registrar
+	if fleet != nil {
+		registrar = fleet
+	}
+	apis.DiscoveryAPI = routes.NewDiscoveryAPI(
+		discovery.New(
+			internal.NewDiscoveryProber(bmcUsername, keyring, viper.GetDuration("discovery.timeout")),
+			registrar,
+			viper.GetInt("discovery.concurrency"),
+			viper.GetFloat64("discovery.rate"),
+			viper.GetInt("discovery.max_addresses"),
+		),
+		authorizer,
+	)
+
+	// the hosts in request bodies are checked against the registry like the :host of paths
+	var policy routes.HostPolicy
+	if fleet != nil {
+		apis.RegistryAPI = routes.NewRegistryAPI(fleet, authorizer, viper.GetBool("registry.reject_unregistered"))
+		apis.BulkAPI = routes.NewBulkAPI(fleet, planMakers, notifier, authorizer, viper.GetInt("registry.concurrency"))
+
+		policy = apis.RegistryAPI
+		apis.CertificateAPI.WithPolicy(policy)
+		apis.RotationAPI.WithPolicy(policy)
+	}
+
+	if viper.GetBool("scheduler.enabled") {
+		actionScheduler, err := scheduler.New(
+			planMakers,
+			notifier,
+			viper.GetString("scheduler.state_file"),
+			viper.GetDuration("scheduler.retention"),
+		)
+		if err != nil {
+			log.Fatal(err)
+		}
+		actionScheduler.Start()
+
+		apis.ScheduleAPI = routes.NewScheduleAPI(actionScheduler, notifier, authorizer).WithPolicy(policy)
+	}
+
+	return apis
+}
+
+// bladeChassis lists the configured chassis and the ones registered at the time of the call
+func bladeChassis(fleet *registry.Registry) func() []string {
+	configured := viper.GetStringSlice("blades.chassis")
+	return func() []string {
+		chassis := append([]string{}, configured...)
+		if fleet != nil {
+			chassis = append(chassis, fleet.Chassis()...)
+		}
+		return chassis
+	}
+}
+
+func newScreenshotStore() (screenshot.Store, error) {
+	if !viper.GetBool("s3.enabled") {
+		return screenshot.NewLocalStore(viper.GetString("screenshot_storage"), "/screenshot")
+	}
+
+	return screenshot.NewS3Store(screenshot.S3Config{
+		Bucket:          viper.GetString("s3.bucket"),
+		Folder:          viper.GetString("s3.folder"),
+		Region:          viper.GetString("s3.region"),
+		AccessKeyID:     viper.GetString("s3.access_key_id"),
+		SecretAccessKey: viper.GetString("s3.secret_access_key"),
+		Endpoint:        viper.GetString("s3.endpoint"),
+		ACL:             viper.GetString("s3.acl"),
+
+		ServerSideEncryption: viper.GetString("s3.server_side_encryption"),
+		SSEKMSKeyID:          viper.GetString("s3.sse_kms_key_id"),
+		StorageClass:         viper.GetString("s3.storage_class"),
+		PresignExpiry:        viper.GetDuration("s3.presign_expiry"),
+	})
 }
 
 func init() {
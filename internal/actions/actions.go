@@ -1,5 +1,7 @@
 package actions
 
+import "strings"
+
 const (
 	IsOn          = "ison"
 	PowerOn       = "poweron"
@@ -10,5 +12,40 @@ const (
 
 	PxeOnce = "pxeonce"
 
+	// SelClear erases the System Event Log, it is privileged
+	SelClear = "selclear"
+
+	// UpdateFirmware starts a firmware update in the background and optionally verifies the new version, it is privileged
+	UpdateFirmware = "updatefirmware <artifact> [version]"
+
 	Screenshot = "screenshot"
+	// ScreenshotAnalyze takes a screenshot and compares it with the previous one and the reference images
+	ScreenshotAnalyze = "screenshot-analyze"
+
+	Sleep = "sleep <duration>"
+
+	// Require fails the plan unless the condition holds: psu-redundant, on, off or temp with <, <=, > or >=, e.g. temp<35
+	Require = "require <condition>"
+
+	// DynamicPower switches dynamic power supply engagement of a chassis on or off
+	DynamicPower = "dynamicpower <on|off>"
+	// IpmiLan switches IPMI over LAN of a blade on or off
+	IpmiLan = "ipmilan <on|off>"
+	// FlexAddress switches the chassis assigned MAC addresses of a blade on or off, the blade has to be powered off
+	FlexAddress = "flexaddress <on|off>"
+
+	// SolCapture records the serial console for the duration or until the optional regexp matches
+	SolCapture = "sol-capture <duration> [regexp]"
 )
+
+// Fixed lists the actions without arguments, executors are probed with them to report their capabilities
+var Fixed = []string{IsOn, PowerOn, PowerOff, PowerCycle, PowerCycleBmc, Reseat, PxeOnce, Screenshot, ScreenshotAnalyze, SelClear}
+
+// privileged lists the names of the actions which may only be requested by authorized clients
+var privileged = map[string]bool{SelClear: true, "updatefirmware": true}
+
+// IsPrivileged tells whether the action, whatever its arguments, may only be requested by authorized clients
+func IsPrivileged(action string) bool {
+	fields := strings.Fields(action)
+	return len(fields) > 0 && privileged[fields[0]]
+}
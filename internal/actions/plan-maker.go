@@ -1,15 +1,28 @@
 package actions
 
-import "fmt"
+import (
+	"fmt"
+	"time"
+)
 
 type (
 	Action struct {
 		value    string
 		executor Executor
+		workflow string
 	}
 
 	PlanMaker struct {
 		executorFactories []ExecutorFactory
+		targetType        string
+		workflows         Workflows
+		guard             Guard
+	}
+
+	// Guard admits the steps of plans on behalf of the identity which requested them, release is called
+	// with the outcome of an admitted step once it has run
+	Guard interface {
+		Admit(action, identity string) (release func(error), err error)
 	}
 
 	ActionResult struct {
@@ -17,6 +30,8 @@ type (
 		Status  bool
 		Message string
 		Error   error
+		// Workflow is the name of the workflow the action has been expanded from, if any
+		Workflow string
 	}
 
 	ExecutorFactory interface {
@@ -29,11 +44,66 @@ type (
 		Cleanup()
 	}
 
+	// CapabilitiesReporter is implemented by executors which are able to describe what they support
+	CapabilitiesReporter interface {
+		Capabilities() (Capabilities, error)
+	}
+
+	// Capabilities describes the actions supported for a target and the BMC behind it
+	Capabilities struct {
+		Actions         []string
+		Vendor          string
+		HardwareType    string
+		Provider        string
+		FirmwareVersion string
+	}
+
+	// ActionRecord is an ActionResult which can be serialized
+	ActionRecord struct {
+		Action   string `json:"action"`
+		Status   bool   `json:"status"`
+		Message  string `json:"message"`
+		Error    string `json:"error"`
+		Workflow string `json:"workflow,omitempty"`
+	}
+
 	ExecutionPlan struct {
 		actions    []Action
 		cleanupFns []func()
+		targetType string
+		params     map[string]interface{}
+		observers  []PlanObserver
+		stepFns    []StepObserver
+		guard      Guard
+		identity   string
+	}
+
+	// PlanReport describes a finished execution plan, successful or not
+	PlanReport struct {
+		TargetType string
+		Params     map[string]interface{}
+		Actions    []string
+		Results    []ActionResult
+		Error      error
+		Started    time.Time
+		Finished   time.Time
 	}
 
+	// PlanObserver is called once the plan has finished
+	PlanObserver func(PlanReport)
+
+	// StepEvent describes the progress of a plan, Result is nil when the step starts and set when it finishes
+	StepEvent struct {
+		Index    int
+		Total    int
+		Action   string
+		Workflow string
+		Result   *ActionResult
+	}
+
+	// StepObserver is called when every step of the plan starts and finishes
+	StepObserver func(StepEvent)
+
 	ActionFn func() ActionResult
 )
 
@@ -41,44 +111,155 @@ func NewPlanMaker(executorFactories ...ExecutorFactory) *PlanMaker {
 	return &PlanMaker{executorFactories: executorFactories}
 }
 
+// WithWorkflows makes the plan maker expand "workflow <name>" actions into the steps for the target type
+func (e *PlanMaker) WithWorkflows(targetType string, workflows Workflows) *PlanMaker {
+	e.targetType = targetType
+	e.workflows = workflows
+	return e
+}
+
+// WithGuard makes the plans of the plan maker run every step by the guard first
+func (e *PlanMaker) WithGuard(guard Guard) *PlanMaker {
+	e.guard = guard
+	return e
+}
+
 func (e *PlanMaker) MakePlan(actionsRaw []string, params map[string]interface{}) (*ExecutionPlan, error) {
+	actions := make([]Action, 0, len(actionsRaw))
+
+	for _, action := range actionsRaw {
+		workflow, steps, isWorkflow, err := e.workflows.expand(action, e.targetType)
+		if err != nil {
+			return nil, err
+		}
+
+		if !isWorkflow {
+			actions = append(actions, Action{value: action})
+			continue
+		}
+
+		for _, step := range steps {
+			actions = append(actions, Action{value: step, workflow: workflow})
+		}
+	}
+
+	executors, cleanupFns, err := e.makeExecutors(params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to make an execution plan: %w", err)
+	}
+
+	for i := range actions {
+		executor := findExecutor(actions[i].value, executors)
+		if executor == nil {
+			return nil, fmt.Errorf("action %q is unknown", actions[i].value)
+		}
+		actions[i].executor = executor
+	}
+
+	return &ExecutionPlan{actions: actions, cleanupFns: cleanupFns, targetType: e.targetType, params: params, guard: e.guard}, nil
+}
+
+// Capabilities collects the capabilities reported by every executor which is able to report them
+func (e *PlanMaker) Capabilities(params map[string]interface{}) (*Capabilities, error) {
+	executors, cleanupFns, err := e.makeExecutors(params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect capabilities: %w", err)
+	}
+
+	defer func() {
+		for _, cleanupFn := range cleanupFns {
+			cleanupFn()
+		}
+	}()
+
+	capabilities := &Capabilities{Actions: make([]string, 0)}
+
+	for _, executor := range executors {
+		reporter, ok := executor.(CapabilitiesReporter)
+		if !ok {
+			continue
+		}
+
+		executorCapabilities, err := reporter.Capabilities()
+		if err != nil {
+			return nil, fmt.Errorf("failed to collect capabilities: %w", err)
+		}
+
+		capabilities.merge(executorCapabilities)
+	}
+
+	return capabilities, nil
+}
+
+func (e *PlanMaker) makeExecutors(params map[string]interface{}) ([]Executor, []func(), error) {
 	executors := make([]Executor, 0)
 	cleanupFns := make([]func(), 0)
 
 	for _, executorFactory := range e.executorFactories {
 		executor, err := executorFactory.New(params)
 		if err != nil {
-			return nil, fmt.Errorf("failed to make an execution plan: %w", err)
+			return nil, nil, err
 		}
 		executors = append(executors, executor)
 		cleanupFns = append(cleanupFns, executor.Cleanup)
 	}
 
-	actions := make([]Action, len(actionsRaw))
+	return executors, cleanupFns, nil
+}
 
-	for i, action := range actionsRaw {
-		executor := findExecutor(action, executors)
-		if executor == nil {
-			return nil, fmt.Errorf("action %q is unknown", action)
-		}
-		actions[i] = Action{value: action, executor: executor}
-	}
+// OnFinish registers an observer to be called when the plan has finished
+func (p *ExecutionPlan) OnFinish(observer PlanObserver) {
+	p.observers = append(p.observers, observer)
+}
+
+// As sets the identity the plan runs on behalf of, e.g. the client of the request, for the guard
+func (p *ExecutionPlan) As(identity string) {
+	p.identity = identity
+}
 
-	return &ExecutionPlan{actions: actions, cleanupFns: cleanupFns}, nil
+// OnStep registers an observer to be called when every step starts and finishes
+func (p *ExecutionPlan) OnStep(observer StepObserver) {
+	p.stepFns = append(p.stepFns, observer)
 }
 
 func (p *ExecutionPlan) Run() ([]ActionResult, error) {
-	defer func() {
-		for _, cleanupFn := range p.cleanupFns {
-			cleanupFn()
+	defer p.Cleanup()
+
+	started := time.Now()
+	results, err := p.run()
+
+	if len(p.observers) > 0 {
+		report := PlanReport{
+			TargetType: p.targetType,
+			Params:     p.params,
+			Actions:    p.actionValues(),
+			Results:    results,
+			Error:      err,
+			Started:    started,
+			Finished:   time.Now(),
 		}
-	}()
+		for _, observer := range p.observers {
+			observer(report)
+		}
+	}
 
+	return results, err
+}
+
+func (p *ExecutionPlan) run() ([]ActionResult, error) {
 	results := make([]ActionResult, 0)
 
-	for _, action := range p.actions {
-		result := action.executor.Run(action.value)
+	for i, action := range p.actions {
+		event := StepEvent{Index: i, Total: len(p.actions), Action: action.value, Workflow: action.workflow}
+		p.notifyStep(event)
+
+		result := p.runStep(action)
+		result.Workflow = action.workflow
 		results = append(results, result)
+
+		event.Result = &result
+		p.notifyStep(event)
+
 		if result.Error != nil {
 			return results, result.Error
 		}
@@ -87,6 +268,54 @@ func (p *ExecutionPlan) Run() ([]ActionResult, error) {
 	return results, nil
 }
 
+// runStep runs the action once the guard, if any, admits it
+func (p *ExecutionPlan) runStep(action Action) ActionResult {
+	if p.guard == nil {
+		return action.executor.Run(action.value)
+	}
+
+	release, err := p.guard.Admit(action.value, p.identity)
+	if err != nil {
+		return NewActionResult(action.value, false, "not carried out", err)
+	}
+
+	result := action.executor.Run(action.value)
+	release(result.Error)
+
+	return result
+}
+
+func (p *ExecutionPlan) notifyStep(event StepEvent) {
+	for _, stepFn := range p.stepFns {
+		stepFn(event)
+	}
+}
+
+func (p *ExecutionPlan) actionValues() []string {
+	values := make([]string, len(p.actions))
+	for i, action := range p.actions {
+		values[i] = action.value
+	}
+	return values
+}
+
+// IsPrivileged tells whether any step of the plan, including the steps of workflows, is privileged
+func (p *ExecutionPlan) IsPrivileged() bool {
+	for _, action := range p.actions {
+		if IsPrivileged(action.value) {
+			return true
+		}
+	}
+	return false
+}
+
+// Cleanup releases the executors of the plan, it is called by Run and is only needed for plans which aren't run
+func (p *ExecutionPlan) Cleanup() {
+	for _, cleanupFn := range p.cleanupFns {
+		cleanupFn()
+	}
+}
+
 func NewActionResult(action string, status bool, message string, err error) ActionResult {
 	return ActionResult{
 		Action:  action,
@@ -96,6 +325,57 @@ func NewActionResult(action string, status bool, message string, err error) Acti
 	}
 }
 
+// NewActionRecords converts results to their serializable form
+func NewActionRecords(results []ActionResult) []ActionRecord {
+	records := make([]ActionRecord, 0, len(results))
+
+	for _, result := range results {
+		record := ActionRecord{
+			Action:   result.Action,
+			Status:   result.Status,
+			Message:  result.Message,
+			Workflow: result.Workflow,
+		}
+		if result.Error != nil {
+			record.Error = result.Error.Error()
+		}
+		records = append(records, record)
+	}
+
+	return records
+}
+
+// SupportedActions returns the fixed actions accepted by the validate function
+func SupportedActions(validate func(string) error) []string {
+	supported := make([]string, 0)
+
+	for _, action := range Fixed {
+		if err := validate(action); err == nil {
+			supported = append(supported, action)
+		}
+	}
+
+	return supported
+}
+
+func (c *Capabilities) merge(other Capabilities) {
+	c.Actions = append(c.Actions, other.Actions...)
+
+	for _, field := range []struct {
+		dst *string
+		src string
+	}{
+		{&c.Vendor, other.Vendor},
+		{&c.HardwareType, other.HardwareType},
+		{&c.Provider, other.Provider},
+		{&c.FirmwareVersion, other.FirmwareVersion},
+	} {
+		if *field.dst == "" {
+			*field.dst = field.src
+		}
+	}
+}
+
 func findExecutor(action string, executors []Executor) Executor {
 	for _, executor := range executors {
 		if err := executor.Validate(action); err == nil {
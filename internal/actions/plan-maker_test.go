@@ -2,6 +2,7 @@ package actions
 
 import (
 	"fmt"
+	"reflect"
 	"testing"
 )
 
@@ -122,3 +123,102 @@ func Test_findExecutor(t *testing.T) {
 		})
 	}
 }
+
+func TestSupportedActions(t *testing.T) {
+	type args struct {
+		validate func(string) error
+	}
+	tests := []struct {
+		name string
+		args args
+		want []string
+	}{
+		{
+			name: "Every action is valid",
+			args: args{validate: func(string) error { return nil }},
+			want: Fixed,
+		},
+		{
+			name: "Every action is invalid",
+			args: args{validate: func(string) error { return fmt.Errorf("action is not valid") }},
+			want: []string{},
+		},
+		{
+			name: "Power actions only",
+			args: args{validate: func(action string) error {
+				if action == PowerOn || action == PowerOff {
+					return nil
+				}
+				return fmt.Errorf("action is not valid")
+			}},
+			want: []string{PowerOn, PowerOff},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SupportedActions(tt.args.validate); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SupportedActions() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExecutionPlan_OnStep(t *testing.T) {
+	executor := &testExecutorEveryActionValid{testExecutor{actionResult: ActionResult{Message: "ok"}}}
+	plan := &ExecutionPlan{actions: []Action{{value: "action1", executor: executor}, {value: "action2", executor: executor}}}
+
+	events := make([]string, 0)
+	plan.OnStep(func(event StepEvent) {
+		state := "started"
+		if event.Result != nil {
+			state = "finished"
+		}
+		events = append(events, fmt.Sprintf("%d/%d %s %s", event.Index, event.Total, event.Action, state))
+	})
+
+	if _, err := plan.Run(); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	want := []string{"0/2 action1 started", "0/2 action1 finished", "1/2 action2 started", "1/2 action2 finished"}
+	if !reflect.DeepEqual(events, want) {
+		t.Errorf("OnStep() events = %v, want %v", events, want)
+	}
+}
+
+type testGuard struct {
+	admitted []string
+	released []string
+}
+
+func (g *testGuard) Admit(action, identity string) (func(error), error) {
+	if action == "action2" {
+		return nil, fmt.Errorf("%s may not run %s", identity, action)
+	}
+	g.admitted = append(g.admitted, identity+" "+action)
+	return func(err error) { g.released = append(g.released, action) }, nil
+}
+
+func TestExecutionPlan_Guard(t *testing.T) {
+	executor := &testExecutorEveryActionValid{testExecutor{actionResult: ActionResult{Message: "ok"}}}
+	guard := &testGuard{}
+	plan := &ExecutionPlan{
+		actions: []Action{{value: "action1", executor: executor}, {value: "action2", executor: executor}, {value: "action3", executor: executor}},
+		guard:   guard,
+	}
+	plan.As("alice")
+
+	results, err := plan.Run()
+	if err == nil {
+		t.Fatalf("Run() succeeded although the guard refused a step")
+	}
+	if len(results) != 2 || results[1].Action != "action2" || results[1].Error == nil {
+		t.Errorf("Run() = %+v, want action1 and the refused action2", results)
+	}
+	if want := []string{"alice action1"}; !reflect.DeepEqual(guard.admitted, want) {
+		t.Errorf("admitted = %v, want %v", guard.admitted, want)
+	}
+	if want := []string{"action1"}; !reflect.DeepEqual(guard.released, want) {
+		t.Errorf("released = %v, want %v", guard.released, want)
+	}
+}
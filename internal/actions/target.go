@@ -0,0 +1,91 @@
+package actions
+
+import "fmt"
+
+// names of the parameters executor factories are created with
+const (
+	ParamHost          = "host"
+	ParamBladePosition = "bladePos"
+	ParamBladeSerial   = "bladeSerial"
+)
+
+type (
+	// Target identifies a host, a chassis or a blade in a chassis (by position or by serial)
+	Target struct {
+		Type          string `json:"type"`
+		Host          string `json:"host"`
+		BladePosition *int   `json:"position,omitempty"`
+		BladeSerial   string `json:"serial,omitempty"`
+	}
+
+	// PlanMakers holds the plan maker of every target type
+	PlanMakers struct {
+		Host          *PlanMaker
+		Chassis       *PlanMaker
+		BladeByPos    *PlanMaker
+		BladeBySerial *PlanMaker
+	}
+)
+
+func (t Target) Validate() error {
+	if t.Host == "" {
+		return fmt.Errorf("invalid target: no host")
+	}
+
+	switch t.Type {
+	case TargetHost, TargetChassis:
+		if t.BladePosition != nil || t.BladeSerial != "" {
+			return fmt.Errorf("invalid target: blade position or serial given for %s", t.Type)
+		}
+	case TargetBlade:
+		if (t.BladePosition == nil) == (t.BladeSerial == "") {
+			return fmt.Errorf("invalid target: exactly one of blade position or serial is required")
+		}
+	default:
+		return fmt.Errorf("invalid target: unknown type %q", t.Type)
+	}
+
+	return nil
+}
+
+// Params returns the parameters executor factories are created with
+func (t Target) Params() map[string]interface{} {
+	params := map[string]interface{}{ParamHost: t.Host}
+
+	if t.BladePosition != nil {
+		params[ParamBladePosition] = *t.BladePosition
+	}
+	if t.BladeSerial != "" {
+		params[ParamBladeSerial] = t.BladeSerial
+	}
+
+	return params
+}
+
+func (t Target) String() string {
+	switch {
+	case t.BladePosition != nil:
+		return fmt.Sprintf("%s/%s/position/%d", t.Type, t.Host, *t.BladePosition)
+	case t.BladeSerial != "":
+		return fmt.Sprintf("%s/%s/serial/%s", t.Type, t.Host, t.BladeSerial)
+	}
+	return fmt.Sprintf("%s/%s", t.Type, t.Host)
+}
+
+// For returns the plan maker for the target
+func (p *PlanMakers) For(target Target) (*PlanMaker, error) {
+	if err := target.Validate(); err != nil {
+		return nil, err
+	}
+
+	switch {
+	case target.Type == TargetHost:
+		return p.Host, nil
+	case target.Type == TargetChassis:
+		return p.Chassis, nil
+	case target.BladePosition != nil:
+		return p.BladeByPos, nil
+	}
+
+	return p.BladeBySerial, nil
+}
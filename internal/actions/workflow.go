@@ -0,0 +1,143 @@
+package actions
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+const (
+	TargetHost    = "host"
+	TargetChassis = "chassis"
+	TargetBlade   = "blade"
+
+	workflowPrefix = "workflow"
+)
+
+type (
+	// Workflow is a named sequence of actions defined in the configuration
+	Workflow struct {
+		// Params maps parameter names to their default values, an empty default makes a parameter required
+		Params map[string]string `mapstructure:"params"`
+		// Steps are used for every target type without a variant
+		Steps []string `mapstructure:"steps"`
+		// Variants overrides Steps per target type (host, chassis, blade)
+		Variants map[string][]string `mapstructure:"variants"`
+	}
+
+	Workflows map[string]*Workflow
+)
+
+// WorkflowAction builds the action invoking the workflow with the given parameters
+func WorkflowAction(name string, params map[string]string) string {
+	keys := make([]string, 0, len(params))
+	for key := range params {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	tokens := []string{workflowPrefix, name}
+	for _, key := range keys {
+		tokens = append(tokens, fmt.Sprintf("%s=%s", key, params[key]))
+	}
+
+	return strings.Join(tokens, " ")
+}
+
+// Validate checks that workflows have steps and don't invoke other workflows
+func (w Workflows) Validate() error {
+	for name, workflow := range w {
+		if workflow == nil || (len(workflow.Steps) == 0 && len(workflow.Variants) == 0) {
+			return fmt.Errorf("workflow %q has no steps", name)
+		}
+
+		stepLists := [][]string{workflow.Steps}
+		for _, steps := range workflow.Variants {
+			stepLists = append(stepLists, steps)
+		}
+
+		for _, steps := range stepLists {
+			for _, step := range steps {
+				if isWorkflowAction(step) {
+					return fmt.Errorf("workflow %q invokes another workflow with %q", name, step)
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// expand turns a workflow action into the concrete steps for the target type, ok is false for other actions
+func (w Workflows) expand(action, targetType string) (name string, steps []string, ok bool, err error) {
+	if !isWorkflowAction(action) {
+		return "", nil, false, nil
+	}
+
+	name, args, err := parseWorkflowAction(action)
+	if err != nil {
+		return "", nil, true, err
+	}
+
+	workflow, exists := w[name]
+	if !exists {
+		return name, nil, true, fmt.Errorf("workflow %q is unknown", name)
+	}
+
+	for arg := range args {
+		if _, exists := workflow.Params[arg]; !exists {
+			return name, nil, true, fmt.Errorf("workflow %q has no parameter %q", name, arg)
+		}
+	}
+
+	replacements := make([]string, 0, 2*len(workflow.Params))
+	for param, defaultValue := range workflow.Params {
+		value, exists := args[param]
+		if !exists {
+			value = defaultValue
+		}
+		if value == "" {
+			return name, nil, true, fmt.Errorf("workflow %q requires parameter %q", name, param)
+		}
+		replacements = append(replacements, fmt.Sprintf("{%s}", param), value)
+	}
+	replacer := strings.NewReplacer(replacements...)
+
+	rawSteps := workflow.Steps
+	if variant, exists := workflow.Variants[targetType]; exists {
+		rawSteps = variant
+	}
+
+	if len(rawSteps) == 0 {
+		return name, nil, true, fmt.Errorf("workflow %q has no steps for %s", name, targetType)
+	}
+
+	steps = make([]string, len(rawSteps))
+	for i, step := range rawSteps {
+		steps[i] = replacer.Replace(step)
+	}
+
+	return name, steps, true, nil
+}
+
+func isWorkflowAction(action string) bool {
+	return strings.HasPrefix(action, workflowPrefix+" ")
+}
+
+func parseWorkflowAction(action string) (string, map[string]string, error) {
+	tokens := strings.Fields(action)
+	if len(tokens) < 2 {
+		return "", nil, fmt.Errorf("invalid workflow action %q: no workflow name", action)
+	}
+
+	args := make(map[string]string)
+	for _, token := range tokens[2:] {
+		kv := strings.SplitN(token, "=", 2)
+		if len(kv) != 2 || kv[0] == "" {
+			return "", nil, fmt.Errorf("invalid workflow action %q: %q is not a key=value parameter", action, token)
+		}
+		args[kv[0]] = kv[1]
+	}
+
+	return tokens[1], args, nil
+}
@@ -0,0 +1,168 @@
+package actions
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestWorkflows_expand(t *testing.T) {
+	workflows := Workflows{
+		"reprovision": {
+			Steps: []string{PxeOnce, PowerCycle},
+		},
+		"hard-reset": {
+			Params:   map[string]string{"delay": "10s"},
+			Steps:    []string{PowerOff, "sleep {delay}", PowerOn},
+			Variants: map[string][]string{TargetChassis: {PowerCycle}},
+		},
+		"wait": {
+			Params: map[string]string{"delay": ""},
+			Steps:  []string{"sleep {delay}"},
+		},
+	}
+
+	type args struct {
+		action     string
+		targetType string
+	}
+	tests := []struct {
+		name      string
+		args      args
+		wantName  string
+		wantSteps []string
+		wantOk    bool
+		wantErr   bool
+	}{
+		{
+			name:      "Not a workflow",
+			args:      args{action: PowerOn, targetType: TargetHost},
+			wantName:  "",
+			wantSteps: nil,
+			wantOk:    false,
+			wantErr:   false,
+		},
+		{
+			name:      "OK",
+			args:      args{action: "workflow reprovision", targetType: TargetHost},
+			wantName:  "reprovision",
+			wantSteps: []string{PxeOnce, PowerCycle},
+			wantOk:    true,
+			wantErr:   false,
+		},
+		{
+			name:      "OK default parameter",
+			args:      args{action: "workflow hard-reset", targetType: TargetHost},
+			wantName:  "hard-reset",
+			wantSteps: []string{PowerOff, "sleep 10s", PowerOn},
+			wantOk:    true,
+			wantErr:   false,
+		},
+		{
+			name:      "OK parameter",
+			args:      args{action: "workflow hard-reset delay=1m", targetType: TargetBlade},
+			wantName:  "hard-reset",
+			wantSteps: []string{PowerOff, "sleep 1m", PowerOn},
+			wantOk:    true,
+			wantErr:   false,
+		},
+		{
+			name:      "OK variant",
+			args:      args{action: "workflow hard-reset", targetType: TargetChassis},
+			wantName:  "hard-reset",
+			wantSteps: []string{PowerCycle},
+			wantOk:    true,
+			wantErr:   false,
+		},
+		{
+			name:      "Unknown workflow",
+			args:      args{action: "workflow unknown", targetType: TargetHost},
+			wantName:  "unknown",
+			wantSteps: nil,
+			wantOk:    true,
+			wantErr:   true,
+		},
+		{
+			name:      "Unknown parameter",
+			args:      args{action: "workflow reprovision delay=1s", targetType: TargetHost},
+			wantName:  "reprovision",
+			wantSteps: nil,
+			wantOk:    true,
+			wantErr:   true,
+		},
+		{
+			name:      "Required parameter is missed",
+			args:      args{action: "workflow wait", targetType: TargetHost},
+			wantName:  "wait",
+			wantSteps: nil,
+			wantOk:    true,
+			wantErr:   true,
+		},
+		{
+			name:      "Invalid parameter",
+			args:      args{action: "workflow wait delay", targetType: TargetHost},
+			wantName:  "",
+			wantSteps: nil,
+			wantOk:    true,
+			wantErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotName, gotSteps, gotOk, err := workflows.expand(tt.args.action, tt.args.targetType)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("expand() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if gotName != tt.wantName {
+				t.Errorf("expand() gotName = %v, want %v", gotName, tt.wantName)
+			}
+			if !reflect.DeepEqual(gotSteps, tt.wantSteps) {
+				t.Errorf("expand() gotSteps = %v, want %v", gotSteps, tt.wantSteps)
+			}
+			if gotOk != tt.wantOk {
+				t.Errorf("expand() gotOk = %v, want %v", gotOk, tt.wantOk)
+			}
+		})
+	}
+}
+
+func TestWorkflows_Validate(t *testing.T) {
+	tests := []struct {
+		name      string
+		workflows Workflows
+		wantErr   bool
+	}{
+		{
+			name:      "OK",
+			workflows: Workflows{"reprovision": {Steps: []string{PxeOnce, PowerCycle}}},
+			wantErr:   false,
+		},
+		{
+			name:      "OK variants only",
+			workflows: Workflows{"reprovision": {Variants: map[string][]string{TargetHost: {PxeOnce}}}},
+			wantErr:   false,
+		},
+		{
+			name:      "No steps",
+			workflows: Workflows{"reprovision": {}},
+			wantErr:   true,
+		},
+		{
+			name:      "Nested workflow",
+			workflows: Workflows{"reprovision": {Steps: []string{"workflow reprovision"}}},
+			wantErr:   true,
+		},
+		{
+			name:      "Nested workflow in a variant",
+			workflows: Workflows{"reprovision": {Steps: []string{PxeOnce}, Variants: map[string][]string{TargetBlade: {"workflow other"}}}},
+			wantErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.workflows.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -1,15 +1,28 @@
 package internal
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/bmc-toolbox/actor/internal/actions"
 	"github.com/bmc-toolbox/actor/internal/providers"
+	"github.com/bmc-toolbox/actor/internal/screenshot"
 )
 
 type (
 	baseBladeExecutor struct {
-		bmc bladeBmcProvider
+		bmc             bladeBmcProvider
+		username        string
+		passwords       passwordSource
+		screenshotStore screenshot.Store
+		analyzer        *screenshot.Analyzer
+		// newBladeBmc connects to the BMC of a blade, it is replaced in tests
+		newBladeBmc func(username, password, host string) bladeScreenshotProvider
+	}
+
+	bladeScreenshotProvider interface {
+		screenshotProvider
+		Close(context.Context) error
 	}
 
 	bladeBmcProvider interface {
@@ -24,20 +37,77 @@ type (
 
 		PxeOnceBlade(int) (bool, error)
 
+		SetIpmiOverLan(int, bool) (bool, error)
+		SetFlexAddressState(int, bool) (bool, error)
+
 		FindBladePosition(string) (int, error)
+		ForgetBlade(string)
+		BladeBmcAddress(int) (string, error)
+
+		// TempC and IsPsuRedundant report the chassis
+		TempC() (int, error)
+		IsPsuRedundant() (bool, error)
+
+		Info() (providers.BmcInfo, error)
 	}
 )
 
-func newBaseBladeExecutor(username, password, host string) *baseBladeExecutor {
-	return &baseBladeExecutor{bmc: providers.NewBladeBmcWrapper(username, password, host)}
+func newBaseBladeExecutor(
+	username string,
+	passwords passwordSource,
+	host string,
+	screenshotStore screenshot.Store,
+	analyzer *screenshot.Analyzer,
+	index *providers.BladeIndex,
+) *baseBladeExecutor {
+	return &baseBladeExecutor{
+		bmc:             providers.NewBladeBmcWrapper(username, passwords.Password(host), host).WithIndex(index),
+		username:        username,
+		passwords:       passwords,
+		screenshotStore: screenshotStore,
+		analyzer:        analyzer,
+		newBladeBmc: func(username, password, host string) bladeScreenshotProvider {
+			return providers.NewServerBmcWrapper(username, password, host)
+		},
+	}
 }
 
 func (e *baseBladeExecutor) Validate(action string) error {
+	if e.isScreenshotAction(action) {
+		return nil
+	}
+
+	if isRequireAction(action) {
+		return e.requirementProbes(0).validate(action)
+	}
+
 	_, err := e.matchActionToFn(action)
 	return err
 }
 
+func (e *baseBladeExecutor) Capabilities() (actions.Capabilities, error) {
+	info, err := e.bmc.Info()
+	if err != nil {
+		return actions.Capabilities{}, err
+	}
+
+	return newCapabilities(append(actions.SupportedActions(e.Validate), actions.IpmiLan, actions.FlexAddress, actions.Require), info), nil
+}
+
 func (e *baseBladeExecutor) matchActionToFn(action string) (func(int) (bool, error), error) {
+	if enable, ok, err := parseToggle(action, ipmiLanToggle); ok {
+		if err != nil {
+			return nil, err
+		}
+		return func(bladePos int) (bool, error) { return e.bmc.SetIpmiOverLan(bladePos, enable) }, nil
+	}
+	if enable, ok, err := parseToggle(action, flexAddressToggle); ok {
+		if err != nil {
+			return nil, err
+		}
+		return func(bladePos int) (bool, error) { return e.bmc.SetFlexAddressState(bladePos, enable) }, nil
+	}
+
 	switch action {
 	case actions.IsOn:
 		return e.bmc.IsOnBlade, nil
@@ -59,6 +129,14 @@ func (e *baseBladeExecutor) matchActionToFn(action string) (func(int) (bool, err
 }
 
 func (e *baseBladeExecutor) doAction(action string, bladePos int) actions.ActionResult {
+	if e.isScreenshotAction(action) {
+		return e.doScreenshot(action, bladePos)
+	}
+
+	if isRequireAction(action) {
+		return e.requirementProbes(bladePos).check(action)
+	}
+
 	fn, err := e.matchActionToFn(action)
 	if err != nil {
 		return actions.NewActionResult(action, false, "failed", err)
@@ -71,6 +149,48 @@ func (e *baseBladeExecutor) doAction(action string, bladePos int) actions.Action
 	return actions.NewActionResult(action, status, "ok", nil)
 }
 
+// requirementProbes check the power of the blade, the temperature and power supplies of the chassis
+func (e *baseBladeExecutor) requirementProbes(bladePos int) requirementProbes {
+	return requirementProbes{
+		isOn:           func() (bool, error) { return e.bmc.IsOnBlade(bladePos) },
+		tempC:          e.bmc.TempC,
+		isPsuRedundant: e.bmc.IsPsuRedundant,
+	}
+}
+
+// doScreenshot takes the screenshot through the BMC of the blade, the chassis only tells where it is
+func (e *baseBladeExecutor) doScreenshot(action string, bladePos int) actions.ActionResult {
+	address, err := e.bmc.BladeBmcAddress(bladePos)
+	if err != nil {
+		return actions.NewActionResult(action, false, "failed", err)
+	}
+
+	bladeBmc := e.newBladeBmc(e.username, e.passwords.Password(address), address)
+	defer func() { _ = bladeBmc.Close(context.TODO()) }()
+
+	var message string
+	if action == actions.ScreenshotAnalyze {
+		message, err = analyzeScreenshot(bladeBmc, address, e.screenshotStore, e.analyzer)
+	} else {
+		message, err = takeScreenshot(bladeBmc, address, e.screenshotStore)
+	}
+	if err != nil {
+		return actions.NewActionResult(action, false, "", err)
+	}
+
+	return actions.NewActionResult(action, true, message, nil)
+}
+
+func (e *baseBladeExecutor) isScreenshotAction(action string) bool {
+	switch action {
+	case actions.Screenshot:
+		return e.screenshotStore != nil
+	case actions.ScreenshotAnalyze:
+		return e.screenshotStore != nil && e.analyzer != nil
+	}
+	return false
+}
+
 func (e *baseBladeExecutor) Cleanup() {
 	if e.bmc != nil {
 		_ = e.bmc.Close()
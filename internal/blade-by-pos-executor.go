@@ -5,12 +5,17 @@ import (
 	"strconv"
 
 	"github.com/bmc-toolbox/actor/internal/actions"
+	"github.com/bmc-toolbox/actor/internal/providers"
+	"github.com/bmc-toolbox/actor/internal/screenshot"
 )
 
 type (
 	BladeByPosExecutorFactory struct {
-		username string
-		password string
+		username        string
+		passwords       passwordSource
+		screenshotStore screenshot.Store
+		analyzer        *screenshot.Analyzer
+		index           *providers.BladeIndex
 	}
 
 	BladeByPosExecutor struct {
@@ -19,22 +24,29 @@ type (
 	}
 )
 
-func NewBladeByPosExecutorFactory(username, password string) *BladeByPosExecutorFactory {
-	return &BladeByPosExecutorFactory{username: username, password: password}
+// NewBladeByPosExecutorFactory creates executors sharing the index of blade positions
+func NewBladeByPosExecutorFactory(
+	username string,
+	passwords passwordSource,
+	screenshotStore screenshot.Store,
+	analyzer *screenshot.Analyzer,
+	index *providers.BladeIndex,
+) *BladeByPosExecutorFactory {
+	return &BladeByPosExecutorFactory{username: username, passwords: passwords, screenshotStore: screenshotStore, analyzer: analyzer, index: index}
 }
 
 func (f *BladeByPosExecutorFactory) New(params map[string]interface{}) (actions.Executor, error) {
-	if err := validateParam(params, paramHost, paramBladePosition); err != nil {
+	if err := validateParam(params, actions.ParamHost, actions.ParamBladePosition); err != nil {
 		return nil, fmt.Errorf("failed to validate params: %w", err)
 	}
 
-	bladePosStr := fmt.Sprintf("%v", params[paramBladePosition])
+	bladePosStr := fmt.Sprintf("%v", params[actions.ParamBladePosition])
 	bladePos, err := strconv.Atoi(bladePosStr)
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse parameter %s from %q: %w", paramBladePosition, bladePosStr, err)
+		return nil, fmt.Errorf("failed to parse parameter %s from %q: %w", actions.ParamBladePosition, bladePosStr, err)
 	}
 
-	baseExecutor := newBaseBladeExecutor(f.username, f.password, fmt.Sprintf("%v", params[paramHost]))
+	baseExecutor := newBaseBladeExecutor(f.username, f.passwords, fmt.Sprintf("%v", params[actions.ParamHost]), f.screenshotStore, f.analyzer, f.index)
 
 	return &BladeByPosExecutor{baseBladeExecutor: baseExecutor, bladePos: bladePos}, nil
 }
@@ -4,12 +4,17 @@ import (
 	"fmt"
 
 	"github.com/bmc-toolbox/actor/internal/actions"
+	"github.com/bmc-toolbox/actor/internal/providers"
+	"github.com/bmc-toolbox/actor/internal/screenshot"
 )
 
 type (
 	BladeBySerialExecutorFactory struct {
-		username string
-		password string
+		username        string
+		passwords       passwordSource
+		screenshotStore screenshot.Store
+		analyzer        *screenshot.Analyzer
+		index           *providers.BladeIndex
 	}
 
 	BladeBySerialExecutor struct {
@@ -18,17 +23,24 @@ type (
 	}
 )
 
-func NewBladeBySerialExecutorFactory(username, password string) *BladeBySerialExecutorFactory {
-	return &BladeBySerialExecutorFactory{username: username, password: password}
+// NewBladeBySerialExecutorFactory creates executors sharing the index of blade positions
+func NewBladeBySerialExecutorFactory(
+	username string,
+	passwords passwordSource,
+	screenshotStore screenshot.Store,
+	analyzer *screenshot.Analyzer,
+	index *providers.BladeIndex,
+) *BladeBySerialExecutorFactory {
+	return &BladeBySerialExecutorFactory{username: username, passwords: passwords, screenshotStore: screenshotStore, analyzer: analyzer, index: index}
 }
 
 func (f *BladeBySerialExecutorFactory) New(params map[string]interface{}) (actions.Executor, error) {
-	if err := validateParam(params, paramHost, paramBladeSerial); err != nil {
+	if err := validateParam(params, actions.ParamHost, actions.ParamBladeSerial); err != nil {
 		return nil, fmt.Errorf("failed to validate params: %w", err)
 	}
 
-	baseExecutor := newBaseBladeExecutor(f.username, f.password, fmt.Sprintf("%v", params[paramHost]))
-	bladeSerial := fmt.Sprintf("%v", params[paramBladeSerial])
+	baseExecutor := newBaseBladeExecutor(f.username, f.passwords, fmt.Sprintf("%v", params[actions.ParamHost]), f.screenshotStore, f.analyzer, f.index)
+	bladeSerial := fmt.Sprintf("%v", params[actions.ParamBladeSerial])
 
 	return &BladeBySerialExecutor{baseBladeExecutor: baseExecutor, bladeSerial: bladeSerial}, nil
 }
@@ -39,5 +51,10 @@ func (e *BladeBySerialExecutor) Run(action string) actions.ActionResult {
 		return actions.NewActionResult(action, false, "failed", err)
 	}
 
-	return e.doAction(action, bladePos)
+	result := e.doAction(action, bladePos)
+	if result.Error != nil {
+		// the blade may have moved since its position was indexed, the next request asks the chassis again
+		e.bmc.ForgetBlade(e.bladeSerial)
+	}
+	return result
 }
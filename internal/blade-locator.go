@@ -0,0 +1,76 @@
+package internal
+
+import (
+	"sync"
+
+	"github.com/bmc-toolbox/actor/internal/providers"
+	log "github.com/sirupsen/logrus"
+)
+
+// BladeLocator finds blades by serial across the known chassis and the chassis seen by requests,
+// chassis is called on every scan so chassis registered meanwhile are scanned as well
+type BladeLocator struct {
+	username    string
+	passwords   passwordSource
+	index       *providers.BladeIndex
+	chassis     func() []string
+	concurrency int
+}
+
+func NewBladeLocator(username string, passwords passwordSource, index *providers.BladeIndex, chassis func() []string, concurrency int) *BladeLocator {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	return &BladeLocator{username: username, passwords: passwords, index: index, chassis: chassis, concurrency: concurrency}
+}
+
+// Locate returns where the blade is, known chassis are only scanned when the index doesn't know the blade or refresh is set
+func (l *BladeLocator) Locate(serial string, refresh bool) (providers.BladeLocation, error) {
+	if !refresh {
+		if location, ok := l.index.Lookup(serial); ok {
+			return location, nil
+		}
+	}
+
+	l.scan()
+
+	if location, ok := l.index.Lookup(serial); ok {
+		return location, nil
+	}
+	return providers.BladeLocation{}, providers.ErrBladeNotFound
+}
+
+// scan lists the blades of every known chassis, a chassis which can't be reached keeps its indexed blades
+func (l *BladeLocator) scan() {
+	seen := make(map[string]bool)
+	var chassis []string
+	for _, host := range append(l.chassis(), l.index.Chassis()...) {
+		if !seen[host] {
+			seen[host] = true
+			chassis = append(chassis, host)
+		}
+	}
+
+	sem := make(chan struct{}, l.concurrency)
+	var wg sync.WaitGroup
+
+	for _, host := range chassis {
+		wg.Add(1)
+		sem <- struct{}{}
+
+		go func(host string) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+
+			bmc := providers.NewBladeBmcWrapper(l.username, l.passwords.Password(host), host).WithIndex(l.index)
+			defer func() { _ = bmc.Close() }()
+
+			if _, err := bmc.ScanBlades(); err != nil {
+				log.WithField("ip", host).WithError(err).Warn("failed to scan blades")
+			}
+		}(host)
+	}
+	wg.Wait()
+}
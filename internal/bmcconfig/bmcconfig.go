@@ -0,0 +1,380 @@
+package bmcconfig
+
+import (
+	"crypto/x509"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/bmc-toolbox/bmclib/cfgresources"
+	"github.com/bmc-toolbox/bmclib/devices"
+)
+
+const (
+	// StatusApplied and the statuses of resources applied to a BMC
+	StatusApplied     = "applied"
+	StatusFailed      = "failed"
+	StatusUnsupported = "unsupported"
+
+	// StatusInSync and the statuses of resources compared with a BMC, bmclib can't read back most resources
+	// so their drift is unknown
+	StatusInSync  = "in-sync"
+	StatusDrift   = "drift"
+	StatusUnknown = "unknown"
+)
+
+type (
+	// Result is the outcome of applying or comparing a resource
+	Result struct {
+		Resource string `json:"resource"`
+		Status   string `json:"status"`
+		Message  string `json:"message,omitempty"`
+	}
+
+	// Secrets resolves the passwords of users, documents reference passwords in the credential store
+	// instead of carrying them
+	Secrets interface {
+		Password(ref string) (string, error)
+	}
+
+	// Applier applies ResourcesConfig documents one resource at a time, a failed resource doesn't stop the others,
+	// username is the user actor connects with
+	Applier struct {
+		secrets  Secrets
+		username string
+		now      func() time.Time
+	}
+
+	// resource is a configuration resource set in a document, setup resources are applied with devices.CmcSetup
+	resource struct {
+		name  string
+		setup bool
+		apply func() (string, error)
+		diff  func() Result
+	}
+)
+
+func NewApplier(secrets Secrets, username string) *Applier {
+	return &Applier{secrets: secrets, username: username, now: time.Now}
+}
+
+// Apply applies the resources set in cfg, setup is nil for BMCs which aren't chassis
+func (a *Applier) Apply(bmc devices.Configure, setup devices.CmcSetup, cfg *cfgresources.ResourcesConfig) []Result {
+	resources := a.resources(bmc, setup, cfg)
+	results := make([]Result, 0, len(resources))
+
+	for _, r := range resources {
+		if reason := supported(bmc, setup, r); reason != "" {
+			results = append(results, Result{Resource: r.name, Status: StatusUnsupported, Message: reason})
+			continue
+		}
+
+		if r.apply == nil {
+			results = append(results, Result{Resource: r.name, Status: StatusUnsupported, Message: "actor can't apply this resource"})
+			continue
+		}
+
+		message, err := r.apply()
+		if err != nil {
+			results = append(results, Result{Resource: r.name, Status: StatusFailed, Message: err.Error()})
+			continue
+		}
+		results = append(results, Result{Resource: r.name, Status: StatusApplied, Message: message})
+	}
+
+	return results
+}
+
+// Diff compares the resources set in cfg with the BMC without changing it
+func (a *Applier) Diff(bmc devices.Configure, setup devices.CmcSetup, cfg *cfgresources.ResourcesConfig) []Result {
+	resources := a.resources(bmc, setup, cfg)
+	results := make([]Result, 0, len(resources))
+
+	for _, r := range resources {
+		if reason := supported(bmc, setup, r); reason != "" {
+			results = append(results, Result{Resource: r.name, Status: StatusUnsupported, Message: reason})
+			continue
+		}
+
+		if r.diff == nil {
+			results = append(results, Result{Resource: r.name, Status: StatusUnknown, Message: "the current configuration can't be read from the BMC"})
+			continue
+		}
+		results = append(results, r.diff())
+	}
+
+	return results
+}
+
+// supported returns why the BMC doesn't support the resource, or an empty string when it does
+func supported(bmc devices.Configure, setup devices.CmcSetup, r resource) string {
+	if !r.setup {
+		if !contains(bmc.Resources(), r.name) {
+			return "the BMC does not support this resource"
+		}
+		return ""
+	}
+
+	if setup == nil {
+		return "only chassis support this resource"
+	}
+	if !contains(setup.ResourcesSetup(), r.name) {
+		return "the chassis does not support this resource"
+	}
+	return ""
+}
+
+// resources lists the resources set in cfg in the order bmclib applies them
+func (a *Applier) resources(bmc devices.Configure, setup devices.CmcSetup, cfg *cfgresources.ResourcesConfig) []resource {
+	var resources []resource
+
+	if cfg.User != nil {
+		resources = append(resources, resource{name: "user", apply: func() (string, error) {
+			users, err := a.users(cfg.User)
+			if err != nil {
+				return "", err
+			}
+			return "", bmc.User(users)
+		}})
+	}
+	if cfg.Syslog != nil {
+		resources = append(resources, resource{name: "syslog", apply: func() (string, error) {
+			return "", bmc.Syslog(cfg.Syslog)
+		}})
+	}
+	if cfg.Network != nil {
+		resources = append(resources, resource{name: "network", apply: func() (string, error) {
+			reset, err := bmc.Network(cfg.Network)
+			if err == nil && reset {
+				return "the BMC resets to apply the network configuration", nil
+			}
+			return "", err
+		}})
+	}
+	if cfg.Ntp != nil {
+		resources = append(resources, resource{name: "ntp", apply: func() (string, error) {
+			return "", bmc.Ntp(cfg.Ntp)
+		}})
+	}
+	if cfg.Ldap != nil {
+		resources = append(resources, resource{name: "ldap", apply: func() (string, error) {
+			return "", bmc.Ldap(cfg.Ldap)
+		}})
+	}
+	if cfg.LdapGroups != nil {
+		resources = append(resources, resource{name: "ldap_group", apply: func() (string, error) {
+			return "", bmc.LdapGroups(cfg.LdapGroups.Groups, cfg.Ldap)
+		}})
+	}
+	if cfg.License != nil {
+		resources = append(resources, resource{name: "license", apply: func() (string, error) {
+			return "", bmc.SetLicense(cfg.License)
+		}})
+	}
+	if cfg.Bios != nil {
+		resources = append(resources, resource{name: "bios", apply: func() (string, error) {
+			return "", bmc.Bios(cfg.Bios)
+		}})
+	}
+	if cfg.Power != nil {
+		resources = append(resources, resource{name: "power", apply: func() (string, error) {
+			return "", bmc.Power(cfg.Power)
+		}})
+	}
+	if cfg.HTTPSCert != nil {
+		// certificates have to be signed before they are uploaded, so they are only compared
+		resources = append(resources, resource{name: "https_cert", diff: func() Result {
+			return a.diffHTTPSCert(bmc, cfg.HTTPSCert)
+		}})
+	}
+	if cfg.Supermicro != nil {
+		resources = append(resources, resource{name: "supermicro"})
+	}
+
+	if cfg.SetupChassis != nil {
+		resources = append(resources, a.setupResources(setup, cfg.SetupChassis)...)
+	}
+
+	return resources
+}
+
+func (a *Applier) setupResources(setup devices.CmcSetup, cfg *cfgresources.SetupChassis) []resource {
+	var resources []resource
+
+	if cfg.DynamicPower != nil {
+		resources = append(resources, resource{name: "dynamicpower", setup: true, apply: func() (string, error) {
+			_, err := setup.SetDynamicPower(cfg.DynamicPower.Enable)
+			return "", err
+		}})
+	}
+	if cfg.AddBladeBmcAdmins != nil {
+		resources = append(resources, resource{name: "add_blade_bmc_admins", setup: true, apply: func() (string, error) {
+			for _, account := range cfg.AddBladeBmcAdmins {
+				password, err := a.password(account.Name, account.Password)
+				if err != nil {
+					return "", err
+				}
+				if err := setup.AddBladeBmcAdmin(account.Name, password); err != nil {
+					return "", fmt.Errorf("failed to add %s: %w", account.Name, err)
+				}
+			}
+			return "", nil
+		}})
+	}
+	if cfg.RemoveBladeBmcUsers != nil {
+		resources = append(resources, resource{name: "remove_blade_bmc_users", setup: true, apply: func() (string, error) {
+			for _, account := range cfg.RemoveBladeBmcUsers {
+				if account.Name == a.username {
+					return "", fmt.Errorf("refusing to remove %s, the user actor connects with", account.Name)
+				}
+			}
+			for _, account := range cfg.RemoveBladeBmcUsers {
+				if err := setup.RemoveBladeBmcUser(account.Name); err != nil {
+					return "", fmt.Errorf("failed to remove %s: %w", account.Name, err)
+				}
+			}
+			return "", nil
+		}})
+	}
+	// these settings are set blade by blade, which bmclib doesn't do from the chassis setup
+	if cfg.FlexAddress != nil {
+		resources = append(resources, resource{name: "flexaddress", setup: true})
+	}
+	if cfg.IpmiOverLan != nil {
+		resources = append(resources, resource{name: "setipmioverlan", setup: true})
+	}
+	if cfg.BladesPower != nil {
+		resources = append(resources, resource{name: "bladespower", setup: true})
+	}
+
+	return resources
+}
+
+// users resolves the passwords of the users, the list must keep the user actor connects with as an enabled admin:
+// some BMCs (e.g. iDRAC9) delete the accounts which aren't listed, which would lock actor out
+func (a *Applier) users(cfgUsers []*cfgresources.User) ([]*cfgresources.User, error) {
+	if !keepsUser(cfgUsers, a.username) {
+		return nil, fmt.Errorf("refusing to apply users without %s, the user actor connects with, as an enabled admin", a.username)
+	}
+
+	users := make([]*cfgresources.User, 0, len(cfgUsers))
+	for _, cfgUser := range cfgUsers {
+		user := *cfgUser
+
+		password, err := a.password(user.Name, user.Password)
+		if err != nil {
+			return nil, err
+		}
+		user.Password = password
+
+		users = append(users, &user)
+	}
+	return users, nil
+}
+
+func keepsUser(users []*cfgresources.User, name string) bool {
+	for _, user := range users {
+		if user.Name == name {
+			return user.Enable && user.Role == "admin"
+		}
+	}
+	return false
+}
+
+func (a *Applier) password(name, ref string) (string, error) {
+	if ref == "" {
+		return "", fmt.Errorf("user %s has no password reference", name)
+	}
+
+	password, err := a.secrets.Password(ref)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve the password of user %s: %w", name, err)
+	}
+	return password, nil
+}
+
+// diffHTTPSCert reports drift when the current certificate expires within RenewBeforeExpiry
+// or when one of the ValidateAttributes doesn't match the certificate
+func (a *Applier) diffHTTPSCert(bmc devices.Configure, cfg *cfgresources.HTTPSCert) Result {
+	result := Result{Resource: "https_cert"}
+
+	certs, _, err := bmc.CurrentHTTPSCert()
+	if err != nil {
+		result.Status, result.Message = StatusFailed, err.Error()
+		return result
+	}
+	if len(certs) == 0 {
+		result.Status, result.Message = StatusDrift, "the BMC has no certificate"
+		return result
+	}
+
+	cert := certs[0]
+	var drift []string
+
+	if cfg.RenewBeforeExpiry > 0 && a.now().Add(cfg.RenewBeforeExpiry).After(cert.NotAfter) {
+		drift = append(drift, fmt.Sprintf("the certificate expires at %s", cert.NotAfter.UTC().Format(time.RFC3339)))
+	}
+
+	for _, attribute := range cfg.ValidateAttributes {
+		expected, actual, err := certAttribute(cfg.Attributes, cert, attribute)
+		if err != nil {
+			drift = append(drift, err.Error())
+			continue
+		}
+		if expected != actual {
+			drift = append(drift, fmt.Sprintf("%s is %q instead of %q", attribute, actual, expected))
+		}
+	}
+
+	if len(drift) == 0 {
+		result.Status = StatusInSync
+		return result
+	}
+
+	result.Status, result.Message = StatusDrift, strings.Join(drift, ", ")
+	return result
+}
+
+// certAttribute returns the expected and the actual value of an attribute named like in the documents
+func certAttribute(attributes *cfgresources.HTTPSCertAttributes, cert *x509.Certificate, name string) (string, string, error) {
+	if attributes == nil {
+		return "", "", fmt.Errorf("no attributes to validate %s", name)
+	}
+
+	first := func(values []string) string {
+		if len(values) == 0 {
+			return ""
+		}
+		return values[0]
+	}
+
+	switch name {
+	case "commonName":
+		return attributes.CommonName, cert.Subject.CommonName, nil
+	case "organizationName":
+		return attributes.OrganizationName, first(cert.Subject.Organization), nil
+	case "organizationUnit":
+		return attributes.OrganizationUnit, first(cert.Subject.OrganizationalUnit), nil
+	case "locality":
+		return attributes.Locality, first(cert.Subject.Locality), nil
+	case "stateName":
+		return attributes.StateName, first(cert.Subject.Province), nil
+	case "countryCode":
+		return attributes.CountryCode, first(cert.Subject.Country), nil
+	case "email":
+		return attributes.Email, first(cert.EmailAddresses), nil
+	case "subjectAltName":
+		return attributes.SubjectAltName, first(cert.DNSNames), nil
+	}
+
+	return "", "", fmt.Errorf("unknown attribute %s", name)
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,248 @@
+package bmcconfig
+
+import (
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/bmc-toolbox/bmclib/cfgresources"
+	"github.com/bmc-toolbox/bmclib/devices"
+)
+
+type testBmc struct {
+	devices.Configure
+	resources []string
+	users     []*cfgresources.User
+	syslog    error
+	cert      *x509.Certificate
+}
+
+func (b *testBmc) Resources() []string {
+	return b.resources
+}
+
+func (b *testBmc) User(users []*cfgresources.User) error {
+	b.users = users
+	return nil
+}
+
+func (b *testBmc) Syslog(*cfgresources.Syslog) error {
+	return b.syslog
+}
+
+func (b *testBmc) Network(*cfgresources.Network) (bool, error) {
+	return true, nil
+}
+
+func (b *testBmc) CurrentHTTPSCert() ([]*x509.Certificate, bool, error) {
+	return []*x509.Certificate{b.cert}, false, nil
+}
+
+type testSetup struct {
+	devices.CmcSetup
+	removed []string
+}
+
+func (s *testSetup) ResourcesSetup() []string {
+	return []string{"remove_blade_bmc_users"}
+}
+
+func (s *testSetup) RemoveBladeBmcUser(username string) error {
+	s.removed = append(s.removed, username)
+	return nil
+}
+
+type testSecrets map[string]string
+
+func (s testSecrets) Password(ref string) (string, error) {
+	if password, ok := s[ref]; ok {
+		return password, nil
+	}
+	return "", fmt.Errorf("credential not found: %s", ref)
+}
+
+func TestApplier_Apply(t *testing.T) {
+	bmc := &testBmc{resources: []string{"user", "syslog", "network"}, syslog: errors.New("timeout")}
+	cfg := &cfgresources.ResourcesConfig{
+		User: []*cfgresources.User{
+			{Name: "actor", Password: "bmc/actor", Role: "admin", Enable: true},
+			{Name: "ops", Password: "bmc/ops", Role: "admin"},
+		},
+		Syslog:  &cfgresources.Syslog{Server: "syslog"},
+		Network: &cfgresources.Network{},
+		Ntp:     &cfgresources.Ntp{},
+		SetupChassis: &cfgresources.SetupChassis{
+			AddBladeBmcAdmins: []*cfgresources.BladeBmcAccount{{Name: "ops", Password: "bmc/ops"}},
+		},
+	}
+
+	results := NewApplier(testSecrets{"bmc/ops": "secret", "bmc/actor": "actor"}, "actor").Apply(bmc, nil, cfg)
+
+	want := []Result{
+		{Resource: "user", Status: StatusApplied},
+		{Resource: "syslog", Status: StatusFailed, Message: "timeout"},
+		{Resource: "network", Status: StatusApplied, Message: "the BMC resets to apply the network configuration"},
+		{Resource: "ntp", Status: StatusUnsupported, Message: "the BMC does not support this resource"},
+		{Resource: "add_blade_bmc_admins", Status: StatusUnsupported, Message: "only chassis support this resource"},
+	}
+	if !reflect.DeepEqual(results, want) {
+		t.Errorf("Apply() = %+v, want %+v", results, want)
+	}
+
+	if len(bmc.users) != 2 || bmc.users[1].Password != "secret" {
+		t.Errorf("Apply() users = %+v, want the resolved password", bmc.users)
+	}
+	if cfg.User[1].Password != "bmc/ops" {
+		t.Errorf("Apply() changed the document password to %q", cfg.User[1].Password)
+	}
+}
+
+func TestApplier_ApplyUnknownPassword(t *testing.T) {
+	bmc := &testBmc{resources: []string{"user"}}
+	cfg := &cfgresources.ResourcesConfig{User: []*cfgresources.User{
+		{Name: "actor", Password: "bmc/actor", Role: "admin", Enable: true},
+		{Name: "ops", Password: "bmc/missing"},
+	}}
+
+	results := NewApplier(testSecrets{}, "actor").Apply(bmc, nil, cfg)
+	if len(results) != 1 || results[0].Status != StatusFailed {
+		t.Fatalf("Apply() = %+v, want a failed user resource", results)
+	}
+	if bmc.users != nil {
+		t.Errorf("Apply() applied users %+v", bmc.users)
+	}
+}
+
+func TestApplier_ApplyUsersWithoutActor(t *testing.T) {
+	tests := []struct {
+		name  string
+		users []*cfgresources.User
+	}{
+		{name: "Missing", users: []*cfgresources.User{{Name: "ops", Password: "bmc/ops", Role: "admin", Enable: true}}},
+		{name: "Disabled", users: []*cfgresources.User{{Name: "actor", Password: "bmc/ops", Role: "admin"}}},
+		{name: "Not an admin", users: []*cfgresources.User{{Name: "actor", Password: "bmc/ops", Role: "user", Enable: true}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bmc := &testBmc{resources: []string{"user"}}
+			cfg := &cfgresources.ResourcesConfig{User: tt.users}
+
+			results := NewApplier(testSecrets{"bmc/ops": "secret"}, "actor").Apply(bmc, nil, cfg)
+			if len(results) != 1 || results[0].Status != StatusFailed {
+				t.Fatalf("Apply() = %+v, want a failed user resource", results)
+			}
+			if bmc.users != nil {
+				t.Errorf("Apply() applied users %+v, which would delete the user of actor", bmc.users)
+			}
+		})
+	}
+}
+
+func TestApplier_ApplyRemoveBladeUsers(t *testing.T) {
+	tests := []struct {
+		name        string
+		users       []string
+		wantStatus  string
+		wantRemoved []string
+	}{
+		{name: "Remove", users: []string{"ops", "former"}, wantStatus: StatusApplied, wantRemoved: []string{"ops", "former"}},
+		{name: "Actor", users: []string{"ops", "actor"}, wantStatus: StatusFailed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setup := &testSetup{}
+			accounts := make([]*cfgresources.BladeBmcAccount, 0, len(tt.users))
+			for _, user := range tt.users {
+				accounts = append(accounts, &cfgresources.BladeBmcAccount{Name: user})
+			}
+			cfg := &cfgresources.ResourcesConfig{SetupChassis: &cfgresources.SetupChassis{RemoveBladeBmcUsers: accounts}}
+
+			results := NewApplier(testSecrets{}, "actor").Apply(&testBmc{}, setup, cfg)
+			if len(results) != 1 || results[0].Status != tt.wantStatus {
+				t.Fatalf("Apply() = %+v, want a %s remove_blade_bmc_users resource", results, tt.wantStatus)
+			}
+			if !reflect.DeepEqual(setup.removed, tt.wantRemoved) {
+				t.Errorf("Apply() removed %v, want %v", setup.removed, tt.wantRemoved)
+			}
+		})
+	}
+}
+
+func TestApplier_Diff(t *testing.T) {
+	now := time.Date(2021, 6, 1, 0, 0, 0, 0, time.UTC)
+	cert := &x509.Certificate{
+		Subject:  pkix.Name{CommonName: "bmc.example.com", Organization: []string{"Example"}},
+		NotAfter: now.Add(10 * 24 * time.Hour),
+	}
+
+	tests := []struct {
+		name string
+		cfg  *cfgresources.HTTPSCert
+		want Result
+	}{
+		{
+			name: "In sync",
+			cfg: &cfgresources.HTTPSCert{
+				RenewBeforeExpiry:  24 * time.Hour,
+				ValidateAttributes: []string{"commonName"},
+				Attributes:         &cfgresources.HTTPSCertAttributes{CommonName: "bmc.example.com"},
+			},
+			want: Result{Resource: "https_cert", Status: StatusInSync},
+		},
+		{
+			name: "Expiring",
+			cfg:  &cfgresources.HTTPSCert{RenewBeforeExpiry: 30 * 24 * time.Hour},
+			want: Result{Resource: "https_cert", Status: StatusDrift, Message: "the certificate expires at 2021-06-11T00:00:00Z"},
+		},
+		{
+			name: "Other attribute",
+			cfg: &cfgresources.HTTPSCert{
+				ValidateAttributes: []string{"organizationName"},
+				Attributes:         &cfgresources.HTTPSCertAttributes{OrganizationName: "Other"},
+			},
+			want: Result{Resource: "https_cert", Status: StatusDrift, Message: `organizationName is "Example" instead of "Other"`},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bmc := &testBmc{resources: []string{"syslog", "https_cert"}, cert: cert}
+			applier := NewApplier(testSecrets{}, "actor")
+			applier.now = func() time.Time { return now }
+
+			results := applier.Diff(bmc, nil, &cfgresources.ResourcesConfig{Syslog: &cfgresources.Syslog{}, HTTPSCert: tt.cfg})
+
+			want := []Result{
+				{Resource: "syslog", Status: StatusUnknown, Message: "the current configuration can't be read from the BMC"},
+				tt.want,
+			}
+			if !reflect.DeepEqual(results, want) {
+				t.Errorf("Diff() = %+v, want %+v", results, want)
+			}
+		})
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		wantErr bool
+	}{
+		{name: "YAML", data: "ntp:\n  enable: true\n  server1: ntp.example.com\n"},
+		{name: "JSON", data: `{"syslog": {"server": "syslog.example.com", "port": 514}}`},
+		{name: "Unknown key", data: `{"sylog": {"server": "syslog.example.com"}}`, wantErr: true},
+		{name: "Empty", data: `{}`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse([]byte(tt.data))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Parse() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,68 @@
+package bmcconfig
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+
+	"github.com/bmc-toolbox/bmclib/cfgresources"
+	"gopkg.in/yaml.v2"
+)
+
+var (
+	// ErrProfileNotFound is returned for unknown profiles
+	ErrProfileNotFound = errors.New("configuration profile not found")
+
+	validProfile = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+)
+
+// Profiles reads named configuration documents from dir, the profile "ntp" is the document ntp.yaml
+type Profiles struct {
+	dir string
+}
+
+func NewProfiles(dir string) *Profiles {
+	return &Profiles{dir: dir}
+}
+
+func (p *Profiles) Profile(name string) (*cfgresources.ResourcesConfig, error) {
+	if p.dir == "" {
+		return nil, fmt.Errorf("%w: no profile directory is configured", ErrProfileNotFound)
+	}
+	if !validProfile.MatchString(name) {
+		return nil, fmt.Errorf("invalid profile name %q", name)
+	}
+
+	data, err := ioutil.ReadFile(filepath.Join(p.dir, name+".yaml"))
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("%w: %s", ErrProfileNotFound, name)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	cfg, err := Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid profile %s: %w", name, err)
+	}
+	return cfg, nil
+}
+
+// Parse reads a ResourcesConfig document, bmclib only declares YAML keys and JSON documents are valid YAML,
+// unknown keys are refused so typos don't go unnoticed
+func Parse(data []byte) (*cfgresources.ResourcesConfig, error) {
+	cfg := &cfgresources.ResourcesConfig{}
+	if err := yaml.UnmarshalStrict(data, cfg); err != nil {
+		return nil, err
+	}
+
+	empty := cfgresources.ResourcesConfig{}
+	if reflect.DeepEqual(*cfg, empty) {
+		return nil, fmt.Errorf("the document sets no resource")
+	}
+	return cfg, nil
+}
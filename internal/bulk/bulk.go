@@ -0,0 +1,115 @@
+package bulk
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/bmc-toolbox/actor/internal/actions"
+)
+
+type (
+	// Job carries out an action sequence on many targets, the plans of all targets are made up front
+	// so a sequence which doesn't fit one of them is refused before anything runs
+	Job struct {
+		targets []actions.Target
+		plans   []*actions.ExecutionPlan
+	}
+
+	// Result is the outcome of the action sequence on one target
+	Result struct {
+		Target  actions.Target         `json:"target"`
+		Status  bool                   `json:"status"`
+		Error   string                 `json:"error,omitempty"`
+		Results []actions.ActionRecord `json:"results"`
+	}
+)
+
+func NewJob(planMakers *actions.PlanMakers, targets []actions.Target, actionSequence []string) (*Job, error) {
+	job := &Job{targets: targets, plans: make([]*actions.ExecutionPlan, 0, len(targets))}
+
+	for _, target := range targets {
+		planMaker, err := planMakers.For(target)
+		if err == nil {
+			var plan *actions.ExecutionPlan
+			if plan, err = planMaker.MakePlan(actionSequence, target.Params()); err == nil {
+				job.plans = append(job.plans, plan)
+				continue
+			}
+		}
+
+		job.Cleanup()
+		return nil, fmt.Errorf("%s: %w", target, err)
+	}
+
+	return job, nil
+}
+
+// OnFinish registers an observer to be called when the plan of every target has finished
+func (j *Job) OnFinish(observer actions.PlanObserver) {
+	for _, plan := range j.plans {
+		plan.OnFinish(observer)
+	}
+}
+
+// As sets the identity the plans run on behalf of
+func (j *Job) As(identity string) {
+	for _, plan := range j.plans {
+		plan.As(identity)
+	}
+}
+
+// Cleanup releases the executors of the plans, it is only needed for jobs which aren't run
+func (j *Job) Cleanup() {
+	for _, plan := range j.plans {
+		plan.Cleanup()
+	}
+}
+
+// Run runs the plans, at most concurrency at a time, and returns the results in the order of the targets,
+// it fails when the plan of any target fails
+func (j *Job) Run(concurrency int) ([]Result, error) {
+	return j.run(concurrency, func(Result) {})
+}
+
+// run runs the plans as Run does, calling finished with the result of each target as soon as it is known
+func (j *Job) run(concurrency int, finished func(Result)) ([]Result, error) {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	results := make([]Result, len(j.plans))
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+
+	for i := range j.plans {
+		wg.Add(1)
+		sem <- struct{}{}
+
+		go func(i int) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+
+			actionResults, err := j.plans[i].Run()
+			results[i] = Result{Target: j.targets[i], Status: err == nil, Results: actions.NewActionRecords(actionResults)}
+			if err != nil {
+				results[i].Error = err.Error()
+			}
+			finished(results[i])
+		}(i)
+	}
+	wg.Wait()
+
+	failed := 0
+	for _, result := range results {
+		if !result.Status {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return results, fmt.Errorf("actions failed on %d of %d targets", failed, len(results))
+	}
+
+	return results, nil
+}
@@ -0,0 +1,142 @@
+package bulk
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bmc-toolbox/actor/internal/actions"
+)
+
+type (
+	testExecutorFactory struct {
+		mu      sync.Mutex
+		running int
+		peak    int
+	}
+
+	testExecutor struct {
+		factory *testExecutorFactory
+		host    string
+	}
+)
+
+func (f *testExecutorFactory) New(params map[string]interface{}) (actions.Executor, error) {
+	return &testExecutor{factory: f, host: fmt.Sprintf("%v", params["host"])}, nil
+}
+
+func (e *testExecutor) Validate(action string) error {
+	if action != actions.IsOn {
+		return fmt.Errorf("unknown action %q", action)
+	}
+	return nil
+}
+
+func (e *testExecutor) Run(action string) actions.ActionResult {
+	e.factory.mu.Lock()
+	e.factory.running++
+	if e.factory.running > e.factory.peak {
+		e.factory.peak = e.factory.running
+	}
+	e.factory.mu.Unlock()
+
+	time.Sleep(10 * time.Millisecond)
+
+	e.factory.mu.Lock()
+	e.factory.running--
+	e.factory.mu.Unlock()
+
+	if e.host == "10.0.0.3" {
+		return actions.NewActionResult(action, false, "failed", fmt.Errorf("unreachable"))
+	}
+	return actions.NewActionResult(action, true, "on", nil)
+}
+
+func (e *testExecutor) Cleanup() {}
+
+func TestJob(t *testing.T) {
+	factory := &testExecutorFactory{}
+	planMakers := &actions.PlanMakers{Host: actions.NewPlanMaker(factory)}
+
+	var targets []actions.Target
+	for i := 1; i <= 6; i++ {
+		targets = append(targets, actions.Target{Type: actions.TargetHost, Host: fmt.Sprintf("10.0.0.%d", i)})
+	}
+
+	if _, err := NewJob(planMakers, targets, []string{actions.PowerOn}); err == nil {
+		t.Fatalf("NewJob() accepted an action no target supports")
+	}
+
+	job, err := NewJob(planMakers, targets, []string{actions.IsOn})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	results, err := job.Run(2)
+	if err == nil {
+		t.Errorf("Run() succeeded although a target failed")
+	}
+	if factory.peak > 2 {
+		t.Errorf("Run() ran %d plans at once, want at most 2", factory.peak)
+	}
+
+	if len(results) != len(targets) {
+		t.Fatalf("Run() returned %d results, want %d", len(results), len(targets))
+	}
+	for i, result := range results {
+		if result.Target.Host != targets[i].Host {
+			t.Errorf("result %d is for %s, want %s", i, result.Target.Host, targets[i].Host)
+		}
+		if wantStatus := result.Target.Host != "10.0.0.3"; result.Status != wantStatus {
+			t.Errorf("status of %s = %v, want %v", result.Target.Host, result.Status, wantStatus)
+		}
+	}
+}
+
+func TestRunner(t *testing.T) {
+	planMakers := &actions.PlanMakers{Host: actions.NewPlanMaker(&testExecutorFactory{})}
+	runner := NewRunner()
+
+	for _, hosts := range [][]string{{"10.0.0.1", "10.0.0.2"}, {"10.0.0.3"}} {
+		var targets []actions.Target
+		for _, host := range hosts {
+			targets = append(targets, actions.Target{Type: actions.TargetHost, Host: host})
+		}
+
+		job, err := NewJob(planMakers, targets, []string{actions.IsOn})
+		if err != nil {
+			t.Fatal(err)
+		}
+		run, err := runner.Start(job, "rack=r1", []string{actions.IsOn}, 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if run.Status != StatusRunning || !reflect.DeepEqual(run.Targets, hosts) {
+			t.Errorf("Start() = %+v, want a running job on %v", run, hosts)
+		}
+	}
+	runner.Wait()
+
+	runs := runner.List()
+	if len(runs) != 2 {
+		t.Fatalf("List() returned %d runs, want 2", len(runs))
+	}
+	for _, run := range runs {
+		wantStatus := StatusDone
+		if run.Targets[0] == "10.0.0.3" {
+			wantStatus = StatusFailed
+		}
+		if run.Status != wantStatus || run.Finished != len(run.Targets) || len(run.Results) != len(run.Targets) {
+			t.Errorf("run on %v = %+v, want %s with a result per target", run.Targets, run, wantStatus)
+		}
+		if got, err := runner.Get(run.ID); err != nil || got.ID != run.ID {
+			t.Errorf("Get(%s) = %+v, %v", run.ID, got, err)
+		}
+	}
+
+	if _, err := runner.Get("unknown"); err != ErrNotFound {
+		t.Errorf("Get() error = %v, want ErrNotFound", err)
+	}
+}
@@ -0,0 +1,176 @@
+package bulk
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// StatusRunning, StatusDone and StatusFailed are the statuses of runs, a run fails when any target failed
+	StatusRunning = "running"
+	StatusDone    = "done"
+	StatusFailed  = "failed"
+
+	// retention is how long finished runs are kept, runs aren't persisted
+	retention = 24 * time.Hour
+)
+
+// ErrNotFound is returned for unknown run IDs
+var ErrNotFound = errors.New("bulk run not found")
+
+type (
+	// Run is a job carried out in the background, Results are in the order of Targets once it is finished
+	Run struct {
+		ID             string    `json:"id"`
+		Selector       string    `json:"selector"`
+		ActionSequence []string  `json:"action-sequence"`
+		Targets        []string  `json:"targets"`
+		Status         string    `json:"status"`
+		Error          string    `json:"error,omitempty"`
+		Created        time.Time `json:"created"`
+		Updated        time.Time `json:"updated"`
+		Finished       int       `json:"finished"`
+		Failed         int       `json:"failed"`
+		Results        []Result  `json:"results"`
+	}
+
+	// Runner runs jobs in the background so a request doesn't wait for every target
+	Runner struct {
+		mu   sync.Mutex
+		runs map[string]*Run
+		wg   sync.WaitGroup
+	}
+)
+
+func NewRunner() *Runner {
+	return &Runner{runs: make(map[string]*Run)}
+}
+
+// Start runs the job in the background, at most concurrency targets at a time
+func (r *Runner) Start(job *Job, selector string, actionSequence []string, concurrency int) (Run, error) {
+	id, err := newID()
+	if err != nil {
+		job.Cleanup()
+		return Run{}, err
+	}
+
+	targets := make([]string, 0, len(job.targets))
+	for _, target := range job.targets {
+		targets = append(targets, target.Host)
+	}
+
+	now := time.Now().UTC()
+	run := &Run{
+		ID:             id,
+		Selector:       selector,
+		ActionSequence: actionSequence,
+		Targets:        targets,
+		Status:         StatusRunning,
+		Created:        now,
+		Updated:        now,
+		Results:        make([]Result, 0),
+	}
+
+	r.mu.Lock()
+	r.prune(now)
+	r.runs[id] = run
+	runCopy := copyRun(run)
+	r.mu.Unlock()
+
+	r.wg.Add(1)
+	go r.run(run, job, concurrency)
+
+	return runCopy, nil
+}
+
+func (r *Runner) Get(id string) (Run, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	run, ok := r.runs[id]
+	if !ok {
+		return Run{}, ErrNotFound
+	}
+	return copyRun(run), nil
+}
+
+// List returns the runs, the most recent first
+func (r *Runner) List() []Run {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	runs := make([]Run, 0, len(r.runs))
+	for _, run := range r.runs {
+		runs = append(runs, copyRun(run))
+	}
+	sort.Slice(runs, func(i, j int) bool { return runs[i].Created.After(runs[j].Created) })
+
+	return runs
+}
+
+// Wait blocks until the running jobs are finished
+func (r *Runner) Wait() {
+	r.wg.Wait()
+}
+
+func (r *Runner) run(run *Run, job *Job, concurrency int) {
+	defer r.wg.Done()
+
+	logger := log.WithField("bulk", run.ID).WithField("selector", run.Selector)
+
+	results, err := job.run(concurrency, func(result Result) {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+
+		run.Finished++
+		if !result.Status {
+			run.Failed++
+		}
+		run.Updated = time.Now().UTC()
+	})
+
+	r.mu.Lock()
+	run.Results = results
+	run.Status = StatusDone
+	if err != nil {
+		run.Status, run.Error = StatusFailed, err.Error()
+	}
+	run.Updated = time.Now().UTC()
+	r.mu.Unlock()
+
+	if err != nil {
+		logger.WithError(err).Warn("bulk run failed")
+		return
+	}
+	logger.WithField("targets", len(results)).Info("bulk run finished")
+}
+
+// prune drops the runs finished for longer than the retention, the caller must hold the lock
+func (r *Runner) prune(now time.Time) {
+	for id, run := range r.runs {
+		if run.Status != StatusRunning && now.Sub(run.Updated) > retention {
+			delete(r.runs, id)
+		}
+	}
+}
+
+func copyRun(run *Run) Run {
+	runCopy := *run
+	runCopy.Results = append(make([]Result, 0, len(run.Results)), run.Results...)
+	return runCopy
+}
+
+func newID() (string, error) {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate an ID: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,91 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/bmc-toolbox/actor/internal/actions"
+	"github.com/bmc-toolbox/actor/internal/certificates"
+	"github.com/bmc-toolbox/actor/internal/providers"
+	"github.com/bmc-toolbox/bmclib/cfgresources"
+	"github.com/bmc-toolbox/bmclib/devices"
+)
+
+const (
+	certificateFileName = "actor.crt"
+	keyFileName         = "actor.key"
+)
+
+// CertificateManager reads the HTTPS certificates of BMCs, generates CSRs and uploads signed certificates
+type CertificateManager struct {
+	username    string
+	passwords   passwordSource
+	timeout     time.Duration
+	concurrency int
+}
+
+func NewCertificateManager(username string, passwords passwordSource, timeout time.Duration, concurrency int) *CertificateManager {
+	return &CertificateManager{username: username, passwords: passwords, timeout: timeout, concurrency: concurrency}
+}
+
+func (m *CertificateManager) Certificate(host string) (certificates.Info, error) {
+	return certificates.Fetch(host, m.timeout)
+}
+
+// Report reads the certificates of the hosts in parallel, the certificates expiring within the duration are flagged
+func (m *CertificateManager) Report(hosts []string, within time.Duration) []certificates.ReportEntry {
+	return certificates.Report(hosts, m.concurrency, time.Now().Add(within), m.Certificate)
+}
+
+// GenerateCSR has the BMC generate a key and returns the PEM CSR to be signed, targetType is host or chassis
+func (m *CertificateManager) GenerateCSR(targetType, host string, attributes *cfgresources.HTTPSCertAttributes) ([]byte, error) {
+	var csr []byte
+
+	err := m.configure(targetType, host, func(bmc devices.Configure) error {
+		var err error
+		csr, err = bmc.GenerateCSR(attributes)
+		return err
+	})
+
+	return csr, err
+}
+
+// UploadCertificate uploads the PEM certificate and key, which are checked with certificates.CheckKeyPair beforehand,
+// it returns whether the BMC resets to use them
+func (m *CertificateManager) UploadCertificate(targetType, host string, cert, key []byte) (bool, error) {
+	var reset bool
+
+	err := m.configure(targetType, host, func(bmc devices.Configure) error {
+		var err error
+		reset, err = bmc.UploadHTTPSCert(cert, certificateFileName, key, keyFileName)
+		return err
+	})
+
+	return reset, err
+}
+
+func (m *CertificateManager) configure(targetType, host string, fn func(devices.Configure) error) error {
+	switch targetType {
+	case actions.TargetHost:
+		bmc := providers.NewServerBmcWrapper(m.username, m.passwords.Password(host), host)
+		defer func() { _ = bmc.Close(context.TODO()) }()
+
+		configurer, err := bmc.Configurer()
+		if err != nil {
+			return err
+		}
+		return fn(configurer)
+	case actions.TargetChassis:
+		bmc := providers.NewChassisBmcWrapper(m.username, m.passwords.Password(host), host)
+		defer func() { _ = bmc.Close() }()
+
+		configurer, _, err := bmc.Configurer()
+		if err != nil {
+			return err
+		}
+		return fn(configurer)
+	}
+
+	return fmt.Errorf("certificates can't be managed for %s targets", targetType)
+}
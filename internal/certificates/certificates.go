@@ -0,0 +1,136 @@
+package certificates
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"sort"
+	"sync"
+	"time"
+)
+
+type (
+	// Info describes the HTTPS certificate of a BMC
+	Info struct {
+		Subject     string    `json:"subject"`
+		Issuer      string    `json:"issuer"`
+		DNSNames    []string  `json:"dns-names"`
+		IPAddresses []string  `json:"ip-addresses"`
+		NotBefore   time.Time `json:"not-before"`
+		NotAfter    time.Time `json:"not-after"`
+		SelfSigned  bool      `json:"self-signed"`
+	}
+
+	// ReportEntry is the certificate of a host in a fleet report, Error is set when it couldn't be read
+	ReportEntry struct {
+		Host        string `json:"host"`
+		Certificate *Info  `json:"certificate,omitempty"`
+		Expiring    bool   `json:"expiring"`
+		Error       string `json:"error,omitempty"`
+	}
+)
+
+func NewInfo(cert *x509.Certificate) Info {
+	info := Info{
+		Subject:     cert.Subject.String(),
+		Issuer:      cert.Issuer.String(),
+		DNSNames:    cert.DNSNames,
+		IPAddresses: make([]string, 0, len(cert.IPAddresses)),
+		NotBefore:   cert.NotBefore.UTC(),
+		NotAfter:    cert.NotAfter.UTC(),
+		SelfSigned:  bytes.Equal(cert.RawIssuer, cert.RawSubject) && cert.CheckSignature(cert.SignatureAlgorithm, cert.RawTBSCertificate, cert.Signature) == nil,
+	}
+	if info.DNSNames == nil {
+		info.DNSNames = []string{}
+	}
+	for _, ip := range cert.IPAddresses {
+		info.IPAddresses = append(info.IPAddresses, ip.String())
+	}
+
+	return info
+}
+
+// Report reads the certificates of the hosts with up to concurrency reads at once, the entries are sorted by expiry
+// with the hosts whose certificate couldn't be read first, a certificate is expiring when it expires before deadline
+func Report(hosts []string, concurrency int, deadline time.Time, read func(host string) (Info, error)) []ReportEntry {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	entries := make([]ReportEntry, len(hosts))
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+
+	for i, host := range hosts {
+		wg.Add(1)
+		sem <- struct{}{}
+
+		go func(i int, host string) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+
+			entry := ReportEntry{Host: host}
+			if info, err := read(host); err != nil {
+				entry.Error = err.Error()
+			} else {
+				entry.Certificate = &info
+				entry.Expiring = info.NotAfter.Before(deadline)
+			}
+			entries[i] = entry
+		}(i, host)
+	}
+	wg.Wait()
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		a, b := entries[i].Certificate, entries[j].Certificate
+		if a == nil || b == nil {
+			return a == nil && b != nil
+		}
+		return a.NotAfter.Before(b.NotAfter)
+	})
+
+	return entries
+}
+
+// CheckKeyPair checks the PEM certificate and key belong together before they are uploaded,
+// a BMC given a mismatching pair may end up without a working HTTPS endpoint
+func CheckKeyPair(cert, key []byte) error {
+	pair, err := tls.X509KeyPair(cert, key)
+	if err != nil {
+		return fmt.Errorf("invalid certificate and key: %w", err)
+	}
+
+	parsed, err := x509.ParseCertificate(pair.Certificate[0])
+	if err != nil {
+		return fmt.Errorf("invalid certificate: %w", err)
+	}
+	if time.Now().After(parsed.NotAfter) {
+		return fmt.Errorf("the certificate expired at %s", parsed.NotAfter.UTC().Format(time.RFC3339))
+	}
+
+	return nil
+}
+
+// Fetch reads the certificate the BMC presents on its HTTPS port, no login is needed
+// so it works for every BMC whatever its vendor
+func Fetch(host string, timeout time.Duration) (Info, error) {
+	dialer := &net.Dialer{Timeout: timeout}
+
+	// the certificate is inspected, not trusted
+	conn, err := tls.DialWithDialer(dialer, "tcp", net.JoinHostPort(host, "443"), &tls.Config{InsecureSkipVerify: true})
+	if err != nil {
+		return Info{}, fmt.Errorf("failed to connect to %s: %w", host, err)
+	}
+	defer func() { _ = conn.Close() }()
+
+	certs := conn.ConnectionState().PeerCertificates
+	if len(certs) == 0 {
+		return Info{}, fmt.Errorf("%s presented no certificate", host)
+	}
+
+	return NewInfo(certs[0]), nil
+}
@@ -0,0 +1,83 @@
+package certificates
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/bmc-toolbox/actor/internal/certificates/certtest"
+)
+
+func TestNewInfo(t *testing.T) {
+	notAfter := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	certPEM, _ := certtest.NewPair(t, notAfter)
+
+	block, _ := pem.Decode(certPEM)
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	info := NewInfo(cert)
+	if info.Subject != "CN=bmc.example.com" || !info.SelfSigned || !info.NotAfter.Equal(notAfter) {
+		t.Errorf("NewInfo() = %+v", info)
+	}
+	if len(info.IPAddresses) != 1 || info.IPAddresses[0] != "10.0.0.1" {
+		t.Errorf("NewInfo() ip addresses = %v, want [10.0.0.1]", info.IPAddresses)
+	}
+}
+
+func TestReport(t *testing.T) {
+	now := time.Date(2021, 6, 1, 0, 0, 0, 0, time.UTC)
+	expiries := map[string]time.Time{
+		"10.0.0.1": now.Add(90 * 24 * time.Hour),
+		"10.0.0.2": now.Add(5 * 24 * time.Hour),
+	}
+	read := func(host string) (Info, error) {
+		notAfter, ok := expiries[host]
+		if !ok {
+			return Info{}, errors.New("connection refused")
+		}
+		return Info{NotAfter: notAfter}, nil
+	}
+
+	entries := Report([]string{"10.0.0.1", "10.0.0.2", "10.0.0.3"}, 2, now.Add(30*24*time.Hour), read)
+
+	var hosts []string
+	for _, entry := range entries {
+		hosts = append(hosts, entry.Host)
+	}
+	if len(hosts) != 3 || hosts[0] != "10.0.0.3" || hosts[1] != "10.0.0.2" || hosts[2] != "10.0.0.1" {
+		t.Fatalf("Report() hosts = %v, want [10.0.0.3 10.0.0.2 10.0.0.1]", hosts)
+	}
+	if entries[0].Error != "connection refused" || !entries[1].Expiring || entries[2].Expiring {
+		t.Errorf("Report() = %+v", entries)
+	}
+}
+
+func TestCheckKeyPair(t *testing.T) {
+	cert, key := certtest.NewPair(t, time.Now().Add(time.Hour))
+	_, otherKey := certtest.NewPair(t, time.Now().Add(time.Hour))
+	expiredCert, expiredKey := certtest.NewPair(t, time.Now().Add(-time.Hour))
+
+	tests := []struct {
+		name    string
+		cert    []byte
+		key     []byte
+		wantErr bool
+	}{
+		{name: "Matching", cert: cert, key: key},
+		{name: "Other key", cert: cert, key: otherKey, wantErr: true},
+		{name: "Expired", cert: expiredCert, key: expiredKey, wantErr: true},
+		{name: "Not PEM", cert: []byte("certificate"), key: key, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := CheckKeyPair(tt.cert, tt.key); (err != nil) != tt.wantErr {
+				t.Errorf("CheckKeyPair() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -9,8 +9,8 @@ import (
 
 type (
 	ChassisExecutorFactory struct {
-		username string
-		password string
+		username  string
+		passwords passwordSource
 	}
 
 	ChassisExecutor struct {
@@ -20,26 +20,36 @@ type (
 	chassisBmcProvider interface {
 		IsOn() (bool, error)
 		PowerOn() (bool, error)
+		PowerOff() (bool, error)
 		PowerCycle() (bool, error)
+		SetDynamicPower(bool) (bool, error)
+		TempC() (int, error)
+		IsPsuRedundant() (bool, error)
 		Close() error
+
+		Info() (providers.BmcInfo, error)
 	}
 )
 
-func NewChassisExecutorFactory(username, password string) *ChassisExecutorFactory {
-	return &ChassisExecutorFactory{username: username, password: password}
+func NewChassisExecutorFactory(username string, passwords passwordSource) *ChassisExecutorFactory {
+	return &ChassisExecutorFactory{username: username, passwords: passwords}
 }
 
 func (f *ChassisExecutorFactory) New(params map[string]interface{}) (actions.Executor, error) {
-	if err := validateParam(params, paramHost); err != nil {
+	if err := validateParam(params, actions.ParamHost); err != nil {
 		return nil, fmt.Errorf("failed to create a new executor: %w", err)
 	}
 
-	host := fmt.Sprintf("%v", params[paramHost])
+	host := fmt.Sprintf("%v", params[actions.ParamHost])
 
-	return &ChassisExecutor{bmc: providers.NewChassisBmcWrapper(f.username, f.password, host)}, nil
+	return &ChassisExecutor{bmc: providers.NewChassisBmcWrapper(f.username, f.passwords.Password(host), host)}, nil
 }
 
 func (e *ChassisExecutor) Validate(action string) error {
+	if isRequireAction(action) {
+		return e.requirementProbes().validate(action)
+	}
+
 	_, err := e.matchActionToFn(action)
 	return err
 }
@@ -48,12 +58,30 @@ func (e *ChassisExecutor) Run(action string) actions.ActionResult {
 	return e.doAction(action)
 }
 
+func (e *ChassisExecutor) Capabilities() (actions.Capabilities, error) {
+	info, err := e.bmc.Info()
+	if err != nil {
+		return actions.Capabilities{}, err
+	}
+
+	return newCapabilities(append(actions.SupportedActions(e.Validate), actions.DynamicPower, actions.Require), info), nil
+}
+
 func (e *ChassisExecutor) matchActionToFn(action string) (func() (bool, error), error) {
+	if enable, ok, err := parseToggle(action, dynamicPowerToggle); ok {
+		if err != nil {
+			return nil, err
+		}
+		return func() (bool, error) { return e.bmc.SetDynamicPower(enable) }, nil
+	}
+
 	switch action {
 	case actions.IsOn:
 		return e.bmc.IsOn, nil
 	case actions.PowerOn:
 		return e.bmc.PowerOn, nil
+	case actions.PowerOff:
+		return e.bmc.PowerOff, nil
 	case actions.PowerCycle:
 		return e.bmc.PowerCycle, nil
 	}
@@ -61,7 +89,15 @@ func (e *ChassisExecutor) matchActionToFn(action string) (func() (bool, error),
 	return nil, fmt.Errorf("unknown action %q", action)
 }
 
+func (e *ChassisExecutor) requirementProbes() requirementProbes {
+	return requirementProbes{isOn: e.bmc.IsOn, tempC: e.bmc.TempC, isPsuRedundant: e.bmc.IsPsuRedundant}
+}
+
 func (e *ChassisExecutor) doAction(action string) actions.ActionResult {
+	if isRequireAction(action) {
+		return e.requirementProbes().check(action)
+	}
+
 	fn, err := e.matchActionToFn(action)
 	if err != nil {
 		return actions.NewActionResult(action, false, "failed", err)
@@ -0,0 +1,55 @@
+package internal
+
+import (
+	"context"
+
+	"github.com/bmc-toolbox/actor/internal/bmcconfig"
+	"github.com/bmc-toolbox/actor/internal/providers"
+	"github.com/bmc-toolbox/bmclib/cfgresources"
+)
+
+// ConfigManager applies ResourcesConfig documents to host and chassis BMCs, or compares them when diff is set
+type ConfigManager struct {
+	username  string
+	passwords passwordSource
+	applier   *bmcconfig.Applier
+	profiles  *bmcconfig.Profiles
+}
+
+func NewConfigManager(username string, passwords passwordSource, applier *bmcconfig.Applier, profiles *bmcconfig.Profiles) *ConfigManager {
+	return &ConfigManager{username: username, passwords: passwords, applier: applier, profiles: profiles}
+}
+
+func (m *ConfigManager) Profile(name string) (*cfgresources.ResourcesConfig, error) {
+	return m.profiles.Profile(name)
+}
+
+func (m *ConfigManager) ConfigureHost(host string, cfg *cfgresources.ResourcesConfig, diff bool) ([]bmcconfig.Result, error) {
+	bmc := providers.NewServerBmcWrapper(m.username, m.passwords.Password(host), host)
+	defer func() { _ = bmc.Close(context.TODO()) }()
+
+	configurer, err := bmc.Configurer()
+	if err != nil {
+		return nil, err
+	}
+
+	if diff {
+		return m.applier.Diff(configurer, nil, cfg), nil
+	}
+	return m.applier.Apply(configurer, nil, cfg), nil
+}
+
+func (m *ConfigManager) ConfigureChassis(host string, cfg *cfgresources.ResourcesConfig, diff bool) ([]bmcconfig.Result, error) {
+	bmc := providers.NewChassisBmcWrapper(m.username, m.passwords.Password(host), host)
+	defer func() { _ = bmc.Close() }()
+
+	configurer, setup, err := bmc.Configurer()
+	if err != nil {
+		return nil, err
+	}
+
+	if diff {
+		return m.applier.Diff(configurer, setup, cfg), nil
+	}
+	return m.applier.Apply(configurer, setup, cfg), nil
+}
@@ -1,7 +1,8 @@
 package internal
 
+// names of the actions switched on or off, e.g. "ipmilan on"
 const (
-	paramHost          = "host"
-	paramBladePosition = "bladePos"
-	paramBladeSerial   = "bladeSerial"
+	dynamicPowerToggle = "dynamicpower"
+	ipmiLanToggle      = "ipmilan"
+	flexAddressToggle  = "flexaddress"
 )
@@ -0,0 +1,81 @@
+package credentials
+
+import (
+	"errors"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const hostsRef = "hosts/"
+
+// hostRefReplacer turns host:port and IPv6 addresses into valid references
+var hostRefReplacer = strings.NewReplacer(":", "_", "[", "", "]", "")
+
+type (
+	// Keyring resolves the BMC password of hosts: the password set for the host in the store once it has been rotated,
+	// the password referenced by the registration of the host, the shared password otherwise. Only missing passwords
+	// and a missing store fall back, a host whose password can't be read gets none rather than a password it may not
+	// have anymore
+	Keyring struct {
+		store  *FileStore
+		shared string
+		refs   refSource
+	}
+
+	// refSource returns the reference of the password of a host, e.g. from the fleet registry
+	refSource interface {
+		PasswordRef(host string) (string, bool)
+	}
+)
+
+func NewKeyring(store *FileStore, shared string) *Keyring {
+	return &Keyring{store: store, shared: shared}
+}
+
+// WithRefs resolves the passwords of hosts which haven't been rotated with the references of refs
+func (k *Keyring) WithRefs(refs refSource) *Keyring {
+	k.refs = refs
+	return k
+}
+
+// Password returns the BMC password of the host, it is empty when the store fails to read it.
+// Without a store every host gets the shared password
+func (k *Keyring) Password(host string) string {
+	password, err := k.store.Password(HostRef(host))
+	if err == nil {
+		return password
+	}
+	if errors.Is(err, ErrNoStore) {
+		return k.shared
+	}
+	if !errors.Is(err, ErrNotFound) {
+		log.WithField("ip", host).WithError(err).Error("failed to read the BMC password")
+		return ""
+	}
+
+	if k.refs != nil {
+		if ref, ok := k.refs.PasswordRef(host); ok {
+			password, err := k.store.Password(ref)
+			if err == nil {
+				return password
+			}
+			if !errors.Is(err, ErrNotFound) {
+				log.WithField("ip", host).WithField("ref", ref).WithError(err).Error("failed to read the referenced BMC password")
+				return ""
+			}
+		}
+	}
+
+	return k.shared
+}
+
+// SetPassword records the BMC password of the host, which is used from then on instead of the shared password
+func (k *Keyring) SetPassword(host, password string) error {
+	return k.store.SetPassword(HostRef(host), password)
+}
+
+// HostRef is the reference of the password of the host in the store
+func HostRef(host string) string {
+	return hostsRef + hostRefReplacer.Replace(host)
+}
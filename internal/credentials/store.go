@@ -0,0 +1,93 @@
+package credentials
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+var (
+	// ErrNotFound is returned for references without a stored password
+	ErrNotFound = errors.New("credential not found")
+	// ErrNoStore is returned when no directory is configured for the store
+	ErrNoStore = errors.New("no credential store is configured")
+
+	// validRef keeps references inside the store, e.g. "bmc/admin-2021q3"
+	validRef = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*(/[A-Za-z0-9][A-Za-z0-9._-]*)*$`)
+)
+
+// FileStore keeps every password in its own file under dir, e.g. secrets mounted by a vault agent,
+// requests reference the passwords by their path relative to dir so they never carry them
+type FileStore struct {
+	dir string
+}
+
+func NewFileStore(dir string) *FileStore {
+	return &FileStore{dir: dir}
+}
+
+// Password reads the password referenced by ref, a trailing newline is dropped
+func (s *FileStore) Password(ref string) (string, error) {
+	path, err := s.path(ref)
+	if err != nil {
+		return "", err
+	}
+
+	payload, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return "", fmt.Errorf("%w: %s", ErrNotFound, ref)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	password := strings.TrimRight(string(payload), "\r\n")
+	if password == "" {
+		return "", fmt.Errorf("credential %s is empty", ref)
+	}
+
+	return password, nil
+}
+
+// SetPassword stores the password under ref, the file is replaced atomically so readers never see a partial password
+func (s *FileStore) SetPassword(ref, password string) error {
+	path, err := s.path(ref)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+
+	tmp, err := ioutil.TempFile(filepath.Dir(path), ".credential-")
+	if err != nil {
+		return err
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+
+	_, err = tmp.WriteString(password)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("failed to store credential %s: %w", ref, err)
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
+
+func (s *FileStore) path(ref string) (string, error) {
+	if s.dir == "" {
+		return "", ErrNoStore
+	}
+	if !validRef.MatchString(ref) {
+		return "", fmt.Errorf("invalid credential reference %q", ref)
+	}
+
+	return filepath.Join(s.dir, filepath.FromSlash(ref)), nil
+}
@@ -0,0 +1,128 @@
+package credentials
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFileStore_Password(t *testing.T) {
+	dir, err := ioutil.TempDir("", "credentials")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	if err := os.MkdirAll(filepath.Join(dir, "bmc"), 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, "bmc", "admin"), []byte("s3cret\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		store   *FileStore
+		ref     string
+		want    string
+		wantErr error
+	}{
+		{name: "Stored", store: NewFileStore(dir), ref: "bmc/admin", want: "s3cret"},
+		{name: "Missing", store: NewFileStore(dir), ref: "bmc/other", wantErr: ErrNotFound},
+		{name: "Outside the store", store: NewFileStore(dir), ref: "../etc/passwd", wantErr: errors.New("invalid")},
+		{name: "Absolute", store: NewFileStore(dir), ref: "/etc/passwd", wantErr: errors.New("invalid")},
+		{name: "No store", store: NewFileStore(""), ref: "bmc/admin", wantErr: ErrNoStore},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.store.Password(tt.ref)
+			if (err != nil) != (tt.wantErr != nil) {
+				t.Fatalf("Password() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if errors.Is(tt.wantErr, ErrNotFound) && !errors.Is(err, ErrNotFound) {
+				t.Errorf("Password() error = %v, want %v", err, ErrNotFound)
+			}
+			if got != tt.want {
+				t.Errorf("Password() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestKeyring(t *testing.T) {
+	dir, err := ioutil.TempDir("", "credentials")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	keyring := NewKeyring(NewFileStore(dir), "shared")
+
+	if got := keyring.Password("10.0.0.1:623"); got != "shared" {
+		t.Errorf("Password() = %q, want the shared password", got)
+	}
+	if err := keyring.SetPassword("10.0.0.1:623", "rotated"); err != nil {
+		t.Fatal(err)
+	}
+	if got := keyring.Password("10.0.0.1:623"); got != "rotated" {
+		t.Errorf("Password() = %q, want the rotated password", got)
+	}
+	if got := keyring.Password("10.0.0.2"); got != "shared" {
+		t.Errorf("Password() of another host = %q, want the shared password", got)
+	}
+}
+
+type testRefs map[string]string
+
+func (r testRefs) PasswordRef(host string) (string, bool) {
+	ref, ok := r[host]
+	return ref, ok
+}
+
+func TestKeyring_NoStore(t *testing.T) {
+	keyring := NewKeyring(NewFileStore(""), "shared").WithRefs(testRefs{"10.0.0.2": "blades/r12"})
+
+	for _, host := range []string{"10.0.0.1", "10.0.0.2"} {
+		if got := keyring.Password(host); got != "shared" {
+			t.Errorf("Password() of %s without a store = %q, want the shared password", host, got)
+		}
+	}
+}
+
+func TestKeyring_WithRefs(t *testing.T) {
+	dir, err := ioutil.TempDir("", "credentials")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	store := NewFileStore(dir)
+	if err := store.SetPassword("blades/r12", "referenced"); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, "blades", "empty"), nil, 0600); err != nil {
+		t.Fatal(err)
+	}
+	refs := testRefs{"10.0.0.1": "blades/r12", "10.0.0.2": "missing", "10.0.0.3": "blades/empty", "10.0.0.4": "../outside"}
+	keyring := NewKeyring(store, "shared").WithRefs(refs)
+
+	if got := keyring.Password("10.0.0.1"); got != "referenced" {
+		t.Errorf("Password() = %q, want the referenced password", got)
+	}
+	if got := keyring.Password("10.0.0.2"); got != "shared" {
+		t.Errorf("Password() with a missing reference = %q, want the shared password", got)
+	}
+	for _, host := range []string{"10.0.0.3", "10.0.0.4"} {
+		if got := keyring.Password(host); got != "" {
+			t.Errorf("Password() with an unreadable reference = %q, want no password rather than the shared one", got)
+		}
+	}
+	if err := keyring.SetPassword("10.0.0.1", "rotated"); err != nil {
+		t.Fatal(err)
+	}
+	if got := keyring.Password("10.0.0.1"); got != "rotated" {
+		t.Errorf("Password() = %q, want the rotated password over the reference", got)
+	}
+}
@@ -0,0 +1,116 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/bmc-toolbox/actor/internal/actions"
+	"github.com/bmc-toolbox/actor/internal/discovery"
+	"github.com/bmc-toolbox/actor/internal/providers"
+	"github.com/bmc-toolbox/actor/internal/providers/ipmi"
+	"github.com/bmc-toolbox/bmclib/devices"
+	"github.com/bmc-toolbox/bmclib/discover"
+	bmcerrors "github.com/bmc-toolbox/bmclib/errors"
+)
+
+// DiscoveryProber identifies BMCs with the vendor probes of bmclib and falls back to the IPMI Presence Ping,
+// the credentials actor would use for the address are checked on the BMCs found
+type DiscoveryProber struct {
+	username  string
+	passwords passwordSource
+	timeout   time.Duration
+}
+
+func NewDiscoveryProber(username string, passwords passwordSource, timeout time.Duration) *DiscoveryProber {
+	return &DiscoveryProber{username: username, passwords: passwords, timeout: timeout}
+}
+
+func (p *DiscoveryProber) Probe(host string) *discovery.BMC {
+	answersIpmi := discovery.Ping(host, p.timeout)
+	password := p.passwords.Password(host)
+
+	if discovery.ListensHTTPS(host, p.timeout) {
+		conn, err := discover.ScanAndConnect(host, p.username, password)
+		switch c := conn.(type) {
+		case devices.Bmc:
+			return p.identifyBmc(host, c)
+		case devices.Cmc:
+			return p.identifyCmc(host, c)
+		}
+
+		// anything else listening on 443 isn't a BMC unless it answers IPMI
+		if err != nil && !errors.Is(err, bmcerrors.ErrVendorUnknown) && !answersIpmi {
+			return nil
+		}
+	}
+
+	if !answersIpmi {
+		return nil
+	}
+
+	bmc := &discovery.BMC{Host: host, Type: actions.TargetHost, Provider: providers.ProviderIpmi}
+
+	ipmiProvider, err := ipmi.New(p.username, password, host)
+	if err == nil {
+		_, err = ipmiProvider.IsOn()
+	}
+	bmc.CredentialsValid = err == nil
+	if err != nil {
+		bmc.Error = err.Error()
+	}
+
+	return bmc
+}
+
+func (p *DiscoveryProber) identifyBmc(host string, conn devices.Bmc) *discovery.BMC {
+	defer func() { _ = conn.Close(context.TODO()) }()
+
+	bmc := &discovery.BMC{
+		Host:         host,
+		Type:         actions.TargetHost,
+		Provider:     providers.ProviderBmclib,
+		Vendor:       conn.Vendor(),
+		HardwareType: conn.HardwareType(),
+	}
+
+	if err := conn.CheckCredentials(); err != nil {
+		bmc.Error = err.Error()
+		return bmc
+	}
+	bmc.CredentialsValid = true
+
+	serial, err := conn.Serial()
+	if err != nil {
+		bmc.Error = err.Error()
+	}
+	bmc.Serial = serial
+
+	return bmc
+}
+
+func (p *DiscoveryProber) identifyCmc(host string, conn devices.Cmc) *discovery.BMC {
+	defer func() { _ = conn.Close() }()
+
+	bmc := &discovery.BMC{
+		Host:         host,
+		Type:         actions.TargetChassis,
+		Provider:     providers.ProviderBmclib,
+		Vendor:       conn.Vendor(),
+		HardwareType: conn.HardwareType(),
+	}
+
+	if err := conn.CheckCredentials(); err != nil {
+		bmc.Error = err.Error()
+		return bmc
+	}
+	bmc.CredentialsValid = true
+
+	serial, err := conn.Serial()
+	if err != nil {
+		bmc.Error = err.Error()
+	}
+	bmc.Serial = serial
+
+	return bmc
+}
@@ -0,0 +1,330 @@
+package discovery
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bmc-toolbox/actor/internal/registry"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// StatusRunning and StatusDone are the statuses of jobs
+	StatusRunning = "running"
+	StatusDone    = "done"
+
+	// retention is how long finished jobs are kept, jobs aren't persisted
+	retention = 24 * time.Hour
+)
+
+// ErrNotFound is returned for unknown job IDs
+var ErrNotFound = errors.New("discovery not found")
+
+type (
+	// BMC describes a BMC found by a scan, Serial is only known when the credentials work
+	BMC struct {
+		Host             string `json:"host"`
+		Type             string `json:"type"`
+		Provider         string `json:"provider"`
+		Vendor           string `json:"vendor"`
+		HardwareType     string `json:"hardware-type"`
+		Serial           string `json:"serial"`
+		CredentialsValid bool   `json:"credentials-valid"`
+		Error            string `json:"error,omitempty"`
+		Registered       bool   `json:"registered"`
+	}
+
+	// Job scans the addresses of a CIDR range, Found is sorted by host
+	Job struct {
+		ID       string            `json:"id"`
+		CIDR     string            `json:"cidr"`
+		Register bool              `json:"register"`
+		Labels   map[string]string `json:"labels,omitempty"`
+		Status   string            `json:"status"`
+		Created  time.Time         `json:"created"`
+		Updated  time.Time         `json:"updated"`
+		Total    int               `json:"total"`
+		Scanned  int               `json:"scanned"`
+		Found    []BMC             `json:"found"`
+	}
+
+	// Prober looks for a BMC at the address, it returns nil when there is none
+	Prober interface {
+		Probe(host string) *BMC
+	}
+
+	// Registrar records the BMCs found in the target registry
+	Registrar interface {
+		IsRegistered(host string) bool
+		Put(target registry.Target) error
+	}
+
+	// Scanner runs discovery jobs in the background, probing at most concurrency addresses at once
+	// and starting at most rate probes per second
+	Scanner struct {
+		prober       Prober
+		registrar    Registrar
+		concurrency  int
+		rate         float64
+		maxAddresses int
+
+		mu   sync.Mutex
+		jobs map[string]*Job
+		wg   sync.WaitGroup
+	}
+)
+
+// New returns a scanner, without a registrar the BMCs found can't be registered
+func New(prober Prober, registrar Registrar, concurrency int, rate float64, maxAddresses int) *Scanner {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	return &Scanner{
+		prober:       prober,
+		registrar:    registrar,
+		concurrency:  concurrency,
+		rate:         rate,
+		maxAddresses: maxAddresses,
+		jobs:         make(map[string]*Job),
+	}
+}
+
+// Start creates a job scanning the CIDR range and runs it in the background,
+// with register the BMCs found which aren't registered yet are registered with the labels
+func (s *Scanner) Start(cidr string, register bool, labels map[string]string) (Job, error) {
+	if register && s.registrar == nil {
+		return Job{}, fmt.Errorf("the BMCs found can't be registered: the registry isn't enabled")
+	}
+	if err := registry.ValidateLabels(labels); err != nil {
+		return Job{}, err
+	}
+
+	hosts, err := Hosts(cidr, s.maxAddresses)
+	if err != nil {
+		return Job{}, err
+	}
+
+	id, err := newID()
+	if err != nil {
+		return Job{}, err
+	}
+
+	now := time.Now().UTC()
+	job := &Job{
+		ID:       id,
+		CIDR:     cidr,
+		Register: register,
+		Labels:   labels,
+		Status:   StatusRunning,
+		Created:  now,
+		Updated:  now,
+		Total:    len(hosts),
+		Found:    make([]BMC, 0),
+	}
+
+	s.mu.Lock()
+	s.prune(now)
+	s.jobs[id] = job
+	jobCopy := copyJob(job)
+	s.mu.Unlock()
+
+	s.wg.Add(1)
+	go s.run(job, hosts)
+
+	return jobCopy, nil
+}
+
+func (s *Scanner) Get(id string) (Job, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	job, ok := s.jobs[id]
+	if !ok {
+		return Job{}, ErrNotFound
+	}
+	return copyJob(job), nil
+}
+
+// List returns the jobs, the most recent first
+func (s *Scanner) List() []Job {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	jobs := make([]Job, 0, len(s.jobs))
+	for _, job := range s.jobs {
+		jobs = append(jobs, copyJob(job))
+	}
+	sort.Slice(jobs, func(i, j int) bool { return jobs[i].Created.After(jobs[j].Created) })
+
+	return jobs
+}
+
+// Wait blocks until the running jobs are finished
+func (s *Scanner) Wait() {
+	s.wg.Wait()
+}
+
+func (s *Scanner) run(job *Job, hosts []string) {
+	defer s.wg.Done()
+
+	logger := log.WithField("discovery", job.ID).WithField("cidr", job.CIDR)
+
+	var tick <-chan time.Time
+	if s.rate > 0 {
+		ticker := time.NewTicker(time.Duration(float64(time.Second) / s.rate))
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+
+	sem := make(chan struct{}, s.concurrency)
+	var wg sync.WaitGroup
+
+	for _, host := range hosts {
+		if tick != nil {
+			<-tick
+		}
+
+		wg.Add(1)
+		sem <- struct{}{}
+
+		go func(host string) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+
+			bmc := s.prober.Probe(host)
+			if bmc != nil && job.Register {
+				s.register(job, bmc, logger)
+			}
+			s.record(job, bmc)
+		}(host)
+	}
+	wg.Wait()
+
+	s.mu.Lock()
+	job.Status = StatusDone
+	job.Updated = time.Now().UTC()
+	found := len(job.Found)
+	s.mu.Unlock()
+
+	logger.WithField("found", found).Info("BMC discovery finished")
+}
+
+// register adds the BMC to the registry unless it is registered already, its vendor is added to the labels
+func (s *Scanner) register(job *Job, bmc *BMC, logger *log.Entry) {
+	if s.registrar.IsRegistered(bmc.Host) {
+		bmc.Registered = true
+		return
+	}
+
+	labels := make(map[string]string, len(job.Labels)+1)
+	for key, value := range job.Labels {
+		labels[key] = value
+	}
+	if vendor := strings.ToLower(bmc.Vendor); vendor != "" {
+		labels["vendor"] = vendor
+	}
+
+	if err := s.registrar.Put(registry.Target{Host: bmc.Host, Type: bmc.Type, Labels: labels}); err != nil {
+		logger.WithField("ip", bmc.Host).WithError(err).Warn("failed to register the BMC found")
+		return
+	}
+	bmc.Registered = true
+}
+
+func (s *Scanner) record(job *Job, bmc *BMC) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	job.Scanned++
+	job.Updated = time.Now().UTC()
+
+	if bmc == nil {
+		return
+	}
+	job.Found = append(job.Found, *bmc)
+	sort.Slice(job.Found, func(i, j int) bool { return compareHosts(job.Found[i].Host, job.Found[j].Host) })
+}
+
+// prune drops the jobs finished for longer than the retention, the caller must hold the lock
+func (s *Scanner) prune(now time.Time) {
+	for id, job := range s.jobs {
+		if job.Status == StatusDone && now.Sub(job.Updated) > retention {
+			delete(s.jobs, id)
+		}
+	}
+}
+
+// Hosts returns the addresses of the CIDR range, without the network and broadcast addresses of IPv4 ranges
+// larger than /31, ranges of more than maxAddresses are refused
+func Hosts(cidr string, maxAddresses int) ([]string, error) {
+	ip, network, err := net.ParseCIDR(cidr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid range: %w", err)
+	}
+
+	ones, bits := network.Mask.Size()
+	if bits-ones > 31 || (maxAddresses > 0 && 1<<uint(bits-ones) > maxAddresses) {
+		return nil, fmt.Errorf("invalid range %s: more than %d addresses", cidr, maxAddresses)
+	}
+
+	if v4 := ip.To4(); v4 != nil {
+		ip = v4
+	}
+	ip = ip.Mask(network.Mask)
+
+	var hosts []string
+	for current := dup(ip); network.Contains(current); increment(current) {
+		hosts = append(hosts, current.String())
+	}
+
+	if len(ip) == net.IPv4len && bits-ones > 1 {
+		hosts = hosts[1 : len(hosts)-1]
+	}
+
+	return hosts, nil
+}
+
+func increment(ip net.IP) {
+	for i := len(ip) - 1; i >= 0; i-- {
+		ip[i]++
+		if ip[i] != 0 {
+			return
+		}
+	}
+}
+
+func dup(ip net.IP) net.IP {
+	return append(net.IP{}, ip...)
+}
+
+// compareHosts orders addresses numerically
+func compareHosts(a, b string) bool {
+	ipA, ipB := net.ParseIP(a), net.ParseIP(b)
+	if ipA == nil || ipB == nil {
+		return a < b
+	}
+	return string(ipA.To16()) < string(ipB.To16())
+}
+
+func copyJob(job *Job) Job {
+	jobCopy := *job
+	jobCopy.Found = append(make([]BMC, 0, len(job.Found)), job.Found...)
+	return jobCopy
+}
+
+func newID() (string, error) {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate an ID: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
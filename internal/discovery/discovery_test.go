@@ -0,0 +1,154 @@
+package discovery
+
+import (
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/bmc-toolbox/actor/internal/registry"
+)
+
+type testProber struct{}
+
+func (testProber) Probe(host string) *BMC {
+	switch host {
+	case "10.0.0.2":
+		return &BMC{Host: host, Type: "host", Vendor: "Dell", HardwareType: "idrac9", Serial: "ABC123", CredentialsValid: true}
+	case "10.0.0.5":
+		return &BMC{Host: host, Type: "chassis", Vendor: "HP", HardwareType: "c7000"}
+	}
+	return nil
+}
+
+func TestHosts(t *testing.T) {
+	tests := []struct {
+		cidr      string
+		wantLen   int
+		wantFirst string
+		wantLast  string
+		wantErr   bool
+	}{
+		{cidr: "10.0.0.0/30", wantLen: 2, wantFirst: "10.0.0.1", wantLast: "10.0.0.2"},
+		{cidr: "10.0.0.5/31", wantLen: 2, wantFirst: "10.0.0.4", wantLast: "10.0.0.5"},
+		{cidr: "10.0.0.7/32", wantLen: 1, wantFirst: "10.0.0.7", wantLast: "10.0.0.7"},
+		{cidr: "10.0.0.255/23", wantLen: 510, wantFirst: "10.0.0.1", wantLast: "10.0.1.254"},
+		{cidr: "10.0.0.0/16", wantErr: true},
+		{cidr: "10.0.0.0", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.cidr, func(t *testing.T) {
+			got, err := Hosts(tt.cidr, 1024)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Hosts() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if len(got) != tt.wantLen || got[0] != tt.wantFirst || got[len(got)-1] != tt.wantLast {
+				t.Errorf("Hosts() = %d addresses from %s to %s, want %d from %s to %s",
+					len(got), got[0], got[len(got)-1], tt.wantLen, tt.wantFirst, tt.wantLast)
+			}
+		})
+	}
+}
+
+func TestScanner(t *testing.T) {
+	dir, err := ioutil.TempDir("", "discovery")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	fleet, err := registry.New(filepath.Join(dir, "targets.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := fleet.Put(registry.Target{Host: "10.0.0.5", Type: "chassis", Labels: map[string]string{"rack": "r1"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	scanner := New(testProber{}, fleet, 4, 0, 256)
+
+	if _, err := scanner.Start("10.0.0.0/16", false, nil); err == nil {
+		t.Errorf("Start() accepted a range larger than the maximum")
+	}
+	if _, err := scanner.Start("10.0.0.0/29", true, map[string]string{"site": "ams 1"}); err == nil {
+		t.Errorf("Start() accepted a label selectors can't match")
+	}
+
+	job, err := scanner.Start("10.0.0.0/29", true, map[string]string{"site": "ams1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	scanner.Wait()
+
+	job, err = scanner.Get(job.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if job.Status != StatusDone || job.Total != 6 || job.Scanned != 6 {
+		t.Errorf("Get() = %s %d/%d, want done 6/6", job.Status, job.Scanned, job.Total)
+	}
+	if len(job.Found) != 2 || job.Found[0].Host != "10.0.0.2" || !job.Found[0].Registered || !job.Found[1].Registered {
+		t.Fatalf("Get() found %+v, want 10.0.0.2 and 10.0.0.5 registered", job.Found)
+	}
+
+	target, err := fleet.Get("10.0.0.2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := map[string]string{"site": "ams1", "vendor": "dell"}; !reflect.DeepEqual(target.Labels, want) {
+		t.Errorf("registered labels = %v, want %v", target.Labels, want)
+	}
+	// registered targets are left as they are
+	if target, _ := fleet.Get("10.0.0.5"); target.Labels["rack"] != "r1" || target.Labels["vendor"] != "" {
+		t.Errorf("labels of the registered chassis = %v, want them unchanged", target.Labels)
+	}
+
+	if _, err := New(testProber{}, nil, 1, 0, 256).Start("10.0.0.0/30", true, nil); err == nil {
+		t.Errorf("Start() accepted to register without a registry")
+	}
+}
+
+func TestPing(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = conn.Close() }()
+
+	go func() {
+		buf := make([]byte, 64)
+		for {
+			n, addr, err := conn.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+			if n != len(presencePing) || buf[8] != 0x80 {
+				continue
+			}
+			pong := append(append([]byte{}, buf[:8]...), asfMessagePresencePong, 0x00, 0x00, 0x10)
+			pong = append(pong, make([]byte, 16)...)
+			_, _ = conn.WriteTo(pong, addr)
+		}
+	}()
+
+	if !ping(conn.LocalAddr().String(), time.Second) {
+		t.Errorf("ping() = false, want the Presence Pong to be recognized")
+	}
+
+	silent, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = silent.Close() }()
+
+	if ping(silent.LocalAddr().String(), 50*time.Millisecond) {
+		t.Errorf("ping() = true for an address which doesn't answer")
+	}
+}
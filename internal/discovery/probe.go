@@ -0,0 +1,58 @@
+package discovery
+
+import (
+	"net"
+	"time"
+)
+
+const (
+	rmcpPort = "623"
+
+	asfMessagePresencePong = 0x40
+)
+
+// presencePing is an RMCP ASF Presence Ping, every IPMI over LAN BMC answers it without credentials
+var presencePing = []byte{
+	0x06, 0x00, 0xff, 0x06, // RMCP version 1.0, no acknowledgement, class ASF
+	0x00, 0x00, 0x11, 0xbe, // ASF IANA enterprise number
+	0x80, 0x00, 0x00, 0x00, // Presence Ping, message tag, reserved, no data
+}
+
+// Ping tells whether an IPMI BMC answers the Presence Ping on the host within the timeout
+func Ping(host string, timeout time.Duration) bool {
+	return ping(net.JoinHostPort(host, rmcpPort), timeout)
+}
+
+func ping(address string, timeout time.Duration) bool {
+	conn, err := net.DialTimeout("udp", address, timeout)
+	if err != nil {
+		return false
+	}
+	defer func() { _ = conn.Close() }()
+
+	if err := conn.SetDeadline(time.Now().Add(timeout)); err != nil {
+		return false
+	}
+	if _, err := conn.Write(presencePing); err != nil {
+		return false
+	}
+
+	buf := make([]byte, 64)
+	n, err := conn.Read(buf)
+	if err != nil {
+		return false
+	}
+
+	return n > 8 && buf[3] == presencePing[3] && buf[8] == asfMessagePresencePong
+}
+
+// ListensHTTPS tells whether the host accepts connections on the HTTPS port within the timeout,
+// the vendor probes are only worth trying when it does
+func ListensHTTPS(host string, timeout time.Duration) bool {
+	conn, err := net.DialTimeout("tcp", net.JoinHostPort(host, "443"), timeout)
+	if err != nil {
+		return false
+	}
+	_ = conn.Close()
+	return true
+}
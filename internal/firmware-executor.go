@@ -0,0 +1,155 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/bmc-toolbox/actor/internal/actions"
+	"github.com/bmc-toolbox/actor/internal/firmware"
+	"github.com/bmc-toolbox/actor/internal/providers"
+)
+
+const updateFirmwarePrefix = "updatefirmware "
+
+type (
+	FirmwareExecutorFactory struct {
+		updater *firmware.Updater
+	}
+
+	// FirmwareExecutor starts firmware updates, the updates run in the background and are reported by FirmwareReader
+	FirmwareExecutor struct {
+		updater *firmware.Updater
+		host    string
+	}
+
+	// FirmwareReader reads the firmware version of hosts and chassis and their last update
+	FirmwareReader struct {
+		username  string
+		passwords passwordSource
+		hosts     *firmware.Updater
+		chassis   *firmware.Updater
+	}
+
+	// chassisFirmwareProvider adapts the chassis BMC wrapper, whose Close takes no context, to firmware.Provider
+	chassisFirmwareProvider struct {
+		*providers.ChassisBmcWrapper
+	}
+)
+
+// NewFirmwareConnector connects the firmware updater to the BMC of a host
+func NewFirmwareConnector(username string, passwords passwordSource) func(host string) firmware.Provider {
+	return func(host string) firmware.Provider {
+		return providers.NewServerBmcWrapper(username, passwords.Password(host), host)
+	}
+}
+
+// NewChassisFirmwareConnector connects the firmware updater to the BMC of a chassis
+func NewChassisFirmwareConnector(username string, passwords passwordSource) func(host string) firmware.Provider {
+	return func(host string) firmware.Provider {
+		return chassisFirmwareProvider{providers.NewChassisBmcWrapper(username, passwords.Password(host), host)}
+	}
+}
+
+func (p chassisFirmwareProvider) Close(context.Context) error {
+	return p.ChassisBmcWrapper.Close()
+}
+
+func NewFirmwareExecutorFactory(updater *firmware.Updater) *FirmwareExecutorFactory {
+	return &FirmwareExecutorFactory{updater: updater}
+}
+
+func (f *FirmwareExecutorFactory) New(params map[string]interface{}) (actions.Executor, error) {
+	if err := validateParam(params, actions.ParamHost); err != nil {
+		return nil, fmt.Errorf("failed to create a new executor: %w", err)
+	}
+
+	return &FirmwareExecutor{updater: f.updater, host: fmt.Sprintf("%v", params[actions.ParamHost])}, nil
+}
+
+func (e *FirmwareExecutor) Validate(action string) error {
+	_, _, err := parseUpdateFirmware(action)
+	return err
+}
+
+func (e *FirmwareExecutor) Run(action string) actions.ActionResult {
+	artifact, version, err := parseUpdateFirmware(action)
+	if err != nil {
+		return actions.NewActionResult(action, false, "failed", err)
+	}
+
+	job, err := e.updater.Start(e.host, artifact, version)
+	if err != nil {
+		return actions.NewActionResult(action, false, "failed", err)
+	}
+
+	return actions.NewActionResult(action, true, fmt.Sprintf("firmware update %s started", job.ID), nil)
+}
+
+func (e *FirmwareExecutor) Capabilities() (actions.Capabilities, error) {
+	return actions.Capabilities{Actions: []string{actions.UpdateFirmware}}, nil
+}
+
+func (e *FirmwareExecutor) Cleanup() {
+}
+
+// parseUpdateFirmware parses "updatefirmware <artifact> [version]"
+func parseUpdateFirmware(action string) (artifact, version string, err error) {
+	if !strings.HasPrefix(action, updateFirmwarePrefix) {
+		return "", "", fmt.Errorf("%q is not a firmware update action", action)
+	}
+
+	args := strings.Fields(strings.TrimPrefix(action, updateFirmwarePrefix))
+	switch len(args) {
+	case 1:
+		return args[0], "", nil
+	case 2:
+		return args[0], args[1], nil
+	}
+
+	return "", "", fmt.Errorf("invalid action %q: an artifact and an optional version are expected", action)
+}
+
+func NewFirmwareReader(username string, passwords passwordSource, hosts, chassis *firmware.Updater) *FirmwareReader {
+	return &FirmwareReader{username: username, passwords: passwords, hosts: hosts, chassis: chassis}
+}
+
+// HostFirmware returns the firmware version of the host, which is empty behind the IPMI fallback, and its last update
+func (r *FirmwareReader) HostFirmware(host string) (firmware.Status, error) {
+	bmc := providers.NewServerBmcWrapper(r.username, r.passwords.Password(host), host)
+	defer func() { _ = bmc.Close(context.TODO()) }()
+
+	info, err := bmc.Info()
+	if err != nil {
+		return firmware.Status{}, err
+	}
+
+	return newFirmwareStatus(info, r.hosts, host), nil
+}
+
+// ChassisFirmware returns the firmware version of the chassis and its last update
+func (r *FirmwareReader) ChassisFirmware(host string) (firmware.Status, error) {
+	bmc := providers.NewChassisBmcWrapper(r.username, r.passwords.Password(host), host)
+	defer func() { _ = bmc.Close() }()
+
+	info, err := bmc.Info()
+	if err != nil {
+		return firmware.Status{}, err
+	}
+
+	return newFirmwareStatus(info, r.chassis, host), nil
+}
+
+func newFirmwareStatus(info providers.BmcInfo, updater *firmware.Updater, host string) firmware.Status {
+	status := firmware.Status{
+		Provider:     info.Provider,
+		Vendor:       info.Vendor,
+		HardwareType: info.HardwareType,
+		Version:      info.FirmwareVersion,
+	}
+	if job, ok := updater.Job(host); ok {
+		status.Update = &job
+	}
+
+	return status
+}
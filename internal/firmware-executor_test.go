@@ -0,0 +1,37 @@
+package internal
+
+import (
+	"testing"
+
+	"github.com/bmc-toolbox/actor/internal/actions"
+)
+
+func Test_parseUpdateFirmware(t *testing.T) {
+	tests := []struct {
+		name         string
+		action       string
+		wantArtifact string
+		wantVersion  string
+		wantErr      bool
+	}{
+		{name: "Artifact", action: "updatefirmware idrac.bin", wantArtifact: "idrac.bin"},
+		{name: "Artifact and version", action: "updatefirmware http://files/idrac.exe 4.40.00.00", wantArtifact: "http://files/idrac.exe", wantVersion: "4.40.00.00"},
+		{name: "No artifact", action: "updatefirmware ", wantErr: true},
+		{name: "Too many arguments", action: "updatefirmware a b c", wantErr: true},
+		{name: "Other action", action: "poweron", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			artifact, version, err := parseUpdateFirmware(tt.action)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseUpdateFirmware() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if artifact != tt.wantArtifact || version != tt.wantVersion {
+				t.Errorf("parseUpdateFirmware() = %q, %q, want %q, %q", artifact, version, tt.wantArtifact, tt.wantVersion)
+			}
+			if !tt.wantErr && !actions.IsPrivileged(tt.action) {
+				t.Errorf("%q should be privileged", tt.action)
+			}
+		})
+	}
+}
@@ -0,0 +1,171 @@
+package firmware
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// ErrNotFound is returned for unknown artifacts
+var ErrNotFound = errors.New("firmware artifact not found")
+
+// validName keeps artifact names usable as file names and in the URLs handed to the BMCs
+var validName = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+type (
+	// Artifact is a firmware image uploaded to actor
+	Artifact struct {
+		Name     string    `json:"name"`
+		Size     int64     `json:"size"`
+		SHA256   string    `json:"sha256"`
+		Uploaded time.Time `json:"uploaded"`
+	}
+
+	// Artifacts keeps the uploaded firmware images in a directory the BMCs download from under url,
+	// images on other file servers may only be referenced under one of sources
+	Artifacts struct {
+		dir     string
+		url     string
+		sources []string
+	}
+)
+
+func NewArtifacts(dir, url string, sources []string) (*Artifacts, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create firmware storage %s: %w", dir, err)
+	}
+
+	return &Artifacts{dir: dir, url: strings.TrimSuffix(url, "/"), sources: sources}, nil
+}
+
+// Save stores the artifact under name, replacing an artifact with the same name
+func (a *Artifacts) Save(name string, r io.Reader) (Artifact, error) {
+	if !validName.MatchString(name) {
+		return Artifact{}, fmt.Errorf("invalid artifact name %q", name)
+	}
+
+	tmp, err := ioutil.TempFile(a.dir, ".upload-")
+	if err != nil {
+		return Artifact{}, err
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+
+	hash := sha256.New()
+	size, err := io.Copy(io.MultiWriter(tmp, hash), r)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return Artifact{}, fmt.Errorf("failed to store artifact %s: %w", name, err)
+	}
+
+	if err := os.Chmod(tmp.Name(), 0644); err != nil {
+		return Artifact{}, err
+	}
+	if err := os.Rename(tmp.Name(), filepath.Join(a.dir, name)); err != nil {
+		return Artifact{}, err
+	}
+
+	return Artifact{Name: name, Size: size, SHA256: hex.EncodeToString(hash.Sum(nil)), Uploaded: time.Now()}, nil
+}
+
+// List returns the uploaded artifacts
+func (a *Artifacts) List() ([]Artifact, error) {
+	entries, err := ioutil.ReadDir(a.dir)
+	if err != nil {
+		return nil, err
+	}
+
+	artifacts := make([]Artifact, 0)
+	for _, entry := range entries {
+		if entry.IsDir() || !validName.MatchString(entry.Name()) {
+			continue
+		}
+
+		sum, err := a.checksum(entry.Name())
+		if err != nil {
+			return nil, err
+		}
+		artifacts = append(artifacts, Artifact{Name: entry.Name(), Size: entry.Size(), SHA256: sum, Uploaded: entry.ModTime()})
+	}
+
+	return artifacts, nil
+}
+
+// Resolve splits an artifact, the name of an uploaded image or the URL of an image on an allowed file server,
+// into the source URL and the file name expected by the BMCs
+func (a *Artifacts) Resolve(artifact string) (source, file string, err error) {
+	if !strings.Contains(artifact, "://") {
+		if !validName.MatchString(artifact) {
+			return "", "", fmt.Errorf("invalid artifact name %q", artifact)
+		}
+		if _, err := os.Stat(filepath.Join(a.dir, artifact)); err != nil {
+			return "", "", fmt.Errorf("%w: %s", ErrNotFound, artifact)
+		}
+		if a.url == "" {
+			return "", "", fmt.Errorf("no URL is configured for the uploaded artifacts")
+		}
+		return a.url, artifact, nil
+	}
+
+	u, err := url.Parse(artifact)
+	if err != nil {
+		return "", "", fmt.Errorf("invalid artifact URL %q: %w", artifact, err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return "", "", fmt.Errorf("invalid artifact URL %q: only http and https are supported", artifact)
+	}
+
+	for _, allowed := range a.sources {
+		if isUnder(u, allowed) {
+			dir, file := path.Split(path.Clean(u.Path))
+			u.Path, u.RawPath = strings.TrimSuffix(dir, "/"), ""
+			return u.String(), file, nil
+		}
+	}
+
+	return "", "", fmt.Errorf("artifact URL %q is not under an allowed source", artifact)
+}
+
+// isUnder tells whether the URL is on the server of the source, scheme and host alike, and its cleaned path
+// is within the path of the source, so neither ".." nor a longer host name gets around the source
+func isUnder(u *url.URL, source string) bool {
+	allowed, err := url.Parse(source)
+	if err != nil || u.User != nil {
+		return false
+	}
+	if !strings.EqualFold(u.Scheme, allowed.Scheme) || !strings.EqualFold(u.Host, allowed.Host) {
+		return false
+	}
+
+	dir := path.Clean("/" + allowed.Path)
+	if dir != "/" {
+		dir += "/"
+	}
+	return strings.HasPrefix(path.Clean("/"+u.Path), dir)
+}
+
+func (a *Artifacts) checksum(name string) (string, error) {
+	f, err := os.Open(filepath.Join(a.dir, name))
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = f.Close() }()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, f); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
@@ -0,0 +1,241 @@
+package firmware
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"path"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	StatusRunning   = "running"
+	StatusVerifying = "verifying"
+	StatusDone      = "done"
+	StatusFailed    = "failed"
+
+	defaultVerifyTimeout  = 30 * time.Minute
+	defaultVerifyInterval = time.Minute
+)
+
+type (
+	// AllowList maps hardware types to the patterns (see path.Match) of the artifact files they may be updated with,
+	// hardware types which aren't listed can't be updated
+	AllowList map[string][]string
+
+	// Provider is the part of a BMC connection needed to update its firmware
+	Provider interface {
+		Connect() error
+		HardwareType() string
+		UpdateFirmware(source, file string) (bool, string, error)
+		CheckFirmwareVersion() (string, error)
+		Close(context.Context) error
+	}
+
+	// Job is a firmware update of a host, Version is read after the update and must match ExpectedVersion when set
+	Job struct {
+		ID              string     `json:"id"`
+		Host            string     `json:"host"`
+		Artifact        string     `json:"artifact"`
+		HardwareType    string     `json:"hardware-type"`
+		ExpectedVersion string     `json:"expected-version,omitempty"`
+		Version         string     `json:"version,omitempty"`
+		Status          string     `json:"status"`
+		Output          string     `json:"output,omitempty"`
+		Error           string     `json:"error,omitempty"`
+		Started         time.Time  `json:"started"`
+		Finished        *time.Time `json:"finished,omitempty"`
+	}
+
+	// Status describes the firmware of a host and its last update
+	Status struct {
+		Provider     string `json:"provider"`
+		Vendor       string `json:"vendor"`
+		HardwareType string `json:"hardware-type"`
+		Version      string `json:"version"`
+		Update       *Job   `json:"update"`
+	}
+
+	// Updater runs firmware updates in the background, one at a time per host, and keeps the last job of every host
+	Updater struct {
+		artifacts      *Artifacts
+		allowList      AllowList
+		connect        func(host string) Provider
+		verifyTimeout  time.Duration
+		verifyInterval time.Duration
+
+		mu   sync.Mutex
+		jobs map[string]*Job
+	}
+)
+
+// Allows tells whether the hardware type may be updated with the artifact file
+func (l AllowList) Allows(hardwareType, file string) bool {
+	for _, pattern := range l[hardwareType] {
+		if ok, err := path.Match(pattern, file); err == nil && ok {
+			return true
+		}
+	}
+	return false
+}
+
+func NewUpdater(artifacts *Artifacts, allowList AllowList, connect func(host string) Provider, verifyTimeout, verifyInterval time.Duration) *Updater {
+	if verifyTimeout <= 0 {
+		verifyTimeout = defaultVerifyTimeout
+	}
+	if verifyInterval <= 0 {
+		verifyInterval = defaultVerifyInterval
+	}
+
+	return &Updater{
+		artifacts:      artifacts,
+		allowList:      allowList,
+		connect:        connect,
+		verifyTimeout:  verifyTimeout,
+		verifyInterval: verifyInterval,
+		jobs:           make(map[string]*Job),
+	}
+}
+
+// Start checks the artifact against the allow-list of the host hardware type and starts the update,
+// the new version is verified afterwards when expectedVersion is set
+func (u *Updater) Start(host, artifact, expectedVersion string) (Job, error) {
+	if job, ok := u.Job(host); ok && job.Finished == nil {
+		return Job{}, fmt.Errorf("firmware update %s is already running", job.ID)
+	}
+
+	source, file, err := u.artifacts.Resolve(artifact)
+	if err != nil {
+		return Job{}, err
+	}
+
+	bmc := u.connect(host)
+	if err := bmc.Connect(); err != nil {
+		_ = bmc.Close(context.TODO())
+		return Job{}, fmt.Errorf("failed to connect to %s: %w", host, err)
+	}
+
+	hardwareType := bmc.HardwareType()
+	if !u.allowList.Allows(hardwareType, file) {
+		_ = bmc.Close(context.TODO())
+		return Job{}, fmt.Errorf("artifact %s is not allowed for hardware type %s", file, hardwareType)
+	}
+
+	job := &Job{
+		ID:              newJobID(),
+		Host:            host,
+		Artifact:        artifact,
+		HardwareType:    hardwareType,
+		ExpectedVersion: expectedVersion,
+		Status:          StatusRunning,
+		Started:         time.Now(),
+	}
+
+	u.mu.Lock()
+	if running, ok := u.jobs[host]; ok && running.Finished == nil {
+		u.mu.Unlock()
+		_ = bmc.Close(context.TODO())
+		return Job{}, fmt.Errorf("firmware update %s is already running", running.ID)
+	}
+	u.jobs[host] = job
+	started := *job
+	u.mu.Unlock()
+
+	go u.run(job, bmc, source, file)
+
+	return started, nil
+}
+
+// Job returns the last firmware update of the host
+func (u *Updater) Job(host string) (Job, bool) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	job, ok := u.jobs[host]
+	if !ok {
+		return Job{}, false
+	}
+	return *job, true
+}
+
+func (u *Updater) run(job *Job, bmc Provider, source, file string) {
+	logger := log.WithFields(log.Fields{"job": job.ID, "ip": job.Host, "artifact": job.Artifact})
+
+	_, output, err := bmc.UpdateFirmware(source, file)
+	_ = bmc.Close(context.TODO())
+	u.update(job, func(j *Job) { j.Output = output })
+	if err != nil {
+		logger.WithError(err).Error("firmware update failed")
+		u.finish(job, "", err)
+		return
+	}
+
+	if job.ExpectedVersion == "" {
+		logger.Info("firmware updated")
+		u.finish(job, "", nil)
+		return
+	}
+
+	u.update(job, func(j *Job) { j.Status = StatusVerifying })
+	version, err := u.verify(job.Host, job.ExpectedVersion)
+	if err != nil {
+		logger.WithError(err).Error("firmware verification failed")
+	} else {
+		logger.Info("firmware updated and verified")
+	}
+	u.finish(job, version, err)
+}
+
+// verify polls the firmware version until it matches, the BMC resets after an update so every check reconnects
+func (u *Updater) verify(host, expectedVersion string) (string, error) {
+	deadline := time.Now().Add(u.verifyTimeout)
+
+	var version string
+	var err error
+	for time.Now().Before(deadline) {
+		time.Sleep(u.verifyInterval)
+
+		bmc := u.connect(host)
+		version, err = bmc.CheckFirmwareVersion()
+		_ = bmc.Close(context.TODO())
+
+		if err == nil && version == expectedVersion {
+			return version, nil
+		}
+	}
+
+	if err != nil {
+		return version, fmt.Errorf("failed to read the firmware version within %s: %w", u.verifyTimeout, err)
+	}
+	return version, fmt.Errorf("firmware version is %s after %s, expected %s", version, u.verifyTimeout, expectedVersion)
+}
+
+func (u *Updater) finish(job *Job, version string, err error) {
+	u.update(job, func(j *Job) {
+		now := time.Now()
+		j.Finished = &now
+		j.Version = version
+		j.Status = StatusDone
+		if err != nil {
+			j.Status = StatusFailed
+			j.Error = err.Error()
+		}
+	})
+}
+
+func (u *Updater) update(job *Job, fn func(*Job)) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	fn(job)
+}
+
+func newJobID() string {
+	b := make([]byte, 8)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
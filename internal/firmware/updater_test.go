@@ -0,0 +1,258 @@
+package firmware
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+type testProvider struct {
+	mu       sync.Mutex
+	version  string
+	updated  string
+	fail     error
+	newValue string
+	down     error
+}
+
+func (p *testProvider) Connect() error {
+	return p.down
+}
+
+func (p *testProvider) HardwareType() string {
+	return "idrac9"
+}
+
+func (p *testProvider) UpdateFirmware(source, file string) (bool, string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.fail != nil {
+		return false, "", p.fail
+	}
+	p.updated = source + "/" + file
+	p.version = p.newValue
+	return true, "Firmware update completed successfully", nil
+}
+
+func (p *testProvider) CheckFirmwareVersion() (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.version, nil
+}
+
+func (p *testProvider) Close(context.Context) error {
+	return nil
+}
+
+func newTestArtifacts(t *testing.T) *Artifacts {
+	dir, err := ioutil.TempDir("", "firmware")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = os.RemoveAll(dir) })
+
+	artifacts, err := NewArtifacts(dir, "http://actor/firmware/files/", []string{"http://files/firmware/"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return artifacts
+}
+
+func waitFinished(t *testing.T, u *Updater, host string) Job {
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		if job, ok := u.Job(host); ok && job.Finished != nil {
+			return job
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatal("the firmware update didn't finish")
+	return Job{}
+}
+
+func TestArtifacts(t *testing.T) {
+	artifacts := newTestArtifacts(t)
+
+	artifact, err := artifacts.Save("idrac-4.40.bin", strings.NewReader("firmware"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if artifact.Size != 8 || len(artifact.SHA256) != 64 {
+		t.Errorf("Save() = %+v", artifact)
+	}
+	if _, err := os.Stat(filepath.Join(artifacts.dir, "idrac-4.40.bin")); err != nil {
+		t.Errorf("Save() didn't store the artifact: %v", err)
+	}
+	if _, err := artifacts.Save("../escape.bin", strings.NewReader("firmware")); err == nil {
+		t.Errorf("Save() accepted an invalid name")
+	}
+
+	list, err := artifacts.List()
+	if err != nil || len(list) != 1 || list[0].SHA256 != artifact.SHA256 {
+		t.Errorf("List() = %+v, %v", list, err)
+	}
+
+	tests := []struct {
+		artifact   string
+		wantSource string
+		wantFile   string
+		wantErr    error
+	}{
+		{artifact: "idrac-4.40.bin", wantSource: "http://actor/firmware/files", wantFile: "idrac-4.40.bin"},
+		{artifact: "missing.bin", wantErr: ErrNotFound},
+		{artifact: "http://files/firmware/dell/idrac.exe", wantSource: "http://files/firmware/dell", wantFile: "idrac.exe"},
+		{artifact: "http://elsewhere/firmware/idrac.exe", wantErr: errors.New("not allowed")},
+		{artifact: "ftp://files/firmware/idrac.exe", wantErr: errors.New("not supported")},
+		{artifact: "http://files/firmware/../../x/evil.bin", wantErr: errors.New("not allowed")},
+		{artifact: "http://files/firmware/%2e%2e/x/evil.bin", wantErr: errors.New("not allowed")},
+		{artifact: "http://files/firmware-evil/idrac.exe", wantErr: errors.New("not allowed")},
+		{artifact: "https://files/firmware/idrac.exe", wantErr: errors.New("not allowed")},
+		{artifact: "http://user@files/firmware/idrac.exe", wantErr: errors.New("not allowed")},
+		{artifact: "http://files/firmware/dell/./../hp/ilo.bin", wantSource: "http://files/firmware/hp", wantFile: "ilo.bin"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.artifact, func(t *testing.T) {
+			source, file, err := artifacts.Resolve(tt.artifact)
+			if (err != nil) != (tt.wantErr != nil) {
+				t.Fatalf("Resolve() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if errors.Is(tt.wantErr, ErrNotFound) && !errors.Is(err, ErrNotFound) {
+				t.Errorf("Resolve() error = %v, want %v", err, ErrNotFound)
+			}
+			if source != tt.wantSource || file != tt.wantFile {
+				t.Errorf("Resolve() = %s, %s, want %s, %s", source, file, tt.wantSource, tt.wantFile)
+			}
+		})
+	}
+}
+
+func TestArtifacts_ResolveSourceWithoutSlash(t *testing.T) {
+	artifacts := &Artifacts{sources: []string{"http://files.example.com/firmware"}}
+
+	tests := []struct {
+		artifact string
+		wantErr  bool
+	}{
+		{artifact: "http://files.example.com/firmware/idrac.exe"},
+		{artifact: "http://files.example.com.evil.net/firmware/idrac.exe", wantErr: true},
+		{artifact: "http://files.example.com/firmwares/idrac.exe", wantErr: true},
+		{artifact: "http://files.example.com:8080/firmware/idrac.exe", wantErr: true},
+		{artifact: "http://files.example.com/firmware/../../x/evil.bin", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.artifact, func(t *testing.T) {
+			if _, _, err := artifacts.Resolve(tt.artifact); (err != nil) != tt.wantErr {
+				t.Errorf("Resolve() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestAllowList_Allows(t *testing.T) {
+	allowList := AllowList{"idrac9": {"iDRAC_*.EXE"}}
+
+	if !allowList.Allows("idrac9", "iDRAC_4.40.EXE") {
+		t.Errorf("Allows() should allow a matching artifact")
+	}
+	if allowList.Allows("idrac9", "ilo5_2.44.bin") {
+		t.Errorf("Allows() should refuse an artifact which doesn't match")
+	}
+	if allowList.Allows("ilo5", "iDRAC_4.40.EXE") {
+		t.Errorf("Allows() should refuse hardware types which aren't listed")
+	}
+}
+
+func TestUpdater_Start(t *testing.T) {
+	artifacts := newTestArtifacts(t)
+	if _, err := artifacts.Save("idrac.bin", strings.NewReader("firmware")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := artifacts.Save("other.bin", strings.NewReader("firmware")); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name          string
+		artifact      string
+		version       string
+		provider      *testProvider
+		wantStartErr  bool
+		wantStatus    string
+		wantUpdatedTo string
+	}{
+		{
+			name:          "Verified",
+			artifact:      "idrac.bin",
+			version:       "4.40",
+			provider:      &testProvider{version: "4.30", newValue: "4.40"},
+			wantStatus:    StatusDone,
+			wantUpdatedTo: "http://actor/firmware/files/idrac.bin",
+		},
+		{
+			name:          "Unverified",
+			artifact:      "idrac.bin",
+			provider:      &testProvider{version: "4.30", newValue: "4.40"},
+			wantStatus:    StatusDone,
+			wantUpdatedTo: "http://actor/firmware/files/idrac.bin",
+		},
+		{
+			name:          "Wrong version",
+			artifact:      "idrac.bin",
+			version:       "5.00",
+			provider:      &testProvider{version: "4.30", newValue: "4.40"},
+			wantStatus:    StatusFailed,
+			wantUpdatedTo: "http://actor/firmware/files/idrac.bin",
+		},
+		{
+			name:       "Update failure",
+			artifact:   "idrac.bin",
+			provider:   &testProvider{fail: errors.New("no space left")},
+			wantStatus: StatusFailed,
+		},
+		{
+			name:         "Unreachable",
+			artifact:     "idrac.bin",
+			provider:     &testProvider{down: errors.New("connection refused")},
+			wantStartErr: true,
+		},
+		{
+			name:         "Not allowed",
+			artifact:     "other.bin",
+			provider:     &testProvider{},
+			wantStartErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			connect := func(string) Provider { return tt.provider }
+			u := NewUpdater(artifacts, AllowList{"idrac9": {"idrac*.bin"}}, connect, 20*time.Millisecond, time.Millisecond)
+
+			_, err := u.Start("host1", tt.artifact, tt.version)
+			if (err != nil) != tt.wantStartErr {
+				t.Fatalf("Start() error = %v, wantErr %v", err, tt.wantStartErr)
+			}
+			if tt.provider.down != nil && !errors.Is(err, tt.provider.down) {
+				t.Fatalf("Start() error = %v, want the connection error %v", err, tt.provider.down)
+			}
+			if tt.wantStartErr {
+				return
+			}
+
+			job := waitFinished(t, u, "host1")
+			if job.Status != tt.wantStatus {
+				t.Errorf("Start() finished with %+v, want status %s", job, tt.wantStatus)
+			}
+			if tt.provider.updated != tt.wantUpdatedTo {
+				t.Errorf("Start() updated from %q, want %q", tt.provider.updated, tt.wantUpdatedTo)
+			}
+		})
+	}
+}
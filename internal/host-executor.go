@@ -11,15 +11,17 @@ import (
 
 type (
 	HostExecutorFactory struct {
-		isS3Enabled bool
-		username    string
-		password    string
+		username        string
+		passwords       passwordSource
+		screenshotStore screenshot.Store
+		analyzer        *screenshot.Analyzer
 	}
 
 	hostExecutor struct {
-		bmc         bmcProvider
-		host        string
-		isS3Enabled bool
+		bmc             bmcProvider
+		host            string
+		screenshotStore screenshot.Store
+		analyzer        *screenshot.Analyzer
 	}
 
 	bmcProvider interface {
@@ -32,34 +34,39 @@ type (
 		PowerCycleBmc() (bool, error)
 
 		PxeOnce() (bool, error)
+		TempC() (int, error)
+		Info() (providers.BmcInfo, error)
 
-		// TODO: it looks like the screenshot's stuff shouldn't be in `hostExecutor`
-		Screenshot() ([]byte, string, error)
-		HardwareType() string
+		screenshotProvider
 	}
 )
 
-func NewHostExecutorFactory(username, password string, isS3Enabled bool) *HostExecutorFactory {
-	return &HostExecutorFactory{username: username, password: password, isS3Enabled: isS3Enabled}
+func NewHostExecutorFactory(username string, passwords passwordSource, screenshotStore screenshot.Store, analyzer *screenshot.Analyzer) *HostExecutorFactory {
+	return &HostExecutorFactory{username: username, passwords: passwords, screenshotStore: screenshotStore, analyzer: analyzer}
 }
 
 func (f *HostExecutorFactory) New(params map[string]interface{}) (actions.Executor, error) {
-	if err := validateParam(params, paramHost); err != nil {
+	if err := validateParam(params, actions.ParamHost); err != nil {
 		return nil, fmt.Errorf("failed to create a new executor: %w", err)
 	}
 
-	host := fmt.Sprintf("%v", params[paramHost])
+	host := fmt.Sprintf("%v", params[actions.ParamHost])
 
 	hostExecutor := &hostExecutor{
-		bmc:         providers.NewServerBmcWrapper(f.username, f.password, host),
-		host:        host,
-		isS3Enabled: f.isS3Enabled,
+		bmc:             providers.NewServerBmcWrapper(f.username, f.passwords.Password(host), host),
+		host:            host,
+		screenshotStore: f.screenshotStore,
+		analyzer:        f.analyzer,
 	}
 
 	return hostExecutor, nil
 }
 
 func (e *hostExecutor) Validate(action string) error {
+	if isRequireAction(action) {
+		return e.requirementProbes().validate(action)
+	}
+
 	_, err := e.matchServerActionToFn(action)
 	if err == nil {
 		return nil
@@ -73,6 +80,24 @@ func (e *hostExecutor) Run(action string) actions.ActionResult {
 	return e.doAction(action)
 }
 
+func (e *hostExecutor) Capabilities() (actions.Capabilities, error) {
+	info, err := e.bmc.Info()
+	if err != nil {
+		return actions.Capabilities{}, err
+	}
+
+	supported := actions.SupportedActions(func(action string) error {
+		_, err := e.matchServerActionToFn(action)
+		return err
+	})
+	if info.SupportsScreenshot {
+		supported = append(supported, actions.Screenshot, actions.ScreenshotAnalyze)
+	}
+	supported = append(supported, actions.Require)
+
+	return newCapabilities(supported, info), nil
+}
+
 func (e *hostExecutor) matchServerActionToFn(action string) (func() (bool, error), error) {
 	switch action {
 	case actions.IsOn:
@@ -93,21 +118,41 @@ func (e *hostExecutor) matchServerActionToFn(action string) (func() (bool, error
 }
 
 func (e *hostExecutor) matchScreenshotActionToFn(action string) (func() (string, bool, error), error) {
-	if action == actions.Screenshot {
+	switch action {
+	case actions.Screenshot:
 		return e.doScreenshot, nil
+	case actions.ScreenshotAnalyze:
+		return e.doScreenshotAnalyze, nil
 	}
 
 	return nil, fmt.Errorf("unknown action %q", action)
 }
 
 func (e *hostExecutor) doScreenshot() (string, bool, error) {
-	if e.isS3Enabled {
-		return screenshot.S3(e.bmc, e.host)
+	url, err := takeScreenshot(e.bmc, e.host, e.screenshotStore)
+	if err != nil {
+		return "", false, err
+	}
+	return url, true, nil
+}
+
+func (e *hostExecutor) doScreenshotAnalyze() (string, bool, error) {
+	message, err := analyzeScreenshot(e.bmc, e.host, e.screenshotStore, e.analyzer)
+	if err != nil {
+		return "", false, err
 	}
-	return screenshot.Local(e.bmc, e.host)
+	return message, true, nil
+}
+
+func (e *hostExecutor) requirementProbes() requirementProbes {
+	return requirementProbes{isOn: e.bmc.IsOn, tempC: e.bmc.TempC}
 }
 
 func (e *hostExecutor) doAction(action string) actions.ActionResult {
+	if isRequireAction(action) {
+		return e.requirementProbes().check(action)
+	}
+
 	serverFn, err := e.matchServerActionToFn(action)
 	if err == nil {
 		return e.doServerFn(action, serverFn)
@@ -25,6 +25,59 @@ func (w *baseChassisBladeBmcWrapper) Close() error {
 	return nil
 }
 
+// Info describes the connected chassis BMC
+func (w *baseChassisBladeBmcWrapper) Info() (BmcInfo, error) {
+	if err := w.initBmcProvider(); err != nil {
+		return BmcInfo{}, err
+	}
+
+	// the firmware version is informational, a failure to read it shouldn't hide the rest
+	firmwareVersion, _ := w.bmc.CheckFirmwareVersion()
+
+	return BmcInfo{
+		Provider:        ProviderBmclib,
+		Vendor:          w.bmc.Vendor(),
+		HardwareType:    w.bmc.HardwareType(),
+		FirmwareVersion: firmwareVersion,
+	}, nil
+}
+
+// Configurer returns the bmclib configuration interfaces of the chassis, CmcSetup applies the one time chassis setup
+func (w *baseChassisBladeBmcWrapper) Configurer() (devices.Configure, devices.CmcSetup, error) {
+	if err := w.initBmcProvider(); err != nil {
+		return nil, nil, err
+	}
+	return w.bmc, w.bmc, nil
+}
+
+func (w *baseChassisBladeBmcWrapper) TempC() (int, error) {
+	if err := w.initBmcProvider(); err != nil {
+		return 0, err
+	}
+	return w.bmc.TempC()
+}
+
+func (w *baseChassisBladeBmcWrapper) IsPsuRedundant() (bool, error) {
+	if err := w.initBmcProvider(); err != nil {
+		return false, err
+	}
+	return w.bmc.IsPsuRedundant()
+}
+
+// Sensors returns the temperature, power draw, fans and power supplies of the chassis
+func (w *baseChassisBladeBmcWrapper) Sensors() ([]Sensor, error) {
+	if err := w.initBmcProvider(); err != nil {
+		return nil, err
+	}
+
+	return newBmclibSensors(w.bmc.TempC, w.bmc.PowerKw, w.bmc.Fans, w.bmc.Psus)
+}
+
+// Connect connects to the chassis BMC, every other method connects on its first call
+func (w *baseChassisBladeBmcWrapper) Connect() error {
+	return w.initBmcProvider()
+}
+
 func (w *baseChassisBladeBmcWrapper) initBmcProvider() error {
 	var err error
 
@@ -1,12 +1,15 @@
 package providers
 
-import "sync"
+import (
+	"fmt"
+
+	"github.com/bmc-toolbox/bmclib/devices"
+)
 
 type (
 	BladeBmcWrapper struct {
 		*baseChassisBladeBmcWrapper
-		bladeSerialToPos map[string]int
-		lock             sync.RWMutex
+		index *BladeIndex
 	}
 )
 
@@ -17,11 +20,16 @@ func NewBladeBmcWrapper(username, password, host string) *BladeBmcWrapper {
 			password: password,
 			host:     host,
 		},
-		bladeSerialToPos: make(map[string]int),
-		lock:             sync.RWMutex{},
+		index: NewBladeIndex(),
 	}
 }
 
+// WithIndex shares the index of blade positions with other wrappers
+func (w *BladeBmcWrapper) WithIndex(index *BladeIndex) *BladeBmcWrapper {
+	w.index = index
+	return w
+}
+
 func (w *BladeBmcWrapper) IsOnBlade(position int) (bool, error) {
 	if err := w.initBmcProvider(); err != nil {
 		return false, err
@@ -57,10 +65,13 @@ func (w *BladeBmcWrapper) PowerCycleBmcBlade(position int) (bool, error) {
 	return w.bmc.PowerCycleBmcBlade(position)
 }
 
+// ReseatBlade forgets the blade at the position, it may be replaced while it is out
 func (w *BladeBmcWrapper) ReseatBlade(position int) (bool, error) {
 	if err := w.initBmcProvider(); err != nil {
 		return false, err
 	}
+
+	w.index.InvalidatePosition(w.host, position)
 	return w.bmc.ReseatBlade(position)
 }
 
@@ -71,32 +82,118 @@ func (w *BladeBmcWrapper) PxeOnceBlade(position int) (bool, error) {
 	return w.bmc.PxeOnceBlade(position)
 }
 
-func (w *BladeBmcWrapper) FindBladePosition(serial string) (int, error) {
+func (w *BladeBmcWrapper) SetIpmiOverLan(position int, enable bool) (bool, error) {
 	if err := w.initBmcProvider(); err != nil {
-		return -1, err
+		return false, err
 	}
+	return w.bmc.SetIpmiOverLan(position, enable)
+}
 
-	w.lock.RLock()
-	if position, ok := w.bladeSerialToPos[serial]; ok {
-		w.lock.RUnlock()
-		return position, nil
+func (w *BladeBmcWrapper) SetFlexAddressState(position int, enable bool) (bool, error) {
+	if err := w.initBmcProvider(); err != nil {
+		return false, err
 	}
+	return w.bmc.SetFlexAddressState(position, enable)
+}
 
-	w.lock.RUnlock()
-
-	w.lock.Lock()
-	defer w.lock.Unlock()
-
-	if position, ok := w.bladeSerialToPos[serial]; ok {
+// FindBladePosition returns the position of the blade in the chassis, the index answers for the blades it knows
+// so a serial is only looked up once. A blade which moved since is forgotten by the executor when its action fails
+func (w *BladeBmcWrapper) FindBladePosition(serial string) (int, error) {
+	if position, ok := w.index.Position(w.host, serial); ok {
 		return position, nil
 	}
 
+	if err := w.initBmcProvider(); err != nil {
+		return -1, err
+	}
+
 	position, err := w.bmc.FindBladePosition(serial)
 	if err != nil {
 		return -1, err
 	}
 
-	w.bladeSerialToPos[serial] = position
+	w.index.Set(w.host, serial, position)
 
 	return position, nil
 }
+
+// ForgetBlade drops the blade from the index, so the chassis is asked again where it is
+func (w *BladeBmcWrapper) ForgetBlade(serial string) {
+	w.index.Invalidate(serial)
+}
+
+// ScanBlades lists the blades of the chassis and records them in the index
+func (w *BladeBmcWrapper) ScanBlades() ([]BladeLocation, error) {
+	blades, err := w.blades()
+	if err != nil {
+		return nil, err
+	}
+
+	locations := make([]BladeLocation, 0, len(blades))
+	for _, blade := range blades {
+		if location, ok := w.index.Lookup(blade.Serial); ok {
+			locations = append(locations, location)
+		}
+	}
+	return locations, nil
+}
+
+// BladeBmcAddress returns the address of the BMC of the blade as reported by the chassis
+func (w *BladeBmcWrapper) BladeBmcAddress(position int) (string, error) {
+	blades, err := w.blades()
+	if err != nil {
+		return "", err
+	}
+
+	for _, blade := range blades {
+		if blade.BladePosition != position {
+			continue
+		}
+		if blade.BmcAddress == "" {
+			return "", fmt.Errorf("the chassis doesn't report the BMC address of the blade at position %d", position)
+		}
+		return blade.BmcAddress, nil
+	}
+
+	return "", fmt.Errorf("no blade at position %d", position)
+}
+
+// AddBladeBmcAdmin creates an admin user on the BMCs of all the blades of the chassis
+func (w *BladeBmcWrapper) AddBladeBmcAdmin(username, password string) error {
+	if err := w.initBmcProvider(); err != nil {
+		return err
+	}
+	return w.bmc.AddBladeBmcAdmin(username, password)
+}
+
+// ModBladeBmcUser changes the password of a user on the BMCs of all the blades of the chassis
+func (w *BladeBmcWrapper) ModBladeBmcUser(username, password string) error {
+	if err := w.initBmcProvider(); err != nil {
+		return err
+	}
+	return w.bmc.ModBladeBmcUser(username, password)
+}
+
+// RemoveBladeBmcUser removes a user from the BMCs of all the blades of the chassis
+func (w *BladeBmcWrapper) RemoveBladeBmcUser(username string) error {
+	if err := w.initBmcProvider(); err != nil {
+		return err
+	}
+	return w.bmc.RemoveBladeBmcUser(username)
+}
+
+// blades lists the blades of the chassis, every list refreshes the index
+func (w *BladeBmcWrapper) blades() ([]*devices.Blade, error) {
+	if err := w.initBmcProvider(); err != nil {
+		return nil, err
+	}
+
+	blades, err := w.bmc.Blades()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list blades: %w", err)
+	}
+
+	w.index.Record(w.host, blades)
+
+	return blades, nil
+}
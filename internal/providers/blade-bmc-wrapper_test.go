@@ -0,0 +1,63 @@
+package providers
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/bmc-toolbox/bmclib/devices"
+)
+
+// testCmc is a chassis holding blades, only the calls the tests make are implemented
+type testCmc struct {
+	devices.Cmc
+	blades   []*devices.Blade
+	listings int
+	lookups  int
+}
+
+func (c *testCmc) Blades() ([]*devices.Blade, error) {
+	c.listings++
+	return c.blades, nil
+}
+
+func (c *testCmc) FindBladePosition(serial string) (int, error) {
+	c.lookups++
+	for _, blade := range c.blades {
+		if strings.EqualFold(blade.Serial, serial) {
+			return blade.BladePosition, nil
+		}
+	}
+	return -1, errors.New("blade not found")
+}
+
+func newTestBladeBmcWrapper(cmc *testCmc, index *BladeIndex) *BladeBmcWrapper {
+	w := NewBladeBmcWrapper("actor", "secret", "chassis-1").WithIndex(index)
+	w.bmc = cmc
+	w.initOnce.Do(func() {})
+	return w
+}
+
+func TestBladeBmcWrapper_FindBladePosition(t *testing.T) {
+	cmc := &testCmc{blades: []*devices.Blade{{Serial: "ABC123", BladePosition: 3}}}
+	w := newTestBladeBmcWrapper(cmc, NewBladeIndex())
+
+	if position, err := w.FindBladePosition("ABC123"); err != nil || position != 3 {
+		t.Fatalf("FindBladePosition() = %d, %v, want 3", position, err)
+	}
+
+	// the index answers without asking the chassis, even once the blade moved
+	cmc.blades = []*devices.Blade{{Serial: "ABC123", BladePosition: 5}}
+	if position, err := w.FindBladePosition("ABC123"); err != nil || position != 3 {
+		t.Fatalf("FindBladePosition() = %d, %v, want the indexed 3", position, err)
+	}
+	if cmc.lookups != 1 || cmc.listings != 0 {
+		t.Errorf("the chassis was asked %d times and listed %d times, want a single lookup", cmc.lookups, cmc.listings)
+	}
+
+	// once the action on the stale position failed the blade is forgotten and looked up again
+	w.ForgetBlade("ABC123")
+	if position, err := w.FindBladePosition("ABC123"); err != nil || position != 5 {
+		t.Errorf("FindBladePosition() = %d, %v, want 5 after the blade was forgotten", position, err)
+	}
+}
@@ -0,0 +1,125 @@
+package providers
+
+import (
+	"errors"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bmc-toolbox/bmclib/devices"
+)
+
+// ErrBladeNotFound is returned when no known chassis holds the blade
+var ErrBladeNotFound = errors.New("blade not found in any known chassis")
+
+type (
+	// BladeLocation is where a blade was last seen
+	BladeLocation struct {
+		Serial   string    `json:"serial"`
+		Chassis  string    `json:"chassis"`
+		Position int       `json:"position"`
+		Updated  time.Time `json:"updated"`
+	}
+
+	// BladeIndex maps blade serials to their chassis and position, it is shared by all requests
+	// so blades are only looked up again once their entry is invalidated or a scan of the chassis moves them
+	BladeIndex struct {
+		mu     sync.RWMutex
+		blades map[string]BladeLocation
+		// chassis lists every chassis seen, even empty ones, so they can be scanned for blades later
+		chassis map[string]bool
+	}
+)
+
+func NewBladeIndex() *BladeIndex {
+	return &BladeIndex{blades: make(map[string]BladeLocation), chassis: make(map[string]bool)}
+}
+
+func (i *BladeIndex) Lookup(serial string) (BladeLocation, bool) {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+
+	location, ok := i.blades[serialKey(serial)]
+	return location, ok
+}
+
+// Position returns the position of the blade when it was last seen in the chassis
+func (i *BladeIndex) Position(chassis, serial string) (int, bool) {
+	location, ok := i.Lookup(serial)
+	if !ok || location.Chassis != chassis {
+		return 0, false
+	}
+	return location.Position, true
+}
+
+func (i *BladeIndex) Set(chassis, serial string, position int) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	i.chassis[chassis] = true
+	i.set(chassis, serial, position, time.Now().UTC())
+}
+
+// Record replaces the blades of the chassis with the result of a scan
+func (i *BladeIndex) Record(chassis string, blades []*devices.Blade) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	i.chassis[chassis] = true
+	for key, location := range i.blades {
+		if location.Chassis == chassis {
+			delete(i.blades, key)
+		}
+	}
+
+	now := time.Now().UTC()
+	for _, blade := range blades {
+		if blade.Serial != "" {
+			i.set(chassis, blade.Serial, blade.BladePosition, now)
+		}
+	}
+}
+
+func (i *BladeIndex) Invalidate(serial string) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	delete(i.blades, serialKey(serial))
+}
+
+// InvalidatePosition drops the blade at the position of the chassis, e.g. after it has been reseated
+func (i *BladeIndex) InvalidatePosition(chassis string, position int) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	for key, location := range i.blades {
+		if location.Chassis == chassis && location.Position == position {
+			delete(i.blades, key)
+		}
+	}
+}
+
+// Chassis returns the chassis seen so far, sorted
+func (i *BladeIndex) Chassis() []string {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+
+	chassis := make([]string, 0, len(i.chassis))
+	for host := range i.chassis {
+		chassis = append(chassis, host)
+	}
+	sort.Strings(chassis)
+
+	return chassis
+}
+
+// set records the blade, a blade found elsewhere moved so its previous location is replaced
+func (i *BladeIndex) set(chassis, serial string, position int, now time.Time) {
+	i.blades[serialKey(serial)] = BladeLocation{Serial: serial, Chassis: chassis, Position: position, Updated: now}
+}
+
+// serialKey ignores the case of serials like the chassis do when they look blades up
+func serialKey(serial string) string {
+	return strings.ToLower(serial)
+}
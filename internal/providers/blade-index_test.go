@@ -0,0 +1,42 @@
+package providers
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/bmc-toolbox/bmclib/devices"
+)
+
+func TestBladeIndex(t *testing.T) {
+	index := NewBladeIndex()
+
+	index.Set("chassis-1", "ABC123", 3)
+	if position, ok := index.Position("chassis-1", "abc123"); !ok || position != 3 {
+		t.Errorf("Position() = %d, %v, want 3 regardless of the case of the serial", position, ok)
+	}
+	if _, ok := index.Position("chassis-2", "ABC123"); ok {
+		t.Errorf("Position() found the blade in another chassis")
+	}
+
+	// a scan of another chassis moves the blade and a scan of its chassis drops the blades which left
+	index.Record("chassis-2", []*devices.Blade{{Serial: "ABC123", BladePosition: 5}, {Serial: "DEF456", BladePosition: 6}})
+	index.Record("chassis-1", []*devices.Blade{{Serial: "GHI789", BladePosition: 1}})
+
+	if location, ok := index.Lookup("ABC123"); !ok || location.Chassis != "chassis-2" || location.Position != 5 {
+		t.Errorf("Lookup() = %+v, %v, want chassis-2 position 5", location, ok)
+	}
+
+	index.InvalidatePosition("chassis-2", 6)
+	if _, ok := index.Lookup("DEF456"); ok {
+		t.Errorf("Lookup() found the blade of an invalidated position")
+	}
+
+	index.Invalidate("ghi789")
+	if _, ok := index.Lookup("GHI789"); ok {
+		t.Errorf("Lookup() found an invalidated blade")
+	}
+
+	if got, want := index.Chassis(), []string{"chassis-1", "chassis-2"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Chassis() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,27 @@
+package providers
+
+const (
+	ProviderBmclib = "bmclib"
+	ProviderIpmi   = "ipmi"
+
+	unknownHardwareType = "unknown"
+)
+
+// screenshotHardwareTypes are the hardware types bmclib takes screenshots of, the other BMCs refuse them
+var screenshotHardwareTypes = map[string]bool{"idrac8": true, "idrac9": true, "ilo5": true, "supermicrox": true}
+
+type (
+	// BmcInfo describes the BMC behind a wrapper and the provider used to talk to it
+	BmcInfo struct {
+		Provider           string
+		Vendor             string
+		HardwareType       string
+		FirmwareVersion    string
+		SupportsScreenshot bool
+	}
+)
+
+// SupportsScreenshot tells whether bmclib takes screenshots of BMCs of the hardware type
+func SupportsScreenshot(hardwareType string) bool {
+	return screenshotHardwareTypes[hardwareType]
+}
@@ -0,0 +1,23 @@
+package providers
+
+import "testing"
+
+func TestSupportsScreenshot(t *testing.T) {
+	tests := []struct {
+		hardwareType string
+		want         bool
+	}{
+		{hardwareType: "idrac9", want: true},
+		{hardwareType: "ilo5", want: true},
+		{hardwareType: "ilo4", want: false},
+		{hardwareType: "m1000e", want: false},
+		{hardwareType: unknownHardwareType, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.hardwareType, func(t *testing.T) {
+			if got := SupportsScreenshot(tt.hardwareType); got != tt.want {
+				t.Errorf("SupportsScreenshot(%s) = %v, want %v", tt.hardwareType, got, tt.want)
+			}
+		})
+	}
+}
@@ -36,3 +36,39 @@ func (w *ChassisBmcWrapper) PowerCycle() (bool, error) {
 	}
 	return w.bmc.PowerCycle()
 }
+
+func (w *ChassisBmcWrapper) PowerOff() (bool, error) {
+	if err := w.initBmcProvider(); err != nil {
+		return false, err
+	}
+	return w.bmc.PowerOff()
+}
+
+func (w *ChassisBmcWrapper) SetDynamicPower(enable bool) (bool, error) {
+	if err := w.initBmcProvider(); err != nil {
+		return false, err
+	}
+	return w.bmc.SetDynamicPower(enable)
+}
+
+// HardwareType returns "unknown" when the chassis BMC can't be reached
+func (w *ChassisBmcWrapper) HardwareType() string {
+	if err := w.initBmcProvider(); err != nil {
+		return unknownHardwareType
+	}
+	return w.bmc.HardwareType()
+}
+
+func (w *ChassisBmcWrapper) UpdateFirmware(source, file string) (bool, string, error) {
+	if err := w.initBmcProvider(); err != nil {
+		return false, "", err
+	}
+	return w.bmc.UpdateFirmware(source, file)
+}
+
+func (w *ChassisBmcWrapper) CheckFirmwareVersion() (string, error) {
+	if err := w.initBmcProvider(); err != nil {
+		return "", err
+	}
+	return w.bmc.CheckFirmwareVersion()
+}
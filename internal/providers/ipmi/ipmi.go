@@ -4,7 +4,9 @@ import (
 	"bufio"
 	"context"
 	"fmt"
+	"io/ioutil"
 	"net"
+	"os"
 	"os/exec"
 	"strings"
 
@@ -36,7 +38,7 @@ func New(username string, password string, host string) (ipmi *Ipmi, err error)
 	return ipmi, nil
 }
 
-func (i *Ipmi) run(command []string) (output string, err error) {
+func (i *Ipmi) args(command []string) []string {
 	ipmiArgs := []string{"-I", "lanplus", "-U", i.Username, "-E", "-N", "5"}
 	if strings.Contains(i.Host, ":") {
 		host, port, err := net.SplitHostPort(i.Host)
@@ -47,10 +49,18 @@ func (i *Ipmi) run(command []string) (output string, err error) {
 		ipmiArgs = append(ipmiArgs, "-H", i.Host)
 	}
 
-	ipmiArgs = append(ipmiArgs, command...)
+	return append(ipmiArgs, command...)
+}
+
+func (i *Ipmi) env() []string {
+	return []string{fmt.Sprintf("IPMITOOL_PASSWORD=%s", i.Password)}
+}
+
+func (i *Ipmi) run(command []string) (output string, err error) {
+	ipmiArgs := i.args(command)
 	ctx := context.Background()
 	cmd := exec.CommandContext(ctx, i.ipmitool, ipmiArgs...)
-	cmd.Env = []string{fmt.Sprintf("IPMITOOL_PASSWORD=%s", i.Password)}
+	cmd.Env = i.env()
 	out, err := cmd.CombinedOutput()
 	if ctx.Err() == context.DeadlineExceeded {
 		return string(out), errors.Wrap(ctx.Err(), "[run, context.DeadlineExceeded]")
@@ -58,6 +68,26 @@ func (i *Ipmi) run(command []string) (output string, err error) {
 	return string(out), errors.Wrap(err, "[run] Output: "+string(out)+"|"+i.ipmitool+" "+strings.Join(ipmiArgs, " "))
 }
 
+// runSecret runs a command holding a secret, e.g. a password, from a file only the current user can read with
+// "ipmitool exec" so the secret never shows up on the command line of ipmitool
+func (i *Ipmi) runSecret(command []string) (output string, err error) {
+	file, err := ioutil.TempFile("", "ipmitool-")
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = os.Remove(file.Name()) }()
+
+	_, err = file.WriteString(strings.Join(command, " ") + "\n")
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return "", err
+	}
+
+	return i.run([]string{"exec", file.Name()})
+}
+
 // Reboot the machine via BMC
 func (i *Ipmi) PowerCycle() (status bool, err error) {
 	output, err := i.run([]string{"chassis", "power", "status"})
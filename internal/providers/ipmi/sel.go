@@ -0,0 +1,144 @@
+package ipmi
+
+import (
+	"bufio"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	SeverityInfo     = "info"
+	SeverityWarning  = "warning"
+	SeverityCritical = "critical"
+
+	selTimeLayout = "01/02/2006 15:04:05"
+)
+
+var (
+	sensorIDPattern = regexp.MustCompile(`\s*#0x[0-9a-fA-F]+$`)
+
+	// the keywords are checked in order, so "non-critical" wins over "critical"
+	severityKeywords = []struct {
+		keyword  string
+		severity string
+	}{
+		{"non-critical", SeverityWarning},
+		{"non-recoverable", SeverityCritical},
+		{"critical", SeverityCritical},
+		{"uncorrectable", SeverityCritical},
+		{"fatal", SeverityCritical},
+		{"ierr", SeverityCritical},
+		{"thermal trip", SeverityCritical},
+		{"machine check", SeverityCritical},
+		{"ac lost", SeverityCritical},
+		{"predictive failure", SeverityWarning},
+		{"failure", SeverityCritical},
+		{"fault", SeverityCritical},
+		{"redundancy lost", SeverityWarning},
+		{"degraded", SeverityWarning},
+		{"correctable", SeverityWarning},
+	}
+)
+
+// SelEntry is an entry of the System Event Log, Time is zero for events logged before the BMC clock was set
+type SelEntry struct {
+	ID         int       `json:"id"`
+	Time       time.Time `json:"time"`
+	Sensor     string    `json:"sensor"`
+	SensorType string    `json:"sensor-type"`
+	Event      string    `json:"event"`
+	Asserted   bool      `json:"asserted"`
+	Severity   string    `json:"severity"`
+}
+
+// ReadSel reads the System Event Log, the BMC clock is assumed to be in UTC
+func (i *Ipmi) ReadSel() ([]SelEntry, error) {
+	output, err := i.run([]string{"sel", "elist"})
+	if err != nil {
+		return nil, fmt.Errorf("[ReadSel Error] %v: %v", err, output)
+	}
+
+	return parseSel(output), nil
+}
+
+// ClearSel erases the System Event Log
+func (i *Ipmi) ClearSel() (bool, error) {
+	output, err := i.run([]string{"sel", "clear"})
+	if err != nil {
+		return false, fmt.Errorf("[ClearSel Error] %v: %v", err, output)
+	}
+
+	if strings.Contains(output, "Clearing SEL") {
+		return true, nil
+	}
+	return false, fmt.Errorf("[ClearSel (unexpected output)] %v", output)
+}
+
+// SeverityRank orders the severities, unknown ones rank lowest
+func SeverityRank(severity string) int {
+	switch severity {
+	case SeverityCritical:
+		return 2
+	case SeverityWarning:
+		return 1
+	}
+	return 0
+}
+
+// parseSel parses the "id | date | time | sensor | event | direction" lines of "ipmitool sel elist",
+// other lines are skipped
+func parseSel(output string) []SelEntry {
+	entries := make([]SelEntry, 0)
+
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	for scanner.Scan() {
+		fields := strings.Split(scanner.Text(), "|")
+		if len(fields) < 6 {
+			continue
+		}
+		for j := range fields {
+			fields[j] = strings.TrimSpace(fields[j])
+		}
+
+		id, err := strconv.ParseInt(fields[0], 16, 32)
+		if err != nil {
+			continue
+		}
+
+		entry := SelEntry{
+			ID:         int(id),
+			Sensor:     fields[3],
+			SensorType: sensorIDPattern.ReplaceAllString(fields[3], ""),
+			Event:      strings.Join(fields[4:len(fields)-1], " | "),
+			Asserted:   fields[len(fields)-1] == "Asserted",
+		}
+
+		// "Pre-Init" entries carry the uptime instead of a date
+		if t, err := time.Parse(selTimeLayout, fields[1]+" "+fields[2]); err == nil {
+			entry.Time = t.UTC()
+		}
+
+		entry.Severity = severity(entry)
+		entries = append(entries, entry)
+	}
+
+	return entries
+}
+
+func severity(entry SelEntry) string {
+	if !entry.Asserted {
+		return SeverityInfo
+	}
+
+	event := strings.ToLower(entry.Event)
+	for _, k := range severityKeywords {
+		if strings.Contains(event, k.keyword) {
+			return k.severity
+		}
+	}
+
+	return SeverityInfo
+}
@@ -0,0 +1,29 @@
+package ipmi
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func Test_parseSel(t *testing.T) {
+	output := `   1 | 06/17/2021 | 13:05:01 | Event Logging Disabled #0x72 | Log area reset/cleared | Asserted
+   2 | Pre-Init  |0000000012| System Event #0x83 | Timestamp Clock Sync | Asserted
+   a | 06/17/2021 | 13:10:22 | Power Supply #0x63 | Power Supply AC lost | Asserted
+   b | 06/17/2021 | 13:10:40 | Power Supply #0x63 | Power Supply AC lost | Deasserted
+   c | 06/17/2021 | 13:11:00 | Temperature #0x30 | Upper Non-critical going high | Reading 42 > Threshold 40 degrees C | Asserted
+SEL has no entries
+`
+
+	want := []SelEntry{
+		{ID: 1, Time: time.Date(2021, 6, 17, 13, 5, 1, 0, time.UTC), Sensor: "Event Logging Disabled #0x72", SensorType: "Event Logging Disabled", Event: "Log area reset/cleared", Asserted: true, Severity: SeverityInfo},
+		{ID: 2, Sensor: "System Event #0x83", SensorType: "System Event", Event: "Timestamp Clock Sync", Asserted: true, Severity: SeverityInfo},
+		{ID: 10, Time: time.Date(2021, 6, 17, 13, 10, 22, 0, time.UTC), Sensor: "Power Supply #0x63", SensorType: "Power Supply", Event: "Power Supply AC lost", Asserted: true, Severity: SeverityCritical},
+		{ID: 11, Time: time.Date(2021, 6, 17, 13, 10, 40, 0, time.UTC), Sensor: "Power Supply #0x63", SensorType: "Power Supply", Event: "Power Supply AC lost", Asserted: false, Severity: SeverityInfo},
+		{ID: 12, Time: time.Date(2021, 6, 17, 13, 11, 0, 0, time.UTC), Sensor: "Temperature #0x30", SensorType: "Temperature", Event: "Upper Non-critical going high | Reading 42 > Threshold 40 degrees C", Asserted: true, Severity: SeverityWarning},
+	}
+
+	if got := parseSel(output); !reflect.DeepEqual(got, want) {
+		t.Errorf("parseSel() = %+v, want %+v", got, want)
+	}
+}
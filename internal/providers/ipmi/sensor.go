@@ -0,0 +1,78 @@
+package ipmi
+
+import (
+	"bufio"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// SensorReading is a row of the SDR sensor table, values and thresholds are nil when not available
+type SensorReading struct {
+	Name   string
+	Value  *float64
+	Unit   string
+	Status string
+
+	LowerNonRecoverable *float64
+	LowerCritical       *float64
+	LowerNonCritical    *float64
+	UpperNonCritical    *float64
+	UpperCritical       *float64
+	UpperNonRecoverable *float64
+}
+
+// ReadSensors reads the sensors with their thresholds from the SDR
+func (i *Ipmi) ReadSensors() ([]SensorReading, error) {
+	output, err := i.run([]string{"sensor"})
+	if err != nil {
+		return nil, fmt.Errorf("[ReadSensors Error] %v: %v", err, output)
+	}
+
+	return parseSensors(output), nil
+}
+
+// parseSensors parses the "name | value | unit | status | lnr | lcr | lnc | unc | ucr | unr" lines of
+// "ipmitool sensor", other lines are skipped
+func parseSensors(output string) []SensorReading {
+	readings := make([]SensorReading, 0)
+
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	for scanner.Scan() {
+		fields := strings.Split(scanner.Text(), "|")
+		if len(fields) < 10 {
+			continue
+		}
+		for j := range fields {
+			fields[j] = strings.TrimSpace(fields[j])
+		}
+
+		readings = append(readings, SensorReading{
+			Name:                fields[0],
+			Value:               parseSensorValue(fields[1]),
+			Unit:                fields[2],
+			Status:              fields[3],
+			LowerNonRecoverable: parseSensorValue(fields[4]),
+			LowerCritical:       parseSensorValue(fields[5]),
+			LowerNonCritical:    parseSensorValue(fields[6]),
+			UpperNonCritical:    parseSensorValue(fields[7]),
+			UpperCritical:       parseSensorValue(fields[8]),
+			UpperNonRecoverable: parseSensorValue(fields[9]),
+		})
+	}
+
+	return readings
+}
+
+// parseSensorValue returns nil for "na" and for the hex states of discrete sensors
+func parseSensorValue(value string) *float64 {
+	if strings.HasPrefix(value, "0x") {
+		return nil
+	}
+
+	v, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return nil
+	}
+	return &v
+}
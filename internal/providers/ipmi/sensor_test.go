@@ -0,0 +1,29 @@
+package ipmi
+
+import "testing"
+
+func Test_parseSensors(t *testing.T) {
+	output := `Inlet Temp       | 23.000     | degrees C  | ok    | na        | -7.000    | 3.000     | 42.000    | 47.000    | na
+Fan1             | 5880.000   | RPM        | ok    | na        | 360.000   | 840.000   | na        | na        | na
+PS1 Status       | 0x1        | discrete   | 0x0100| na        | na        | na        | na        | na        | na
+Pwr Consumption  | 168.000    | Watts      | nc    | na        | na        | na        | 896.000   | 980.000   | na
+not a sensor line
+`
+
+	got := parseSensors(output)
+	if len(got) != 4 {
+		t.Fatalf("parseSensors() = %d readings, want 4", len(got))
+	}
+
+	inlet := got[0]
+	if inlet.Name != "Inlet Temp" || inlet.Value == nil || *inlet.Value != 23 || inlet.Unit != "degrees C" || inlet.Status != "ok" {
+		t.Errorf("parseSensors()[0] = %+v", inlet)
+	}
+	if inlet.LowerNonRecoverable != nil || inlet.LowerCritical == nil || *inlet.LowerCritical != -7 || inlet.UpperCritical == nil || *inlet.UpperCritical != 47 {
+		t.Errorf("parseSensors()[0] thresholds = %+v", inlet)
+	}
+
+	if psu := got[2]; psu.Value != nil || psu.Status != "0x0100" {
+		t.Errorf("parseSensors()[2] = %+v, want a discrete sensor without value", psu)
+	}
+}
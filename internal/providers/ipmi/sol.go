@@ -0,0 +1,97 @@
+package ipmi
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os/exec"
+	"regexp"
+	"strings"
+)
+
+const (
+	// MaxSolTranscript bounds the transcript of a capture, the capture stops once it is reached
+	MaxSolTranscript = 4 << 20
+	// solMatchOverlap is how much of the transcript read before is matched along with the new output,
+	// so matches spanning reads are found without rescanning the whole transcript
+	solMatchOverlap = 4096
+)
+
+// CaptureSol records the serial console through a SOL session until the context is done, the transcript
+// reaches MaxSolTranscript or it matches until, which may be nil. It returns the transcript and whether until matched.
+func (i *Ipmi) CaptureSol(ctx context.Context, until *regexp.Regexp) ([]byte, bool, error) {
+	// a session left behind by another client makes the activation fail
+	_, _ = i.run([]string{"sol", "deactivate"})
+	defer func() { _, _ = i.run([]string{"sol", "deactivate"}) }()
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, i.ipmitool, i.args([]string{"sol", "activate"})...)
+	cmd.Env = i.env()
+
+	stderr := &bytes.Buffer{}
+	cmd.Stderr = stderr
+
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, false, fmt.Errorf("[CaptureSol Error] %w", err)
+	}
+
+	// ipmitool ends the session on EOF of its input, so the input stays open until the capture is over
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, false, fmt.Errorf("[CaptureSol Error] %w", err)
+	}
+	defer stdin.Close()
+
+	if err := cmd.Start(); err != nil {
+		return nil, false, fmt.Errorf("[CaptureSol Error] %w", err)
+	}
+
+	transcript, matched := capture(stdout, until, MaxSolTranscript, cancel)
+	deadline := ctx.Err() != nil
+
+	_ = cmd.Wait()
+
+	if !matched && !deadline {
+		return transcript, false, fmt.Errorf("[CaptureSol Error] the SOL session ended early: %s", strings.TrimSpace(stderr.String()))
+	}
+
+	return transcript, matched, nil
+}
+
+// capture reads the console until EOF, until the transcript holds limit bytes or until the output matches,
+// cancel is called on a match or once the limit is reached
+func capture(r io.Reader, until *regexp.Regexp, limit int, cancel func()) ([]byte, bool) {
+	transcript := &bytes.Buffer{}
+	buf := make([]byte, 4096)
+
+	for {
+		n, err := r.Read(buf)
+		if transcript.Len()+n > limit {
+			n = limit - transcript.Len()
+		}
+		transcript.Write(buf[:n])
+
+		if n > 0 && until != nil {
+			start := transcript.Len() - n - solMatchOverlap
+			if start < 0 {
+				start = 0
+			}
+			if until.Match(transcript.Bytes()[start:]) {
+				cancel()
+				return transcript.Bytes(), true
+			}
+		}
+
+		if transcript.Len() >= limit {
+			cancel()
+			return transcript.Bytes(), false
+		}
+		if err != nil {
+			return transcript.Bytes(), false
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package ipmi
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+func Test_capture(t *testing.T) {
+	boot := strings.Repeat("booting...\n", 1000) + "host login: "
+
+	tests := []struct {
+		name        string
+		console     string
+		until       *regexp.Regexp
+		limit       int
+		wantLen     int
+		wantMatched bool
+	}{
+		{name: "Match", console: boot + "ignored", until: regexp.MustCompile(`login: $`), limit: MaxSolTranscript, wantMatched: true},
+		{name: "No match", console: boot, until: regexp.MustCompile(`panic`), limit: MaxSolTranscript, wantLen: len(boot)},
+		{name: "No pattern", console: boot, limit: MaxSolTranscript, wantLen: len(boot)},
+		{name: "Limit", console: boot, until: regexp.MustCompile(`login`), limit: 1000, wantLen: 1000},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cancelled := false
+			// one byte at a time, so the match spans many reads
+			r := iotest.OneByteReader(bytes.NewReader([]byte(tt.console)))
+
+			transcript, matched := capture(r, tt.until, tt.limit, func() { cancelled = true })
+			if matched != tt.wantMatched {
+				t.Errorf("capture() matched = %v, want %v", matched, tt.wantMatched)
+			}
+			if tt.wantMatched {
+				if !strings.HasSuffix(string(transcript), "login: ") || !cancelled {
+					t.Errorf("capture() didn't stop at the match")
+				}
+				return
+			}
+			if len(transcript) != tt.wantLen {
+				t.Errorf("capture() kept %d bytes, want %d", len(transcript), tt.wantLen)
+			}
+			if wantCancelled := tt.wantLen == tt.limit; cancelled != wantCancelled {
+				t.Errorf("capture() cancelled = %v, want %v", cancelled, wantCancelled)
+			}
+		})
+	}
+}
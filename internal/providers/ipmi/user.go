@@ -0,0 +1,246 @@
+package ipmi
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const (
+	RoleAdmin    = "admin"
+	RoleOperator = "operator"
+	RoleUser     = "user"
+
+	// userChannel is the LAN channel the privileges are granted on
+	userChannel = "1"
+	// noAccessPrivilege revokes the access of disabled users, they are listed with noAccess
+	noAccessPrivilege = "15"
+	noAccess          = "NO ACCESS"
+	// maxShortPassword is the longest password accepted without switching to 20 byte IPMI v2 passwords
+	maxShortPassword = 16
+)
+
+var (
+	ErrUserNotFound = errors.New("BMC user not found")
+	ErrUserExists   = errors.New("BMC user already exists")
+	ErrUserDisabled = errors.New("BMC user is disabled")
+
+	rolePrivileges = map[string]string{RoleAdmin: "4", RoleOperator: "3", RoleUser: "2"}
+)
+
+// User is a local user of the BMC as listed by "ipmitool user list"
+type User struct {
+	ID            int    `json:"id"`
+	Name          string `json:"name"`
+	Callin        bool   `json:"callin"`
+	LinkAuth      bool   `json:"link-auth"`
+	IpmiMessaging bool   `json:"ipmi-messaging"`
+	Privilege     string `json:"privilege"`
+}
+
+// Users lists the named users of the BMC
+func (i *Ipmi) Users() ([]User, error) {
+	slots, err := i.userSlots()
+	if err != nil {
+		return nil, err
+	}
+
+	users := make([]User, 0, len(slots))
+	for _, slot := range slots {
+		if slot.Name != "" {
+			users = append(users, slot)
+		}
+	}
+
+	return users, nil
+}
+
+// CreateUser creates an enabled user in the first free slot, the first slot is reserved for the anonymous user
+func (i *Ipmi) CreateUser(name, password, role string) error {
+	privilege, err := rolePrivilege(role)
+	if err != nil {
+		return err
+	}
+
+	slots, err := i.userSlots()
+	if err != nil {
+		return err
+	}
+
+	id := 0
+	for _, slot := range slots {
+		if slot.Name == name {
+			return fmt.Errorf("%w: %s", ErrUserExists, name)
+		}
+		if id == 0 && slot.Name == "" && slot.ID > 1 {
+			id = slot.ID
+		}
+	}
+	if id == 0 {
+		return fmt.Errorf("no free user slot for %s", name)
+	}
+
+	if output, err := i.run([]string{"user", "set", "name", strconv.Itoa(id), name}); err != nil {
+		return fmt.Errorf("[CreateUser Error] %v: %v", err, output)
+	}
+
+	if err := i.setUser(id, password, privilege); err != nil {
+		return err
+	}
+
+	if output, err := i.run([]string{"user", "enable", strconv.Itoa(id)}); err != nil {
+		return fmt.Errorf("[CreateUser (enable) Error] %v: %v", err, output)
+	}
+
+	return nil
+}
+
+// UpdateUser changes the password and the role of the user, empty values are left unchanged,
+// users disabled by DisableUser stay disabled so their role can't be changed
+func (i *Ipmi) UpdateUser(name, password, role string) error {
+	privilege := ""
+	if role != "" {
+		var err error
+		if privilege, err = rolePrivilege(role); err != nil {
+			return err
+		}
+	}
+
+	user, err := i.findUser(name)
+	if err != nil {
+		return err
+	}
+	if privilege != "" && user.Privilege == noAccess {
+		return fmt.Errorf("%w: the role of %s can't be changed", ErrUserDisabled, name)
+	}
+
+	return i.setUser(user.ID, password, privilege)
+}
+
+// DisableUser disables the user and revokes its access, IPMI has no way to delete users
+func (i *Ipmi) DisableUser(name string) error {
+	if name == i.Username {
+		return fmt.Errorf("refusing to disable %s, the user actor connects with", name)
+	}
+
+	user, err := i.findUser(name)
+	if err != nil {
+		return err
+	}
+	id := user.ID
+
+	if output, err := i.run([]string{"user", "disable", strconv.Itoa(id)}); err != nil {
+		return fmt.Errorf("[DisableUser Error] %v: %v", err, output)
+	}
+
+	output, err := i.run([]string{"channel", "setaccess", userChannel, strconv.Itoa(id), "callin=off", "ipmi=off", "link=off", "privilege=" + noAccessPrivilege})
+	if err != nil {
+		return fmt.Errorf("[DisableUser (access) Error] %v: %v", err, output)
+	}
+
+	return nil
+}
+
+// setUser sets the password and the privilege when they aren't empty, it leaves the user enabled or disabled
+func (i *Ipmi) setUser(id int, password, privilege string) error {
+	if password != "" {
+		if strings.ContainsAny(password, " \t\r\n\"'") {
+			return fmt.Errorf("[setUser (password) Error] the password of user %d holds whitespace or quotes, ipmitool can't set it", id)
+		}
+
+		command := []string{"user", "set", "password", strconv.Itoa(id), password}
+		if len(password) > maxShortPassword {
+			command = append(command, "20")
+		}
+		if _, err := i.runSecret(command); err != nil {
+			// the output may echo the command, which holds the password
+			return fmt.Errorf("[setUser (password) Error] failed to set the password of user %d", id)
+		}
+	}
+
+	if privilege != "" {
+		output, err := i.run([]string{"channel", "setaccess", userChannel, strconv.Itoa(id), "callin=on", "ipmi=on", "link=on", "privilege=" + privilege})
+		if err != nil {
+			return fmt.Errorf("[setUser (access) Error] %v: %v", err, output)
+		}
+	}
+
+	return nil
+}
+
+func (i *Ipmi) findUser(name string) (User, error) {
+	slots, err := i.userSlots()
+	if err != nil {
+		return User{}, err
+	}
+
+	for _, slot := range slots {
+		if slot.Name == name {
+			return slot, nil
+		}
+	}
+
+	return User{}, fmt.Errorf("%w: %s", ErrUserNotFound, name)
+}
+
+func (i *Ipmi) userSlots() ([]User, error) {
+	output, err := i.run([]string{"user", "list", userChannel})
+	if err != nil {
+		return nil, fmt.Errorf("[Users Error] %v: %v", err, output)
+	}
+
+	return parseUsers(output), nil
+}
+
+// parseUsers parses the "ID Name Callin Link-Auth IPMI-Msg Channel-Priv-Limit" table of "ipmitool user list",
+// the name of free slots is empty
+func parseUsers(output string) []User {
+	users := make([]User, 0)
+
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 5 {
+			continue
+		}
+
+		id, err := strconv.Atoi(fields[0])
+		if err != nil {
+			continue
+		}
+
+		// free slots have no name, so the flags start right after the ID
+		name := ""
+		if !isFlag(fields[1]) {
+			name, fields = fields[1], fields[1:]
+		}
+		if len(fields) < 5 {
+			continue
+		}
+
+		users = append(users, User{
+			ID:            id,
+			Name:          name,
+			Callin:        fields[1] == "true",
+			LinkAuth:      fields[2] == "true",
+			IpmiMessaging: fields[3] == "true",
+			Privilege:     strings.Join(fields[4:], " "),
+		})
+	}
+
+	return users
+}
+
+func isFlag(field string) bool {
+	return field == "true" || field == "false"
+}
+
+func rolePrivilege(role string) (string, error) {
+	privilege, ok := rolePrivileges[role]
+	if !ok {
+		return "", fmt.Errorf("unknown role %q: one of admin, operator and user is expected", role)
+	}
+	return privilege, nil
+}
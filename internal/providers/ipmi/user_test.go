@@ -0,0 +1,157 @@
+package ipmi
+
+import (
+	"errors"
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const testUserList = `ID  Name	     Callin  Link Auth	IPMI Msg   Channel Priv Limit
+1                    true    false      false      Unknown (0x00)
+2   root             false   true       true       ADMINISTRATOR
+3   actor            true    true       true       OPERATOR
+4   former           true    false      false      NO ACCESS
+5                    true    false      false      NO ACCESS
+`
+
+// newTestIpmi returns an Ipmi running a fake ipmitool, which prints testUserList and logs the commands it gets,
+// including those of the files it runs with exec, as well as its command lines
+func newTestIpmi(t *testing.T) (*Ipmi, func() []string, func() []string) {
+	dir := t.TempDir()
+	users, log, argv := filepath.Join(dir, "users"), filepath.Join(dir, "log"), filepath.Join(dir, "argv")
+	if err := ioutil.WriteFile(users, []byte(testUserList), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	// the connection arguments of Ipmi.args come first
+	script := "#!/bin/sh\n" +
+		"echo \"$*\" >> " + argv + "\n" +
+		"shift 9\n" +
+		"if [ \"$1\" = exec ]; then cat \"$2\" >> " + log + "; else echo \"$*\" >> " + log + "; fi\n" +
+		"[ \"$1 $2\" = \"user list\" ] && cat " + users + "\n" +
+		"exit 0\n"
+	ipmitool := filepath.Join(dir, "ipmitool")
+	if err := ioutil.WriteFile(ipmitool, []byte(script), 0700); err != nil {
+		t.Fatal(err)
+	}
+
+	lines := func(file string) []string {
+		b, _ := ioutil.ReadFile(file)
+		var lines []string
+		for _, line := range strings.Split(strings.TrimSpace(string(b)), "\n") {
+			if line != "" && !strings.HasPrefix(line, "user list") {
+				lines = append(lines, line)
+			}
+		}
+		return lines
+	}
+
+	return &Ipmi{Username: "actor", Host: "10.0.0.1", ipmitool: ipmitool},
+		func() []string { return lines(log) },
+		func() []string { return lines(argv) }
+}
+
+// checkArgv fails the test when the password shows up on a command line of ipmitool
+func checkArgv(t *testing.T, method string, argv []string, password string) {
+	t.Helper()
+	for _, line := range argv {
+		if strings.Contains(line, password) {
+			t.Errorf("%s() ran ipmitool with the password on its command line: %s", method, line)
+		}
+	}
+}
+
+func Test_parseUsers(t *testing.T) {
+	output := `ID  Name	     Callin  Link Auth	IPMI Msg   Channel Priv Limit
+1                    true    false      false      Unknown (0x00)
+2   root             false   true       true       ADMINISTRATOR
+3   actor            true    true       true       OPERATOR
+4                    true    false      false      NO ACCESS
+`
+
+	want := []User{
+		{ID: 1, Callin: true, Privilege: "Unknown (0x00)"},
+		{ID: 2, Name: "root", LinkAuth: true, IpmiMessaging: true, Privilege: "ADMINISTRATOR"},
+		{ID: 3, Name: "actor", Callin: true, LinkAuth: true, IpmiMessaging: true, Privilege: "OPERATOR"},
+		{ID: 4, Callin: true, Privilege: "NO ACCESS"},
+	}
+
+	if got := parseUsers(output); !reflect.DeepEqual(got, want) {
+		t.Errorf("parseUsers() = %+v, want %+v", got, want)
+	}
+}
+
+func TestIpmi_CreateUser(t *testing.T) {
+	i, commands, argv := newTestIpmi(t)
+
+	if err := i.CreateUser("new", "secret", RoleUser); err != nil {
+		t.Fatal(err)
+	}
+	checkArgv(t, "CreateUser", argv(), "secret")
+
+	want := []string{
+		"user set name 5 new",
+		"user set password 5 secret",
+		"channel setaccess 1 5 callin=on ipmi=on link=on privilege=2",
+		"user enable 5",
+	}
+	if got := commands(); !reflect.DeepEqual(got, want) {
+		t.Errorf("CreateUser() ran %q, want %q", got, want)
+	}
+}
+
+func TestIpmi_UpdateUser(t *testing.T) {
+	tests := []struct {
+		name         string
+		user         string
+		password     string
+		role         string
+		wantErr      error
+		wantCommands []string
+	}{
+		{
+			name:         "Password and role",
+			user:         "actor",
+			password:     "secret",
+			role:         RoleAdmin,
+			wantCommands: []string{"user set password 3 secret", "channel setaccess 1 3 callin=on ipmi=on link=on privilege=4"},
+		},
+		{
+			// a rotation must not enable a user which was disabled on purpose
+			name:         "Password of a disabled user",
+			user:         "former",
+			password:     "secret",
+			wantCommands: []string{"user set password 4 secret"},
+		},
+		{
+			name:    "Role of a disabled user",
+			user:    "former",
+			role:    RoleAdmin,
+			wantErr: ErrUserDisabled,
+		},
+		{
+			name:    "Unknown user",
+			user:    "nobody",
+			role:    RoleAdmin,
+			wantErr: ErrUserNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			i, commands, argv := newTestIpmi(t)
+
+			if err := i.UpdateUser(tt.user, tt.password, tt.role); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("UpdateUser() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.password != "" {
+				checkArgv(t, "UpdateUser", argv(), tt.password)
+			}
+			if got := commands(); !reflect.DeepEqual(got, tt.wantCommands) {
+				t.Errorf("UpdateUser() ran %q, want %q", got, tt.wantCommands)
+			}
+		})
+	}
+}
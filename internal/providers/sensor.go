@@ -0,0 +1,172 @@
+package providers
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/bmc-toolbox/actor/internal/providers/ipmi"
+	"github.com/bmc-toolbox/bmclib/devices"
+)
+
+const (
+	SensorTemperature = "temperature"
+	SensorFan         = "fan"
+	SensorVoltage     = "voltage"
+	SensorPower       = "power"
+	SensorPsu         = "psu"
+	SensorOther       = "other"
+
+	SensorStatusOK       = "ok"
+	SensorStatusWarning  = "warning"
+	SensorStatusCritical = "critical"
+	SensorStatusUnknown  = "unknown"
+)
+
+type (
+	// Sensor is a normalized sensor reading, temperatures are in C and power in W
+	Sensor struct {
+		Name       string
+		Type       string
+		Value      *float64
+		Unit       string
+		Status     string
+		Thresholds SensorThresholds
+	}
+
+	// SensorThresholds are nil when the BMC doesn't report them
+	SensorThresholds struct {
+		LowerCritical *float64
+		LowerWarning  *float64
+		UpperWarning  *float64
+		UpperCritical *float64
+	}
+)
+
+func newIpmiSensor(reading ipmi.SensorReading) Sensor {
+	sensor := Sensor{
+		Name:   reading.Name,
+		Value:  reading.Value,
+		Unit:   reading.Unit,
+		Status: SensorStatusUnknown,
+		Thresholds: SensorThresholds{
+			LowerCritical: reading.LowerCritical,
+			LowerWarning:  reading.LowerNonCritical,
+			UpperWarning:  reading.UpperNonCritical,
+			UpperCritical: reading.UpperCritical,
+		},
+	}
+
+	switch strings.ToLower(reading.Unit) {
+	case "degrees c":
+		sensor.Type, sensor.Unit = SensorTemperature, "C"
+	case "rpm":
+		sensor.Type, sensor.Unit = SensorFan, "RPM"
+	case "volts":
+		sensor.Type, sensor.Unit = SensorVoltage, "V"
+	case "watts":
+		sensor.Type, sensor.Unit = SensorPower, "W"
+	default:
+		sensor.Type = SensorOther
+		name := strings.ToLower(reading.Name)
+		if strings.HasPrefix(name, "ps") || strings.Contains(name, "power supply") {
+			sensor.Type = SensorPsu
+		}
+	}
+
+	switch reading.Status {
+	case "ok":
+		sensor.Status = SensorStatusOK
+	case "nc":
+		sensor.Status = SensorStatusWarning
+	case "cr", "nr":
+		sensor.Status = SensorStatusCritical
+	}
+
+	return sensor
+}
+
+// newBmclibSensors collects the readings bmclib offers for servers and chassis, the collectors missing
+// on a vendor are skipped and an error is only returned when nothing could be read
+func newBmclibSensors(tempC func() (int, error), powerKw func() (float64, error), fans func() ([]*devices.Fan, error), psus func() ([]*devices.Psu, error)) ([]Sensor, error) {
+	sensors := make([]Sensor, 0)
+	var firstErr error
+	keepErr := func(err error) {
+		if firstErr == nil {
+			firstErr = err
+		}
+	}
+
+	if temp, err := tempC(); err == nil {
+		sensors = append(sensors, Sensor{Name: "temperature", Type: SensorTemperature, Value: float(float64(temp)), Unit: "C", Status: SensorStatusUnknown})
+	} else {
+		keepErr(err)
+	}
+
+	if kw, err := powerKw(); err == nil {
+		sensors = append(sensors, Sensor{Name: "power", Type: SensorPower, Value: float(kw * 1000), Unit: "W", Status: SensorStatusUnknown})
+	} else {
+		keepErr(err)
+	}
+
+	if fans != nil {
+		if list, err := fans(); err == nil {
+			for _, fan := range list {
+				sensors = append(sensors, Sensor{
+					Name:   fmt.Sprintf("fan %d", fan.Position),
+					Type:   SensorFan,
+					Value:  float(float64(fan.CurrentRPM)),
+					Unit:   "RPM",
+					Status: normalizeVendorStatus(fan.Status),
+				})
+			}
+		} else {
+			keepErr(err)
+		}
+	}
+
+	if psus != nil {
+		if list, err := psus(); err == nil {
+			for _, psu := range list {
+				sensor := Sensor{
+					Name:   fmt.Sprintf("psu %d", psu.Position),
+					Type:   SensorPsu,
+					Value:  float(psu.PowerKw * 1000),
+					Unit:   "W",
+					Status: normalizeVendorStatus(psu.Status),
+				}
+				if psu.CapacityKw > 0 {
+					sensor.Thresholds.UpperCritical = float(psu.CapacityKw * 1000)
+				}
+				sensors = append(sensors, sensor)
+			}
+		} else {
+			keepErr(err)
+		}
+	}
+
+	if len(sensors) == 0 && firstErr != nil {
+		return nil, fmt.Errorf("failed to read sensors: %w", firstErr)
+	}
+
+	return sensors, nil
+}
+
+// normalizeVendorStatus maps the free form statuses reported by the vendors
+func normalizeVendorStatus(status string) string {
+	status = strings.ToLower(strings.TrimSpace(status))
+
+	switch {
+	case status == "":
+		return SensorStatusUnknown
+	case status == "ok" || status == "good" || status == "normal" || status == "present" || status == "on" || status == "enabled":
+		return SensorStatusOK
+	case strings.Contains(status, "fail") || strings.Contains(status, "critical") || strings.Contains(status, "error") || strings.Contains(status, "absent"):
+		return SensorStatusCritical
+	}
+
+	return SensorStatusWarning
+}
+
+func float(v float64) *float64 {
+	return &v
+}
@@ -0,0 +1,59 @@
+package providers
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/bmc-toolbox/actor/internal/providers/ipmi"
+	"github.com/bmc-toolbox/bmclib/devices"
+)
+
+func Test_newIpmiSensor(t *testing.T) {
+	tests := []struct {
+		name       string
+		reading    ipmi.SensorReading
+		wantType   string
+		wantUnit   string
+		wantStatus string
+	}{
+		{name: "Temperature", reading: ipmi.SensorReading{Name: "Inlet Temp", Unit: "degrees C", Status: "ok"}, wantType: SensorTemperature, wantUnit: "C", wantStatus: SensorStatusOK},
+		{name: "Fan", reading: ipmi.SensorReading{Name: "Fan1", Unit: "RPM", Status: "cr"}, wantType: SensorFan, wantUnit: "RPM", wantStatus: SensorStatusCritical},
+		{name: "Power", reading: ipmi.SensorReading{Name: "Pwr Consumption", Unit: "Watts", Status: "nc"}, wantType: SensorPower, wantUnit: "W", wantStatus: SensorStatusWarning},
+		{name: "PSU", reading: ipmi.SensorReading{Name: "PS1 Status", Unit: "discrete", Status: "0x0100"}, wantType: SensorPsu, wantUnit: "discrete", wantStatus: SensorStatusUnknown},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := newIpmiSensor(tt.reading)
+			if got.Type != tt.wantType || got.Unit != tt.wantUnit || got.Status != tt.wantStatus {
+				t.Errorf("newIpmiSensor() = %+v, want type %s, unit %s, status %s", got, tt.wantType, tt.wantUnit, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func Test_newBmclibSensors(t *testing.T) {
+	tempC := func() (int, error) { return 30, nil }
+	noPower := func() (float64, error) { return 0, errors.New("not supported") }
+	fans := func() ([]*devices.Fan, error) {
+		return []*devices.Fan{{Position: 1, CurrentRPM: 4000, Status: "OK"}}, nil
+	}
+	psus := func() ([]*devices.Psu, error) {
+		return []*devices.Psu{{Position: 2, PowerKw: 0.5, CapacityKw: 2.7, Status: "Failed"}}, nil
+	}
+
+	sensors, err := newBmclibSensors(tempC, noPower, fans, psus)
+	if err != nil {
+		t.Fatalf("newBmclibSensors() error = %v", err)
+	}
+	if len(sensors) != 3 {
+		t.Fatalf("newBmclibSensors() = %+v, want temperature, fan and psu", sensors)
+	}
+	if psu := sensors[2]; psu.Status != SensorStatusCritical || *psu.Value != 500 || *psu.Thresholds.UpperCritical != 2700 {
+		t.Errorf("newBmclibSensors() psu = %+v", psu)
+	}
+
+	noTemp := func() (int, error) { return 0, errors.New("not supported") }
+	if _, err := newBmclibSensors(noTemp, noPower, nil, nil); err == nil {
+		t.Errorf("newBmclibSensors() without readings succeeded")
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
 	"sync"
 
 	"github.com/bmc-toolbox/actor/internal/providers/ipmi"
@@ -11,6 +12,7 @@ import (
 	"github.com/bmc-toolbox/bmclib/devices"
 	"github.com/bmc-toolbox/bmclib/discover"
 	bmcerrors "github.com/bmc-toolbox/bmclib/errors"
+	log "github.com/sirupsen/logrus"
 )
 
 type (
@@ -21,6 +23,8 @@ type (
 		initOnce     sync.Once
 		bmc          serverBmcProvider
 		screenshoter screenshot.BmcScreenshoter
+		// hardwareType is read once per connection, some BMCs are queried for it
+		hardwareType string
 	}
 
 	// this is abstraction over devices.Bmc and ipmi.Ipmi
@@ -55,6 +59,11 @@ func (w *ServerBmcWrapper) initBmcProvider() error {
 	return err
 }
 
+// Connect connects to the BMC, every other method connects on its first call
+func (w *ServerBmcWrapper) Connect() error {
+	return w.initBmcProvider()
+}
+
 func (w *ServerBmcWrapper) IsOn() (bool, error) {
 	if err := w.initBmcProvider(); err != nil {
 		return false, err
@@ -103,27 +112,168 @@ func (w *ServerBmcWrapper) Screenshot() ([]byte, string, error) {
 	}
 
 	if w.screenshoter == nil {
-		return nil, "", fmt.Errorf("BMC provider does not support the method Screenshot()")
+		return nil, "", screenshot.ErrUnsupported
 	}
 
 	return w.screenshoter.Screenshot()
 }
 
+// HardwareType returns "unknown" when the BMC can't be reached or sits behind the IPMI fallback
 func (w *ServerBmcWrapper) HardwareType() string {
+	if err := w.initBmcProvider(); err != nil || w.screenshoter == nil {
+		return unknownHardwareType
+	}
+
+	if w.hardwareType == "" {
+		w.hardwareType = w.screenshoter.HardwareType()
+	}
+	return w.hardwareType
+}
+
+// SupportsScreenshot tells from the hardware type whether the BMC takes screenshots, without querying the BMC further
+func (w *ServerBmcWrapper) SupportsScreenshot() (bool, error) {
 	if err := w.initBmcProvider(); err != nil {
-		panic("failed to initialize BMC provider")
+		return false, err
 	}
 
-	if w.screenshoter == nil {
-		panic("BMC provider does not support the method HardwareType()")
+	return w.screenshoter != nil && SupportsScreenshot(w.HardwareType()), nil
+}
+
+// Info describes the connected BMC, it never fails on the IPMI fallback which has no vendor data
+func (w *ServerBmcWrapper) Info() (BmcInfo, error) {
+	if err := w.initBmcProvider(); err != nil {
+		return BmcInfo{}, err
+	}
+
+	bmc, ok := w.bmc.(devices.Bmc)
+	if !ok {
+		return BmcInfo{Provider: ProviderIpmi}, nil
+	}
+
+	// the firmware version is informational, a failure to read it shouldn't hide the rest
+	firmwareVersion, err := bmc.CheckFirmwareVersion()
+	if err != nil {
+		log.WithField("ip", w.host).WithError(err).Warn("failed to read the BMC firmware version")
+	}
+
+	hardwareType := w.HardwareType()
+	return BmcInfo{
+		Provider:           ProviderBmclib,
+		Vendor:             bmc.Vendor(),
+		HardwareType:       hardwareType,
+		FirmwareVersion:    firmwareVersion,
+		SupportsScreenshot: SupportsScreenshot(hardwareType),
+	}, nil
+}
+
+// CheckCredentials checks the BMC accepts the credentials of the wrapper, the IPMI fallback checks them with a power status
+func (w *ServerBmcWrapper) CheckCredentials() error {
+	if err := w.initBmcProvider(); err != nil {
+		return err
+	}
+
+	if bmc, ok := w.bmc.(devices.Bmc); ok {
+		return bmc.CheckCredentials()
+	}
+
+	_, err := w.bmc.IsOn()
+	return err
+}
+
+// UpdateFirmware updates the BMC firmware from source/file, the IPMI fallback can't update firmware
+func (w *ServerBmcWrapper) UpdateFirmware(source, file string) (bool, string, error) {
+	if err := w.initBmcProvider(); err != nil {
+		return false, "", err
+	}
+
+	bmc, ok := w.bmc.(devices.Bmc)
+	if !ok {
+		return false, "", fmt.Errorf("firmware updates are not supported by provider %s", ProviderIpmi)
+	}
+
+	return bmc.UpdateFirmware(source, file)
+}
+
+func (w *ServerBmcWrapper) CheckFirmwareVersion() (string, error) {
+	if err := w.initBmcProvider(); err != nil {
+		return "", err
+	}
+
+	bmc, ok := w.bmc.(devices.Bmc)
+	if !ok {
+		return "", fmt.Errorf("firmware versions are not supported by provider %s", ProviderIpmi)
+	}
+
+	return bmc.CheckFirmwareVersion()
+}
+
+// Configurer returns the bmclib configuration interface of the BMC, the IPMI fallback can't be configured
+func (w *ServerBmcWrapper) Configurer() (devices.Configure, error) {
+	if err := w.initBmcProvider(); err != nil {
+		return nil, err
+	}
+
+	bmc, ok := w.bmc.(devices.Bmc)
+	if !ok {
+		return nil, fmt.Errorf("configuration is not supported by provider %s", ProviderIpmi)
+	}
+
+	return bmc, nil
+}
+
+// TempC returns the temperature reported by bmclib or the inlet temperature read from the SDR on the IPMI fallback
+func (w *ServerBmcWrapper) TempC() (int, error) {
+	if err := w.initBmcProvider(); err != nil {
+		return 0, err
+	}
+
+	if bmc, ok := w.bmc.(devices.Bmc); ok {
+		return bmc.TempC()
+	}
+
+	sensors, err := w.Sensors()
+	if err != nil {
+		return 0, err
+	}
+
+	for _, sensor := range sensors {
+		name := strings.ToLower(sensor.Name)
+		if sensor.Type == SensorTemperature && sensor.Value != nil && (strings.Contains(name, "inlet") || strings.Contains(name, "ambient")) {
+			return int(*sensor.Value), nil
+		}
+	}
+
+	return 0, fmt.Errorf("no inlet temperature sensor found")
+}
+
+// Sensors reads the SDR on the IPMI fallback and the temperature and power draw reported by bmclib otherwise
+func (w *ServerBmcWrapper) Sensors() ([]Sensor, error) {
+	if err := w.initBmcProvider(); err != nil {
+		return nil, err
+	}
+
+	switch bmc := w.bmc.(type) {
+	case devices.Bmc:
+		return newBmclibSensors(bmc.TempC, bmc.PowerKw, nil, nil)
+	case *ipmi.Ipmi:
+		readings, err := bmc.ReadSensors()
+		if err != nil {
+			return nil, err
+		}
+
+		sensors := make([]Sensor, 0, len(readings))
+		for _, reading := range readings {
+			sensors = append(sensors, newIpmiSensor(reading))
+		}
+		return sensors, nil
 	}
 
-	return w.screenshoter.HardwareType()
+	return nil, fmt.Errorf("BMC provider does not support sensors")
 }
 
 func (w *ServerBmcWrapper) Close(context.Context) error {
 	if w.bmc != nil {
-		w.screenshoter = nil
+		w.screenshoter, w.hardwareType = nil, ""
 		return w.bmc.Close(context.TODO())
 	}
 	return nil
@@ -0,0 +1,363 @@
+package registry
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/bmc-toolbox/actor/internal/actions"
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	csvHost        = "host"
+	csvType        = "type"
+	csvChassis     = "chassis"
+	csvPasswordRef = "password-ref"
+)
+
+var (
+	// ErrNotFound is returned for hosts which aren't registered
+	ErrNotFound = errors.New("target not registered")
+
+	csvColumns = []string{csvHost, csvType, csvChassis, csvPasswordRef}
+)
+
+type (
+	// Target is a registered BMC, a host (possibly a blade of Chassis) or a chassis,
+	// PasswordRef references its BMC password in the credential store when it doesn't use the shared one
+	Target struct {
+		Host        string            `json:"host" yaml:"host"`
+		Type        string            `json:"type" yaml:"type"`
+		Chassis     string            `json:"chassis,omitempty" yaml:"chassis,omitempty"`
+		PasswordRef string            `json:"password-ref,omitempty" yaml:"password-ref,omitempty"`
+		Labels      map[string]string `json:"labels" yaml:"labels"`
+	}
+
+	// Registry keeps the known targets in a YAML file or, when its extension is .csv, a CSV file with a column
+	// per label, changes are written back to the file
+	Registry struct {
+		file string
+
+		mu      sync.RWMutex
+		targets map[string]Target
+	}
+)
+
+func New(file string) (*Registry, error) {
+	r := &Registry{file: file, targets: make(map[string]Target)}
+
+	if err := r.load(); err != nil {
+		return nil, fmt.Errorf("failed to load the registry from %s: %w", file, err)
+	}
+
+	return r, nil
+}
+
+func (t *Target) Validate() error {
+	if t.Host == "" {
+		return fmt.Errorf("invalid target: no host")
+	}
+
+	switch t.Type {
+	case "":
+		t.Type = actions.TargetHost
+	case actions.TargetHost:
+	case actions.TargetChassis:
+		if t.Chassis != "" {
+			return fmt.Errorf("invalid target %s: a chassis isn't part of a chassis", t.Host)
+		}
+	default:
+		return fmt.Errorf("invalid target %s: unknown type %q", t.Host, t.Type)
+	}
+
+	if err := ValidateLabels(t.Labels); err != nil {
+		return fmt.Errorf("invalid target %s: %w", t.Host, err)
+	}
+	if t.Labels == nil {
+		t.Labels = map[string]string{}
+	}
+
+	return nil
+}
+
+// ValidateLabels checks the label keys and values are ones selectors can match
+func ValidateLabels(labels map[string]string) error {
+	for key, value := range labels {
+		if !validLabel.MatchString(key) || !validLabel.MatchString(value) {
+			return fmt.Errorf("invalid label %s=%s", key, value)
+		}
+	}
+	return nil
+}
+
+// ActionTarget returns the target actions are carried out on, blades are addressed through their own BMC
+func (t Target) ActionTarget() actions.Target {
+	return actions.Target{Type: t.Type, Host: t.Host}
+}
+
+// List returns the targets matching the selector sorted by host
+func (r *Registry) List(selector Selector) []Target {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	targets := make([]Target, 0, len(r.targets))
+	for _, target := range r.sorted() {
+		if selector.Matches(target.Labels) {
+			targets = append(targets, copyTarget(target))
+		}
+	}
+	return targets
+}
+
+func (r *Registry) Get(host string) (Target, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	target, ok := r.targets[host]
+	if !ok {
+		return Target{}, fmt.Errorf("%w: %s", ErrNotFound, host)
+	}
+	return copyTarget(target), nil
+}
+
+// Put registers the target or replaces its registration
+func (r *Registry) Put(target Target) error {
+	if err := target.Validate(); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	previous, existed := r.targets[target.Host]
+	r.targets[target.Host] = copyTarget(target)
+
+	if err := r.save(); err != nil {
+		if existed {
+			r.targets[target.Host] = previous
+		} else {
+			delete(r.targets, target.Host)
+		}
+		return err
+	}
+	return nil
+}
+
+func (r *Registry) Delete(host string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	previous, ok := r.targets[host]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrNotFound, host)
+	}
+	delete(r.targets, host)
+
+	if err := r.save(); err != nil {
+		r.targets[host] = previous
+		return err
+	}
+	return nil
+}
+
+func (r *Registry) IsRegistered(host string) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	_, ok := r.targets[host]
+	return ok
+}
+
+// PasswordRef returns the reference of the BMC password of the host, if it has one
+func (r *Registry) PasswordRef(host string) (string, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	target, ok := r.targets[host]
+	if !ok || target.PasswordRef == "" {
+		return "", false
+	}
+	return target.PasswordRef, true
+}
+
+// Chassis returns the hosts of the registered chassis
+func (r *Registry) Chassis() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var chassis []string
+	for _, target := range r.sorted() {
+		if target.Type == actions.TargetChassis {
+			chassis = append(chassis, target.Host)
+		}
+	}
+	return chassis
+}
+
+func (r *Registry) load() error {
+	data, err := ioutil.ReadFile(r.file)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	var targets []Target
+	if r.isCSV() {
+		targets, err = parseCSV(data)
+	} else {
+		err = yaml.UnmarshalStrict(data, &targets)
+	}
+	if err != nil {
+		return err
+	}
+
+	for _, target := range targets {
+		if err := target.Validate(); err != nil {
+			return err
+		}
+		if _, ok := r.targets[target.Host]; ok {
+			return fmt.Errorf("target %s is registered twice", target.Host)
+		}
+		r.targets[target.Host] = target
+	}
+
+	return nil
+}
+
+func (r *Registry) save() error {
+	var data []byte
+	var err error
+
+	if r.isCSV() {
+		data, err = formatCSV(r.sorted())
+	} else {
+		data, err = yaml.Marshal(r.sorted())
+	}
+	if err != nil {
+		return fmt.Errorf("failed to marshal the registry: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(r.file), 0755); err != nil {
+		return fmt.Errorf("failed to create the directory for %s: %w", r.file, err)
+	}
+
+	tmpFile := r.file + ".tmp"
+	if err := ioutil.WriteFile(tmpFile, data, 0644); err != nil {
+		return fmt.Errorf("failed to write the registry: %w", err)
+	}
+
+	if err := os.Rename(tmpFile, r.file); err != nil {
+		return fmt.Errorf("failed to write the registry: %w", err)
+	}
+
+	return nil
+}
+
+func (r *Registry) isCSV() bool {
+	return strings.EqualFold(filepath.Ext(r.file), ".csv")
+}
+
+func (r *Registry) sorted() []Target {
+	targets := make([]Target, 0, len(r.targets))
+	for _, target := range r.targets {
+		targets = append(targets, target)
+	}
+
+	sort.Slice(targets, func(i, j int) bool { return targets[i].Host < targets[j].Host })
+
+	return targets
+}
+
+// parseCSV reads targets from a CSV file with a header, the columns which aren't target fields are labels
+// and empty cells are labels the target doesn't have
+func parseCSV(data []byte) ([]Target, error) {
+	records, err := csv.NewReader(bytes.NewReader(data)).ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, nil
+	}
+
+	header := records[0]
+	targets := make([]Target, 0, len(records)-1)
+
+	for _, record := range records[1:] {
+		target := Target{Labels: map[string]string{}}
+
+		for i, column := range header {
+			value := strings.TrimSpace(record[i])
+			switch strings.TrimSpace(column) {
+			case csvHost:
+				target.Host = value
+			case csvType:
+				target.Type = value
+			case csvChassis:
+				target.Chassis = value
+			case csvPasswordRef:
+				target.PasswordRef = value
+			default:
+				if value != "" {
+					target.Labels[strings.TrimSpace(column)] = value
+				}
+			}
+		}
+
+		targets = append(targets, target)
+	}
+
+	return targets, nil
+}
+
+func formatCSV(targets []Target) ([]byte, error) {
+	keys := make(map[string]bool)
+	for _, target := range targets {
+		for key := range target.Labels {
+			keys[key] = true
+		}
+	}
+
+	labels := make([]string, 0, len(keys))
+	for key := range keys {
+		labels = append(labels, key)
+	}
+	sort.Strings(labels)
+
+	buf := &bytes.Buffer{}
+	w := csv.NewWriter(buf)
+
+	if err := w.Write(append(append([]string{}, csvColumns...), labels...)); err != nil {
+		return nil, err
+	}
+	for _, target := range targets {
+		record := []string{target.Host, target.Type, target.Chassis, target.PasswordRef}
+		for _, key := range labels {
+			record = append(record, target.Labels[key])
+		}
+		if err := w.Write(record); err != nil {
+			return nil, err
+		}
+	}
+	w.Flush()
+
+	return buf.Bytes(), w.Error()
+}
+
+func copyTarget(target Target) Target {
+	labels := make(map[string]string, len(target.Labels))
+	for key, value := range target.Labels {
+		labels[key] = value
+	}
+	target.Labels = labels
+	return target
+}
@@ -0,0 +1,138 @@
+package registry
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestSelector(t *testing.T) {
+	labels := map[string]string{"rack": "r12", "role": "compute", "site": "ams1"}
+
+	tests := []struct {
+		selector string
+		want     bool
+		wantErr  bool
+	}{
+		{selector: "", want: true},
+		{selector: "rack=r12,role=compute", want: true},
+		{selector: "rack=r12, role=storage", want: false},
+		{selector: "site!=fra1", want: true},
+		{selector: "site!=ams1", want: false},
+		{selector: "vendor!=dell", want: true},
+		{selector: "site", want: true},
+		{selector: "vendor", want: false},
+		{selector: "site=ams1,rack in (r1,r12)", want: true},
+		{selector: "rack in (r1, r2)", want: false},
+		{selector: "rack notin (r1,r2),role=compute", want: true},
+		{selector: "vendor notin (dell)", want: true},
+		{selector: "rack in (r1,(r2))", wantErr: true},
+		{selector: "rack in (r1", wantErr: true},
+		{selector: "rack=", wantErr: true},
+		{selector: "rack=r 12", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.selector, func(t *testing.T) {
+			selector, err := ParseSelector(tt.selector)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseSelector() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if got := selector.Matches(labels); got != tt.want {
+				t.Errorf("Matches() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRegistry(t *testing.T) {
+	for _, name := range []string{"targets.yaml", "targets.csv"} {
+		t.Run(name, func(t *testing.T) {
+			dir, err := ioutil.TempDir("", "registry")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer os.RemoveAll(dir)
+			file := filepath.Join(dir, name)
+
+			r, err := New(file)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			targets := []Target{
+				{Host: "10.0.0.1", Type: "chassis", Labels: map[string]string{"rack": "r12"}},
+				{Host: "10.0.0.2", Type: "host", Chassis: "10.0.0.1", PasswordRef: "blades/r12", Labels: map[string]string{"rack": "r12", "role": "compute"}},
+				{Host: "10.0.0.3", Labels: map[string]string{"rack": "r13", "role": "storage"}},
+			}
+			for _, target := range targets {
+				if err := r.Put(target); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			// the registry written to the file has to be read back as it was
+			r, err = New(file)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			selector, _ := ParseSelector("rack=r12")
+			got := r.List(selector)
+			if len(got) != 2 || got[0].Host != "10.0.0.1" || got[1].Host != "10.0.0.2" {
+				t.Fatalf("List() = %+v, want 10.0.0.1 and 10.0.0.2", got)
+			}
+			if !reflect.DeepEqual(got[1], targets[1]) {
+				t.Errorf("List() = %+v, want %+v", got[1], targets[1])
+			}
+
+			if ref, ok := r.PasswordRef("10.0.0.2"); !ok || ref != "blades/r12" {
+				t.Errorf("PasswordRef() = %s, %v, want blades/r12", ref, ok)
+			}
+			if _, ok := r.PasswordRef("10.0.0.3"); ok {
+				t.Errorf("PasswordRef() found a reference for a target without one")
+			}
+			if got := r.Chassis(); !reflect.DeepEqual(got, []string{"10.0.0.1"}) {
+				t.Errorf("Chassis() = %v, want [10.0.0.1]", got)
+			}
+
+			if err := r.Delete("10.0.0.3"); err != nil {
+				t.Fatal(err)
+			}
+			if err := r.Delete("10.0.0.3"); !errors.Is(err, ErrNotFound) {
+				t.Errorf("Delete() error = %v, want %v", err, ErrNotFound)
+			}
+			if r.IsRegistered("10.0.0.3") {
+				t.Errorf("IsRegistered() = true for a deleted target")
+			}
+		})
+	}
+}
+
+func TestTarget_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		target  Target
+		wantErr bool
+	}{
+		{name: "host", target: Target{Host: "10.0.0.1"}},
+		{name: "no host", target: Target{Type: "host"}, wantErr: true},
+		{name: "unknown type", target: Target{Host: "10.0.0.1", Type: "switch"}, wantErr: true},
+		{name: "chassis in chassis", target: Target{Host: "10.0.0.1", Type: "chassis", Chassis: "10.0.0.2"}, wantErr: true},
+		{name: "invalid label", target: Target{Host: "10.0.0.1", Labels: map[string]string{"rack": "r,12"}}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.target.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,168 @@
+package registry
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+const (
+	opExists    = ""
+	opEquals    = "="
+	opNotEquals = "!="
+	opIn        = "in"
+	opNotIn     = "notin"
+)
+
+var (
+	// validLabel restricts label keys and values so selectors stay unambiguous
+	validLabel = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._/-]*$`)
+
+	// setRequirement matches "key in (v1,v2)" and "key notin (v1,v2)"
+	setRequirement = regexp.MustCompile(`^(\S+)\s+(in|notin)\s*\((.*)\)$`)
+)
+
+type (
+	// Selector matches the labels of targets, every requirement has to hold
+	Selector []requirement
+
+	requirement struct {
+		key    string
+		op     string
+		values []string
+	}
+)
+
+// ParseSelector parses comma separated requirements: key=value, key!=value, key in (v1,v2), key notin (v1,v2)
+// or key for targets having the label, an empty selector matches every target
+func ParseSelector(selector string) (Selector, error) {
+	var requirements Selector
+
+	parts, err := splitRequirements(selector)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, part := range parts {
+		r, err := parseRequirement(part)
+		if err != nil {
+			return nil, err
+		}
+		requirements = append(requirements, r)
+	}
+
+	return requirements, nil
+}
+
+func (s Selector) Matches(labels map[string]string) bool {
+	for _, r := range s {
+		value, ok := labels[r.key]
+		switch r.op {
+		case opExists:
+			if !ok {
+				return false
+			}
+		case opEquals, opIn:
+			if !ok || !contains(r.values, value) {
+				return false
+			}
+		case opNotEquals, opNotIn:
+			if ok && contains(r.values, value) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func (s Selector) String() string {
+	parts := make([]string, 0, len(s))
+	for _, r := range s {
+		switch r.op {
+		case opExists:
+			parts = append(parts, r.key)
+		case opEquals, opNotEquals:
+			parts = append(parts, r.key+r.op+r.values[0])
+		default:
+			parts = append(parts, fmt.Sprintf("%s %s (%s)", r.key, r.op, strings.Join(r.values, ",")))
+		}
+	}
+	return strings.Join(parts, ",")
+}
+
+// splitRequirements splits the selector on the commas which aren't within the parentheses of a set
+func splitRequirements(selector string) ([]string, error) {
+	var parts []string
+	depth, start := 0, 0
+
+	for i, c := range selector {
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case ',':
+			if depth == 0 {
+				parts = append(parts, selector[start:i])
+				start = i + 1
+			}
+		}
+		if depth < 0 || depth > 1 {
+			return nil, fmt.Errorf("invalid selector %q: unbalanced parentheses", selector)
+		}
+	}
+	if depth != 0 {
+		return nil, fmt.Errorf("invalid selector %q: unbalanced parentheses", selector)
+	}
+	parts = append(parts, selector[start:])
+
+	nonEmpty := parts[:0]
+	for _, part := range parts {
+		if part = strings.TrimSpace(part); part != "" {
+			nonEmpty = append(nonEmpty, part)
+		}
+	}
+	return nonEmpty, nil
+}
+
+func parseRequirement(part string) (requirement, error) {
+	var r requirement
+
+	switch {
+	case setRequirement.MatchString(part):
+		match := setRequirement.FindStringSubmatch(part)
+		r = requirement{key: match[1], op: match[2]}
+		for _, value := range strings.Split(match[3], ",") {
+			r.values = append(r.values, strings.TrimSpace(value))
+		}
+	case strings.Contains(part, opNotEquals):
+		kv := strings.SplitN(part, opNotEquals, 2)
+		r = requirement{key: kv[0], op: opNotEquals, values: []string{strings.TrimSpace(kv[1])}}
+	case strings.Contains(part, opEquals):
+		kv := strings.SplitN(part, opEquals, 2)
+		r = requirement{key: kv[0], op: opEquals, values: []string{strings.TrimSpace(kv[1])}}
+	default:
+		r = requirement{key: part, op: opExists}
+	}
+
+	r.key = strings.TrimSpace(r.key)
+	if !validLabel.MatchString(r.key) {
+		return requirement{}, fmt.Errorf("invalid selector requirement %q", part)
+	}
+	for _, value := range r.values {
+		if !validLabel.MatchString(value) {
+			return requirement{}, fmt.Errorf("invalid selector requirement %q: invalid value %q", part, value)
+		}
+	}
+
+	return r, nil
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,148 @@
+package internal
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/bmc-toolbox/actor/internal/actions"
+)
+
+const (
+	requirePrefix = "require "
+
+	conditionPsuRedundant = "psu-redundant"
+	conditionOn           = "on"
+	conditionOff          = "off"
+	conditionTemp         = "temp"
+)
+
+// the longer operators come first so "<=" isn't parsed as "<"
+var temperatureOperators = []string{"<=", ">=", "<", ">"}
+
+type (
+	// requirement is a parsed "require <condition>" step
+	requirement struct {
+		condition string
+		operator  string
+		limit     float64
+	}
+
+	// requirementProbes read the state checked by the requirements, a nil probe isn't supported by the target
+	requirementProbes struct {
+		isOn           func() (bool, error)
+		tempC          func() (int, error)
+		isPsuRedundant func() (bool, error)
+	}
+)
+
+func isRequireAction(action string) bool {
+	return strings.HasPrefix(action, requirePrefix)
+}
+
+// parseRequirement parses "require psu-redundant", "require on", "require off" and "require temp<35"
+// with any of the <, <=, > and >= operators
+func parseRequirement(action string) (requirement, error) {
+	condition := strings.ReplaceAll(strings.TrimPrefix(action, requirePrefix), " ", "")
+
+	switch condition {
+	case conditionPsuRedundant, conditionOn, conditionOff:
+		return requirement{condition: condition}, nil
+	}
+
+	if strings.HasPrefix(condition, conditionTemp) {
+		rest := strings.TrimPrefix(condition, conditionTemp)
+		for _, operator := range temperatureOperators {
+			if !strings.HasPrefix(rest, operator) {
+				continue
+			}
+
+			limit, err := strconv.ParseFloat(strings.TrimPrefix(rest, operator), 64)
+			if err != nil {
+				return requirement{}, fmt.Errorf("invalid temperature in %q: %w", action, err)
+			}
+			return requirement{condition: conditionTemp, operator: operator, limit: limit}, nil
+		}
+	}
+
+	return requirement{}, fmt.Errorf("unknown requirement %q", action)
+}
+
+func (p requirementProbes) validate(action string) error {
+	r, err := parseRequirement(action)
+	if err != nil {
+		return err
+	}
+
+	var supported bool
+	switch r.condition {
+	case conditionPsuRedundant:
+		supported = p.isPsuRedundant != nil
+	case conditionOn, conditionOff:
+		supported = p.isOn != nil
+	case conditionTemp:
+		supported = p.tempC != nil
+	}
+
+	if !supported {
+		return fmt.Errorf("requirement %q is not supported by the target", action)
+	}
+
+	return nil
+}
+
+// check fails when the condition doesn't hold, so the plan stops before the following steps
+func (p requirementProbes) check(action string) actions.ActionResult {
+	if err := p.validate(action); err != nil {
+		return actions.NewActionResult(action, false, "failed", err)
+	}
+
+	r, _ := parseRequirement(action)
+
+	switch r.condition {
+	case conditionPsuRedundant:
+		redundant, err := p.isPsuRedundant()
+		if err != nil {
+			return actions.NewActionResult(action, false, "failed", err)
+		}
+		if !redundant {
+			return actions.NewActionResult(action, false, "failed", fmt.Errorf("requirement not met: the power supplies are not redundant"))
+		}
+		return actions.NewActionResult(action, true, "power supplies are redundant", nil)
+
+	case conditionOn, conditionOff:
+		isOn, err := p.isOn()
+		if err != nil {
+			return actions.NewActionResult(action, false, "failed", err)
+		}
+		if isOn != (r.condition == conditionOn) {
+			return actions.NewActionResult(action, false, "failed", fmt.Errorf("requirement not met: the target is not powered %s", r.condition))
+		}
+		return actions.NewActionResult(action, true, "powered "+r.condition, nil)
+
+	default:
+		temp, err := p.tempC()
+		if err != nil {
+			return actions.NewActionResult(action, false, "failed", err)
+		}
+		message := fmt.Sprintf("temperature is %dC", temp)
+		if !compareTemperature(float64(temp), r.operator, r.limit) {
+			return actions.NewActionResult(action, false, message, fmt.Errorf("requirement not met: %s, expected %s%v", message, r.operator, r.limit))
+		}
+		return actions.NewActionResult(action, true, message, nil)
+	}
+}
+
+func compareTemperature(temp float64, operator string, limit float64) bool {
+	switch operator {
+	case "<":
+		return temp < limit
+	case "<=":
+		return temp <= limit
+	case ">":
+		return temp > limit
+	case ">=":
+		return temp >= limit
+	}
+	return false
+}
@@ -0,0 +1,77 @@
+package internal
+
+import (
+	"errors"
+	"testing"
+)
+
+func Test_parseRequirement(t *testing.T) {
+	tests := []struct {
+		name    string
+		action  string
+		want    requirement
+		wantErr bool
+	}{
+		{name: "PSU redundant", action: "require psu-redundant", want: requirement{condition: conditionPsuRedundant}},
+		{name: "Power on", action: "require on", want: requirement{condition: conditionOn}},
+		{name: "Temperature", action: "require temp<35", want: requirement{condition: conditionTemp, operator: "<", limit: 35}},
+		{name: "Temperature with spaces", action: "require temp >= 10.5", want: requirement{condition: conditionTemp, operator: ">=", limit: 10.5}},
+		{name: "Temperature without limit", action: "require temp<", wantErr: true},
+		{name: "Temperature without operator", action: "require temp35", wantErr: true},
+		{name: "Unknown condition", action: "require fans", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseRequirement(tt.action)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseRequirement() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("parseRequirement() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_requirementProbes_check(t *testing.T) {
+	probes := requirementProbes{
+		isOn:           func() (bool, error) { return true, nil },
+		tempC:          func() (int, error) { return 30, nil },
+		isPsuRedundant: func() (bool, error) { return false, nil },
+	}
+
+	tests := []struct {
+		name   string
+		probes requirementProbes
+		action string
+		want   bool
+	}{
+		{name: "Powered on", probes: probes, action: "require on", want: true},
+		{name: "Not powered off", probes: probes, action: "require off"},
+		{name: "Temperature below", probes: probes, action: "require temp<35", want: true},
+		{name: "Temperature above", probes: probes, action: "require temp>30"},
+		{name: "Temperature at limit", probes: probes, action: "require temp>=30", want: true},
+		{name: "PSU not redundant", probes: probes, action: "require psu-redundant"},
+		{name: "Unsupported", probes: requirementProbes{isOn: probes.isOn}, action: "require psu-redundant"},
+		{
+			name:   "Probe error",
+			probes: requirementProbes{tempC: func() (int, error) { return 0, errors.New("unreachable") }},
+			action: "require temp<35",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.probes.check(tt.action)
+			if got.Status != tt.want {
+				t.Errorf("check() = %+v, want status %v", got, tt.want)
+			}
+			if !got.Status && got.Error == nil {
+				t.Errorf("check() failed without an error")
+			}
+		})
+	}
+
+	if err := (requirementProbes{isOn: probes.isOn}).validate("require temp<35"); err == nil {
+		t.Errorf("validate() of an unsupported requirement should fail")
+	}
+}
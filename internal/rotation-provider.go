@@ -0,0 +1,35 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/bmc-toolbox/actor/internal/providers"
+	"github.com/bmc-toolbox/actor/internal/providers/ipmi"
+)
+
+// RotationProvider changes the password of the user actor connects with, through IPMI like the other
+// host user changes, and checks the new password through the vendor API
+type RotationProvider struct {
+	username string
+}
+
+func NewRotationProvider(username string) *RotationProvider {
+	return &RotationProvider{username: username}
+}
+
+func (p *RotationProvider) ChangePassword(host, currentPassword, newPassword string) error {
+	bmc, err := ipmi.New(p.username, currentPassword, host)
+	if err != nil {
+		return fmt.Errorf("failed to setup IPMI connection: %w", err)
+	}
+
+	return bmc.UpdateUser(p.username, newPassword, "")
+}
+
+func (p *RotationProvider) CheckCredentials(host, password string) error {
+	bmc := providers.NewServerBmcWrapper(p.username, password, host)
+	defer func() { _ = bmc.Close(context.TODO()) }()
+
+	return bmc.CheckCredentials()
+}
@@ -0,0 +1,405 @@
+package rotation
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// StatusRunning and the final statuses of jobs, a job is failed when any host failed
+	StatusRunning     = "running"
+	StatusDone        = "done"
+	StatusFailed      = "failed"
+	StatusInterrupted = "interrupted"
+
+	// HostPending and the statuses of hosts, a host is verified once the BMC accepts the new password
+	// and done once the keyring holds it
+	HostPending  = "pending"
+	HostVerified = "verified"
+	HostDone     = "done"
+	HostFailed   = "failed"
+)
+
+var (
+	// ErrNotFound is returned for unknown job IDs
+	ErrNotFound = errors.New("rotation not found")
+	// ErrRunning is returned when resuming a job which is still running
+	// and when a host of a new or resumed job is being rotated by another job
+	ErrRunning = errors.New("rotation is running")
+)
+
+type (
+	// Job rotates the BMC password of hosts to the password referenced in the credential store,
+	// the password itself is never kept in the state file
+	Job struct {
+		ID          string      `json:"id"`
+		PasswordRef string      `json:"password-ref"`
+		Status      string      `json:"status"`
+		Created     time.Time   `json:"created"`
+		Updated     time.Time   `json:"updated"`
+		Hosts       []HostState `json:"hosts"`
+	}
+
+	// HostState is the progress of the rotation of a host, Changed tells the BMC may already have the new password
+	HostState struct {
+		Host     string    `json:"host"`
+		Status   string    `json:"status"`
+		Changed  bool      `json:"changed"`
+		Attempts int       `json:"attempts"`
+		Error    string    `json:"error,omitempty"`
+		Updated  time.Time `json:"updated"`
+	}
+
+	// Provider changes the BMC password of hosts and checks that a password is accepted
+	Provider interface {
+		ChangePassword(host, currentPassword, newPassword string) error
+		CheckCredentials(host, password string) error
+	}
+
+	// Keyring keeps the BMC password actor uses for every host
+	Keyring interface {
+		Password(host string) string
+		SetPassword(host, password string) error
+	}
+
+	// Secrets holds the new passwords
+	Secrets interface {
+		Password(ref string) (string, error)
+	}
+
+	// Rotator runs the rotation jobs one host after the other and persists their progress,
+	// so jobs interrupted by failures or restarts can be resumed
+	Rotator struct {
+		provider  Provider
+		keyring   Keyring
+		secrets   Secrets
+		stateFile string
+
+		mu   sync.Mutex
+		jobs map[string]*Job
+		wg   sync.WaitGroup
+	}
+)
+
+func New(provider Provider, keyring Keyring, secrets Secrets, stateFile string) (*Rotator, error) {
+	r := &Rotator{
+		provider:  provider,
+		keyring:   keyring,
+		secrets:   secrets,
+		stateFile: stateFile,
+		jobs:      make(map[string]*Job),
+	}
+
+	if err := r.load(); err != nil {
+		return nil, fmt.Errorf("failed to load rotations from %s: %w", stateFile, err)
+	}
+
+	return r, nil
+}
+
+// Start creates a job rotating the password of the hosts and runs it in the background
+func (r *Rotator) Start(hosts []string, passwordRef string) (Job, error) {
+	if len(hosts) == 0 {
+		return Job{}, fmt.Errorf("no hosts to rotate")
+	}
+	if _, err := r.secrets.Password(passwordRef); err != nil {
+		return Job{}, err
+	}
+
+	id, err := newID()
+	if err != nil {
+		return Job{}, err
+	}
+
+	now := time.Now().UTC()
+	job := &Job{ID: id, PasswordRef: passwordRef, Status: StatusRunning, Created: now, Updated: now}
+
+	seen := make(map[string]bool, len(hosts))
+	for _, host := range hosts {
+		if host == "" || seen[host] {
+			continue
+		}
+		seen[host] = true
+		job.Hosts = append(job.Hosts, HostState{Host: host, Status: HostPending, Updated: now})
+	}
+
+	r.mu.Lock()
+	if err := r.checkBusy(job); err != nil {
+		r.mu.Unlock()
+		return Job{}, err
+	}
+	r.jobs[id] = job
+	err = r.save()
+	jobCopy := copyJob(job)
+	r.mu.Unlock()
+
+	if err != nil {
+		return Job{}, err
+	}
+
+	r.wg.Add(1)
+	go r.run(job)
+
+	return jobCopy, nil
+}
+
+// Resume runs a job again for the hosts which aren't done
+func (r *Rotator) Resume(id string) (Job, error) {
+	r.mu.Lock()
+	job, ok := r.jobs[id]
+	if !ok {
+		r.mu.Unlock()
+		return Job{}, ErrNotFound
+	}
+	if job.Status == StatusRunning {
+		r.mu.Unlock()
+		return Job{}, ErrRunning
+	}
+	if err := r.checkBusy(job); err != nil {
+		r.mu.Unlock()
+		return Job{}, err
+	}
+
+	job.Status = StatusRunning
+	job.Updated = time.Now().UTC()
+	err := r.save()
+	jobCopy := copyJob(job)
+	r.mu.Unlock()
+
+	if err != nil {
+		return Job{}, err
+	}
+
+	r.wg.Add(1)
+	go r.run(job)
+
+	return jobCopy, nil
+}
+
+// checkBusy fails when a host of the job which isn't done is rotated by another running job,
+// two jobs would race on the password of the BMC, the caller must hold the lock
+func (r *Rotator) checkBusy(job *Job) error {
+	for _, other := range r.jobs {
+		if other.ID == job.ID || other.Status != StatusRunning {
+			continue
+		}
+
+		running := make(map[string]bool, len(other.Hosts))
+		for _, state := range other.Hosts {
+			running[state.Host] = true
+		}
+		for _, state := range job.Hosts {
+			if state.Status != HostDone && running[state.Host] {
+				return fmt.Errorf("%w: %s is rotated by %s", ErrRunning, state.Host, other.ID)
+			}
+		}
+	}
+
+	return nil
+}
+
+func (r *Rotator) Get(id string) (Job, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	job, ok := r.jobs[id]
+	if !ok {
+		return Job{}, ErrNotFound
+	}
+	return copyJob(job), nil
+}
+
+func (r *Rotator) List() []Job {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	jobs := make([]Job, 0, len(r.jobs))
+	for _, job := range r.sorted() {
+		jobs = append(jobs, copyJob(job))
+	}
+	return jobs
+}
+
+// Wait blocks until the running jobs are finished
+func (r *Rotator) Wait() {
+	r.wg.Wait()
+}
+
+func (r *Rotator) run(job *Job) {
+	defer r.wg.Done()
+
+	logger := log.WithField("rotation", job.ID)
+
+	newPassword, err := r.secrets.Password(job.PasswordRef)
+
+	for i := range job.Hosts {
+		r.mu.Lock()
+		state := job.Hosts[i]
+		r.mu.Unlock()
+
+		if state.Status == HostDone {
+			continue
+		}
+
+		if err == nil {
+			state = r.rotate(job, i, newPassword)
+		} else {
+			state = r.updateHost(job, i, func(h *HostState) { h.Status, h.Error = HostFailed, err.Error() })
+		}
+
+		if state.Status == HostDone {
+			logger.WithField("ip", state.Host).Info("BMC password rotated")
+		} else {
+			logger.WithField("ip", state.Host).Error("BMC password rotation failed: " + state.Error)
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	job.Status = StatusDone
+	for _, state := range job.Hosts {
+		if state.Status != HostDone {
+			job.Status = StatusFailed
+		}
+	}
+	job.Updated = time.Now().UTC()
+	if err := r.save(); err != nil {
+		logger.WithError(err).Error("failed to save rotations")
+	}
+}
+
+// rotate changes the password of the host, checks the BMC accepts it and only then updates the keyring,
+// a host which may already have the new password is checked first so it isn't changed twice
+func (r *Rotator) rotate(job *Job, i int, newPassword string) HostState {
+	state := r.updateHost(job, i, func(h *HostState) { h.Attempts++; h.Error = "" })
+
+	alreadyChanged := state.Changed && r.provider.CheckCredentials(state.Host, newPassword) == nil
+	if !alreadyChanged {
+		currentPassword := r.keyring.Password(state.Host)
+		if err := r.provider.ChangePassword(state.Host, currentPassword, newPassword); err != nil {
+			return r.fail(job, i, fmt.Errorf("failed to change the password: %w", err))
+		}
+		r.updateHost(job, i, func(h *HostState) { h.Changed = true })
+
+		if err := r.provider.CheckCredentials(state.Host, newPassword); err != nil {
+			return r.fail(job, i, fmt.Errorf("the new password is not accepted: %w", err))
+		}
+	}
+	r.updateHost(job, i, func(h *HostState) { h.Status = HostVerified })
+
+	if err := r.keyring.SetPassword(state.Host, newPassword); err != nil {
+		return r.fail(job, i, fmt.Errorf("failed to store the new password: %w", err))
+	}
+
+	return r.updateHost(job, i, func(h *HostState) { h.Status = HostDone })
+}
+
+func (r *Rotator) fail(job *Job, i int, err error) HostState {
+	return r.updateHost(job, i, func(h *HostState) { h.Status, h.Error = HostFailed, err.Error() })
+}
+
+// updateHost changes the state of a host and persists it right away, so every step survives a restart
+func (r *Rotator) updateHost(job *Job, i int, fn func(*HostState)) HostState {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now().UTC()
+	fn(&job.Hosts[i])
+	job.Hosts[i].Updated = now
+	job.Updated = now
+
+	if err := r.save(); err != nil {
+		log.WithField("rotation", job.ID).WithError(err).Error("failed to save rotations")
+	}
+
+	return job.Hosts[i]
+}
+
+func (r *Rotator) load() error {
+	data, err := ioutil.ReadFile(r.stateFile)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	jobs := make([]*Job, 0)
+	if err := json.Unmarshal(data, &jobs); err != nil {
+		return err
+	}
+
+	for _, job := range jobs {
+		// jobs running when actor stopped wait to be resumed
+		if job.Status == StatusRunning {
+			job.Status = StatusInterrupted
+		}
+		r.jobs[job.ID] = job
+	}
+
+	return nil
+}
+
+func (r *Rotator) save() error {
+	data, err := json.MarshalIndent(r.sorted(), "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal rotations: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(r.stateFile), 0755); err != nil {
+		return fmt.Errorf("failed to create the directory for %s: %w", r.stateFile, err)
+	}
+
+	tmpFile := r.stateFile + ".tmp"
+	if err := ioutil.WriteFile(tmpFile, data, 0600); err != nil {
+		return fmt.Errorf("failed to write rotations: %w", err)
+	}
+
+	if err := os.Rename(tmpFile, r.stateFile); err != nil {
+		return fmt.Errorf("failed to write rotations: %w", err)
+	}
+
+	return nil
+}
+
+func (r *Rotator) sorted() []*Job {
+	jobs := make([]*Job, 0, len(r.jobs))
+	for _, job := range r.jobs {
+		jobs = append(jobs, job)
+	}
+
+	sort.Slice(jobs, func(i, j int) bool {
+		if jobs[i].Created.Equal(jobs[j].Created) {
+			return jobs[i].ID < jobs[j].ID
+		}
+		return jobs[i].Created.Before(jobs[j].Created)
+	})
+
+	return jobs
+}
+
+func copyJob(job *Job) Job {
+	jobCopy := *job
+	jobCopy.Hosts = append([]HostState{}, job.Hosts...)
+	return jobCopy
+}
+
+func newID() (string, error) {
+	buf := make([]byte, 8)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate an ID: %w", err)
+	}
+	return hex.EncodeToString(buf), nil
+}
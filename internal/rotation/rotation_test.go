@@ -0,0 +1,191 @@
+package rotation
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+type testSecrets map[string]string
+
+func (s testSecrets) Password(ref string) (string, error) {
+	password, ok := s[ref]
+	if !ok {
+		return "", errors.New("not found")
+	}
+	return password, nil
+}
+
+type testKeyring struct {
+	mu        sync.Mutex
+	passwords map[string]string
+}
+
+func (k *testKeyring) Password(host string) string {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	if password, ok := k.passwords[host]; ok {
+		return password
+	}
+	return "old"
+}
+
+func (k *testKeyring) SetPassword(host, password string) error {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	k.passwords[host] = password
+	return nil
+}
+
+// testProvider plays BMCs which accept their current password, hosts in rejectChange refuse the change
+// and hosts in rejectCheck change the password but fail the check
+type testProvider struct {
+	mu           sync.Mutex
+	bmcPasswords map[string]string
+	rejectChange map[string]bool
+	rejectCheck  map[string]bool
+	hold         chan struct{}
+	changes      int
+}
+
+func (p *testProvider) ChangePassword(host, currentPassword, newPassword string) error {
+	if p.hold != nil {
+		<-p.hold
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.rejectChange[host] || p.bmcPasswords[host] != currentPassword {
+		return errors.New("access denied")
+	}
+	p.bmcPasswords[host] = newPassword
+	p.changes++
+	return nil
+}
+
+func (p *testProvider) CheckCredentials(host, password string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.rejectCheck[host] || p.bmcPasswords[host] != password {
+		return errors.New("login failed")
+	}
+	return nil
+}
+
+func newTestRotator(t *testing.T, provider *testProvider, keyring *testKeyring) (*Rotator, string) {
+	dir, err := ioutil.TempDir("", "rotation")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = os.RemoveAll(dir) })
+
+	stateFile := filepath.Join(dir, "rotations.json")
+	r, err := New(provider, keyring, testSecrets{"bmc/new": "new"}, stateFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return r, stateFile
+}
+
+func TestRotator(t *testing.T) {
+	provider := &testProvider{
+		bmcPasswords: map[string]string{"host1": "old", "host2": "old", "host3": "old"},
+		rejectChange: map[string]bool{"host2": true},
+		rejectCheck:  map[string]bool{"host3": true},
+	}
+	keyring := &testKeyring{passwords: map[string]string{}}
+	r, stateFile := newTestRotator(t, provider, keyring)
+
+	if _, err := r.Start([]string{"host1"}, "bmc/missing"); err == nil {
+		t.Errorf("Start() with an unknown password-ref should fail")
+	}
+
+	job, err := r.Start([]string{"host1", "host2", "host3", "host1"}, "bmc/new")
+	if err != nil {
+		t.Fatal(err)
+	}
+	r.Wait()
+
+	job, _ = r.Get(job.ID)
+	if job.Status != StatusFailed || len(job.Hosts) != 3 {
+		t.Fatalf("Start() = %+v, want a failed job with 3 hosts", job)
+	}
+	want := map[string]string{"host1": HostDone, "host2": HostFailed, "host3": HostFailed}
+	for _, state := range job.Hosts {
+		if state.Status != want[state.Host] {
+			t.Errorf("host %s = %+v, want status %s", state.Host, state, want[state.Host])
+		}
+	}
+	if keyring.Password("host1") != "new" || keyring.Password("host3") != "old" {
+		t.Errorf("keyring = %v, only host1 should have the new password", keyring.passwords)
+	}
+
+	// host3 has the new password already, resuming stores it without changing it again
+	provider.rejectChange, provider.rejectCheck = nil, nil
+	changes := provider.changes
+	if _, err := r.Resume(job.ID); err != nil {
+		t.Fatal(err)
+	}
+	r.Wait()
+
+	job, _ = r.Get(job.ID)
+	if job.Status != StatusDone {
+		t.Errorf("Resume() = %+v, want a done job", job)
+	}
+	if provider.changes != changes+1 {
+		t.Errorf("Resume() changed %d passwords, want only the one of host2", provider.changes-changes)
+	}
+	if keyring.Password("host2") != "new" || keyring.Password("host3") != "new" {
+		t.Errorf("keyring = %v, all hosts should have the new password", keyring.passwords)
+	}
+
+	reloaded, err := New(provider, keyring, testSecrets{}, stateFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if jobs := reloaded.List(); len(jobs) != 1 || jobs[0].Status != StatusDone {
+		t.Errorf("List() after reload = %+v", jobs)
+	}
+	if _, err := reloaded.Resume("unknown"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Resume() error = %v, want %v", err, ErrNotFound)
+	}
+}
+
+func TestRotator_Busy(t *testing.T) {
+	provider := &testProvider{
+		bmcPasswords: map[string]string{"host1": "old", "host2": "old", "host3": "old"},
+		hold:         make(chan struct{}),
+	}
+	r, _ := newTestRotator(t, provider, &testKeyring{passwords: map[string]string{}})
+
+	running, err := r.Start([]string{"host1", "host2"}, "bmc/new")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := r.Start([]string{"host3", "host2"}, "bmc/new"); !errors.Is(err, ErrRunning) {
+		t.Errorf("Start() error = %v, want %v for a host rotated by %s", err, ErrRunning, running.ID)
+	}
+	other, err := r.Start([]string{"host3"}, "bmc/new")
+	if err != nil {
+		t.Errorf("Start() error = %v for a host no job rotates", err)
+	}
+
+	close(provider.hold)
+	r.Wait()
+
+	if _, err := r.Start([]string{"host2"}, "bmc/new"); err != nil {
+		t.Errorf("Start() error = %v once the other rotation is finished", err)
+	}
+	r.Wait()
+	if job, _ := r.Get(other.ID); job.Status != StatusDone {
+		t.Errorf("Get() = %+v, want a done job", job)
+	}
+}
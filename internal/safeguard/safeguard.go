@@ -0,0 +1,332 @@
+package safeguard
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	metrics "github.com/bmc-toolbox/gin-go-metrics"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// BreakerClosed lets destructive actions through, BreakerOpen halts them until the breaker is reset or cools down
+	BreakerClosed = "closed"
+	BreakerOpen   = "open"
+
+	rateWindow = time.Minute
+
+	defaultBreakerWindow = 5 * time.Minute
+)
+
+var (
+	// ErrLimited is returned for destructive actions beyond the rate or concurrency limits
+	ErrLimited = errors.New("destructive action refused by the blast-radius limits")
+	// ErrBreakerOpen is returned for destructive actions while the circuit breaker is open
+	ErrBreakerOpen = errors.New("destructive actions are halted by the circuit breaker")
+
+	// DefaultActions are the destructive actions when none are configured
+	DefaultActions = []string{"poweroff", "powercycle", "reseat", "powercyclebmc"}
+)
+
+type (
+	// Limits bound destructive actions, zero means unlimited
+	Limits struct {
+		PerMinute  int `mapstructure:"per_minute" json:"per-minute"`
+		Concurrent int `mapstructure:"concurrent" json:"concurrent"`
+	}
+
+	// BreakerConfig opens the breaker when at least MinActions destructive actions ran within Window
+	// and FailureRatio of them failed, the breaker closes by itself after Cooldown unless it is zero
+	BreakerConfig struct {
+		Window       time.Duration `mapstructure:"window" json:"window"`
+		MinActions   int           `mapstructure:"min_actions" json:"min-actions"`
+		FailureRatio float64       `mapstructure:"failure_ratio" json:"failure-ratio"`
+		Cooldown     time.Duration `mapstructure:"cooldown" json:"cooldown"`
+	}
+
+	Config struct {
+		Actions     []string      `mapstructure:"actions" json:"actions"`
+		Global      Limits        `mapstructure:"global" json:"global"`
+		PerIdentity Limits        `mapstructure:"per_identity" json:"per-identity"`
+		Breaker     BreakerConfig `mapstructure:"breaker" json:"breaker"`
+	}
+
+	// Status describes the limits, the current usage and the breaker
+	Status struct {
+		Config     Config           `json:"config"`
+		Breaker    BreakerStatus    `json:"breaker"`
+		Global     Usage            `json:"global"`
+		Identities map[string]Usage `json:"identities"`
+	}
+
+	BreakerStatus struct {
+		State    string     `json:"state"`
+		Opened   *time.Time `json:"opened,omitempty"`
+		Reason   string     `json:"reason,omitempty"`
+		Actions  int        `json:"actions"`
+		Failures int        `json:"failures"`
+	}
+
+	// Usage is the number of destructive actions started within the last minute and running
+	Usage struct {
+		LastMinute int `json:"last-minute"`
+		Running    int `json:"running"`
+	}
+
+	// Safeguard admits the destructive steps of plans within the limits while its breaker is closed,
+	// other steps are always admitted
+	Safeguard struct {
+		config      Config
+		destructive map[string]bool
+		now         func() time.Time
+
+		mu         sync.Mutex
+		global     usage
+		identities map[string]*usage
+		outcomes   []outcome
+		opened     *time.Time
+		reason     string
+	}
+
+	usage struct {
+		started []time.Time
+		running int
+	}
+
+	outcome struct {
+		at     time.Time
+		failed bool
+	}
+)
+
+func New(config Config) *Safeguard {
+	if len(config.Actions) == 0 {
+		config.Actions = DefaultActions
+	}
+	if config.Breaker.Window <= 0 {
+		config.Breaker.Window = defaultBreakerWindow
+	}
+
+	destructive := make(map[string]bool, len(config.Actions))
+	for _, action := range config.Actions {
+		destructive[action] = true
+	}
+
+	return &Safeguard{config: config, destructive: destructive, now: time.Now, identities: make(map[string]*usage)}
+}
+
+// IsDestructive tells whether the action, whatever its arguments, is limited
+func (s *Safeguard) IsDestructive(action string) bool {
+	fields := strings.Fields(action)
+	return len(fields) > 0 && s.destructive[fields[0]]
+}
+
+// Admit checks a destructive action against the breaker and the limits and counts it,
+// release records its outcome and must be called once it has run
+func (s *Safeguard) Admit(action, identity string) (func(error), error) {
+	if !s.IsDestructive(action) {
+		return func(error) {}, nil
+	}
+	if identity == "" {
+		identity = "unknown"
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	logger := log.WithField("action", action).WithField("identity", identity)
+
+	if s.isOpen(now) {
+		metrics.IncrCounter([]string{"errors", "safeguard", "breaker_open"}, 1)
+		logger.Warn("destructive action halted by the circuit breaker")
+		return nil, fmt.Errorf("%w since %s: %s", ErrBreakerOpen, s.opened.Format(time.RFC3339), s.reason)
+	}
+
+	s.pruneIdentities(now)
+	perIdentity, ok := s.identities[identity]
+	if !ok {
+		perIdentity = &usage{}
+		s.identities[identity] = perIdentity
+	}
+
+	for _, check := range []struct {
+		scope  string
+		usage  *usage
+		limits Limits
+	}{
+		{scope: "global", usage: &s.global, limits: s.config.Global},
+		{scope: "identity " + identity, usage: perIdentity, limits: s.config.PerIdentity},
+	} {
+		if err := check.usage.check(check.limits, now); err != nil {
+			metrics.IncrCounter([]string{"errors", "safeguard", "limited"}, 1)
+			logger.Warn("destructive action refused: " + err.Error())
+			return nil, fmt.Errorf("%w: %s %s", ErrLimited, check.scope, err)
+		}
+	}
+
+	s.global.start(now)
+	perIdentity.start(now)
+
+	var once sync.Once
+	return func(err error) {
+		once.Do(func() { s.release(identity, err) })
+	}, nil
+}
+
+// Reset closes the breaker and forgets the outcomes which opened it
+func (s *Safeguard) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.opened != nil {
+		log.WithField("reason", s.reason).Info("circuit breaker reset")
+	}
+	s.opened, s.reason = nil, ""
+	s.outcomes = nil
+}
+
+func (s *Safeguard) Status() Status {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	s.isOpen(now)
+	s.pruneOutcomes(now)
+	s.pruneIdentities(now)
+
+	status := Status{
+		Config:     s.config,
+		Breaker:    BreakerStatus{State: BreakerClosed, Reason: s.reason},
+		Global:     s.global.report(now),
+		Identities: make(map[string]Usage, len(s.identities)),
+	}
+	if s.opened != nil {
+		opened := *s.opened
+		status.Breaker.State, status.Breaker.Opened = BreakerOpen, &opened
+	}
+	for _, o := range s.outcomes {
+		status.Breaker.Actions++
+		if o.failed {
+			status.Breaker.Failures++
+		}
+	}
+
+	for identity, perIdentity := range s.identities {
+		status.Identities[identity] = perIdentity.report(now)
+	}
+
+	return status
+}
+
+func (s *Safeguard) release(identity string, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+
+	s.global.running--
+	if perIdentity, ok := s.identities[identity]; ok {
+		perIdentity.running--
+	}
+
+	s.outcomes = append(s.outcomes, outcome{at: now, failed: err != nil})
+	s.trip(now)
+	s.pruneIdentities(now)
+}
+
+// trip opens the breaker when the failure ratio within the window reaches the threshold, the caller must hold the lock
+func (s *Safeguard) trip(now time.Time) {
+	breaker := s.config.Breaker
+	if s.opened != nil || breaker.FailureRatio <= 0 {
+		return
+	}
+
+	s.pruneOutcomes(now)
+
+	failures := 0
+	for _, o := range s.outcomes {
+		if o.failed {
+			failures++
+		}
+	}
+
+	actions := len(s.outcomes)
+	if actions == 0 || actions < breaker.MinActions || float64(failures)/float64(actions) < breaker.FailureRatio {
+		return
+	}
+
+	s.opened = &now
+	s.reason = fmt.Sprintf("%d of the last %d destructive actions failed", failures, actions)
+	metrics.IncrCounter([]string{"errors", "safeguard", "breaker_tripped"}, 1)
+	log.WithField("reason", s.reason).Error("circuit breaker opened, destructive actions are halted")
+}
+
+// isOpen tells whether the breaker is open, closing it once it has cooled down, the caller must hold the lock
+func (s *Safeguard) isOpen(now time.Time) bool {
+	if s.opened == nil {
+		return false
+	}
+
+	if cooldown := s.config.Breaker.Cooldown; cooldown > 0 && now.Sub(*s.opened) >= cooldown {
+		log.WithField("reason", s.reason).Info("circuit breaker closed after its cooldown")
+		s.opened, s.reason = nil, ""
+		s.outcomes = nil
+		return false
+	}
+
+	return true
+}
+
+// pruneIdentities forgets the idle identities so they don't pile up, the caller must hold the lock
+func (s *Safeguard) pruneIdentities(now time.Time) {
+	for identity, perIdentity := range s.identities {
+		if u := perIdentity.report(now); u.LastMinute == 0 && u.Running == 0 {
+			delete(s.identities, identity)
+		}
+	}
+}
+
+// pruneOutcomes forgets the outcomes older than the window, the caller must hold the lock
+func (s *Safeguard) pruneOutcomes(now time.Time) {
+	window := s.config.Breaker.Window
+
+	i := 0
+	for i < len(s.outcomes) && now.Sub(s.outcomes[i].at) > window {
+		i++
+	}
+	s.outcomes = s.outcomes[i:]
+}
+
+func (u *usage) check(limits Limits, now time.Time) error {
+	u.prune(now)
+
+	if limits.PerMinute > 0 && len(u.started) >= limits.PerMinute {
+		return fmt.Errorf("limit of %d per minute reached", limits.PerMinute)
+	}
+	if limits.Concurrent > 0 && u.running >= limits.Concurrent {
+		return fmt.Errorf("limit of %d running at once reached", limits.Concurrent)
+	}
+	return nil
+}
+
+func (u *usage) start(now time.Time) {
+	u.started = append(u.started, now)
+	u.running++
+}
+
+func (u *usage) prune(now time.Time) {
+	i := 0
+	for i < len(u.started) && now.Sub(u.started[i]) >= rateWindow {
+		i++
+	}
+	u.started = u.started[i:]
+}
+
+func (u *usage) report(now time.Time) Usage {
+	u.prune(now)
+	return Usage{LastMinute: len(u.started), Running: u.running}
+}
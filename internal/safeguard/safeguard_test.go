@@ -0,0 +1,134 @@
+package safeguard
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func newTestSafeguard(config Config) (*Safeguard, *time.Time) {
+	now := time.Date(2021, 6, 1, 10, 0, 0, 0, time.UTC)
+	s := New(config)
+	s.now = func() time.Time { return now }
+	return s, &now
+}
+
+func TestSafeguard_Limits(t *testing.T) {
+	s, now := newTestSafeguard(Config{
+		Global:      Limits{Concurrent: 2},
+		PerIdentity: Limits{PerMinute: 2},
+	})
+
+	if _, err := s.Admit("ison", "alice"); err != nil {
+		t.Errorf("Admit() refused an action which isn't destructive: %v", err)
+	}
+
+	releaseA, err := s.Admit("poweroff", "alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	releaseA(nil)
+	if _, err := s.Admit("powercycle", "alice"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Admit("reseat", "alice"); !errors.Is(err, ErrLimited) {
+		t.Errorf("Admit() error = %v, want the per identity rate limit", err)
+	}
+
+	// alice still runs a powercycle, bob gets the last running slot
+	releaseB, err := s.Admit("poweroff", "bob")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Admit("poweroff", "carol"); !errors.Is(err, ErrLimited) {
+		t.Errorf("Admit() error = %v, want the global concurrency limit", err)
+	}
+	releaseB(nil)
+	releaseB(nil)
+
+	*now = now.Add(time.Minute)
+	release, err := s.Admit("reseat", "alice")
+	if err != nil {
+		t.Errorf("Admit() error = %v, want the rate limit to be over after a minute", err)
+	} else {
+		release(nil)
+	}
+
+	status := s.Status()
+	if status.Global.Running != 1 || status.Identities["alice"].LastMinute != 1 {
+		t.Errorf("Status() = %+v, want 1 running and 1 reseat of alice within the last minute", status)
+	}
+	if _, ok := status.Identities["bob"]; ok {
+		t.Errorf("Status() reports bob, who is idle")
+	}
+}
+
+func TestSafeguard_PruneIdentities(t *testing.T) {
+	s, now := newTestSafeguard(Config{Global: Limits{Concurrent: 1}})
+
+	release, err := s.Admit("poweroff", "alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Admit("poweroff", "bob"); !errors.Is(err, ErrLimited) {
+		t.Fatalf("Admit() error = %v, want the global concurrency limit", err)
+	}
+
+	*now = now.Add(time.Minute)
+	release(nil)
+
+	// Status is never called, admitting and releasing are enough to forget idle identities
+	if len(s.identities) != 0 {
+		t.Errorf("%d identities are kept, want the idle ones to be forgotten", len(s.identities))
+	}
+}
+
+func TestSafeguard_Breaker(t *testing.T) {
+	s, now := newTestSafeguard(Config{Breaker: BreakerConfig{Window: time.Minute, MinActions: 4, FailureRatio: 0.5, Cooldown: time.Hour}})
+
+	for i := 0; i < 3; i++ {
+		release, err := s.Admit("powercyclebmc", "alice")
+		if err != nil {
+			t.Fatal(err)
+		}
+		release(errors.New("unreachable"))
+	}
+	// the breaker needs more outcomes before it trips
+	if s.Status().Breaker.State != BreakerClosed {
+		t.Fatalf("breaker opened before MinActions")
+	}
+
+	release, err := s.Admit("powercyclebmc", "alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	release(nil)
+
+	if _, err := s.Admit("poweroff", "bob"); !errors.Is(err, ErrBreakerOpen) {
+		t.Fatalf("Admit() error = %v, want the breaker to be open", err)
+	}
+	if status := s.Status(); status.Breaker.State != BreakerOpen || status.Breaker.Failures != 3 || status.Breaker.Actions != 4 {
+		t.Errorf("Status() breaker = %+v, want open with 3 failures of 4 actions", status.Breaker)
+	}
+	if _, err := s.Admit("ison", "bob"); err != nil {
+		t.Errorf("Admit() refused an action which isn't destructive: %v", err)
+	}
+
+	s.Reset()
+	if _, err := s.Admit("poweroff", "bob"); err != nil {
+		t.Errorf("Admit() error = %v after a reset", err)
+	}
+
+	// failures out of the window don't count and an open breaker cools down
+	for i := 0; i < 4; i++ {
+		release, _ := s.Admit("poweroff", "bob")
+		release(errors.New("unreachable"))
+	}
+	if s.Status().Breaker.State != BreakerOpen {
+		t.Fatalf("breaker didn't open")
+	}
+	*now = now.Add(time.Hour)
+	if _, err := s.Admit("poweroff", "bob"); err != nil {
+		t.Errorf("Admit() error = %v after the cooldown", err)
+	}
+}
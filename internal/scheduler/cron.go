@@ -0,0 +1,161 @@
+package scheduler
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// cronSearchLimit bounds the search of the next activation, an expression like "0 0 30 2 *" never matches
+const cronSearchLimit = 5 * 366 * 24 * time.Hour
+
+type (
+	// cronExpr is a standard 5 fields cron expression: minute, hour, day of month, month, day of week
+	cronExpr struct {
+		minutes     map[int]bool
+		hours       map[int]bool
+		daysOfMonth map[int]bool
+		months      map[int]bool
+		daysOfWeek  map[int]bool
+		// the day matches when either day field matches if both of them are restricted, as cron(8) does
+		anyDayOfMonth bool
+		anyDayOfWeek  bool
+	}
+
+	cronField struct {
+		name string
+		min  int
+		max  int
+	}
+)
+
+var cronFields = []cronField{
+	{name: "minute", min: 0, max: 59},
+	{name: "hour", min: 0, max: 23},
+	{name: "day of month", min: 1, max: 31},
+	{name: "month", min: 1, max: 12},
+	{name: "day of week", min: 0, max: 7},
+}
+
+func parseCron(expr string) (*cronExpr, error) {
+	tokens := strings.Fields(expr)
+	if len(tokens) != len(cronFields) {
+		return nil, fmt.Errorf("invalid cron expression %q: %d fields expected", expr, len(cronFields))
+	}
+
+	values := make([]map[int]bool, len(cronFields))
+	for i, field := range cronFields {
+		fieldValues, err := field.parse(tokens[i])
+		if err != nil {
+			return nil, fmt.Errorf("invalid cron expression %q: %w", expr, err)
+		}
+		values[i] = fieldValues
+	}
+
+	// both 0 and 7 are Sunday
+	if values[4][7] {
+		values[4][0] = true
+	}
+
+	return &cronExpr{
+		minutes:       values[0],
+		hours:         values[1],
+		daysOfMonth:   values[2],
+		months:        values[3],
+		daysOfWeek:    values[4],
+		anyDayOfMonth: strings.HasPrefix(tokens[2], "*"),
+		anyDayOfWeek:  strings.HasPrefix(tokens[4], "*"),
+	}, nil
+}
+
+// next returns the first activation strictly after t, the expression is evaluated in UTC
+func (c *cronExpr) next(t time.Time) (time.Time, error) {
+	t = t.UTC().Truncate(time.Minute).Add(time.Minute)
+	limit := t.Add(cronSearchLimit)
+
+	for t.Before(limit) {
+		switch {
+		case !c.months[int(t.Month())]:
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, time.UTC)
+		case !c.matchDay(t):
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, time.UTC)
+		case !c.hours[t.Hour()]:
+			t = t.Truncate(time.Hour).Add(time.Hour)
+		case !c.minutes[t.Minute()]:
+			t = t.Add(time.Minute)
+		default:
+			return t, nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("cron expression never matches")
+}
+
+func (c *cronExpr) matchDay(t time.Time) bool {
+	dayOfMonth := c.daysOfMonth[t.Day()]
+	dayOfWeek := c.daysOfWeek[int(t.Weekday())]
+
+	if c.anyDayOfMonth || c.anyDayOfWeek {
+		return dayOfMonth && dayOfWeek
+	}
+	return dayOfMonth || dayOfWeek
+}
+
+func (f cronField) parse(token string) (map[int]bool, error) {
+	values := make(map[int]bool)
+
+	for _, part := range strings.Split(token, ",") {
+		if err := f.parsePart(part, values); err != nil {
+			return nil, err
+		}
+	}
+
+	return values, nil
+}
+
+func (f cronField) parsePart(part string, values map[int]bool) error {
+	rangePart, step := part, 1
+
+	if i := strings.Index(part, "/"); i >= 0 {
+		var err error
+		rangePart = part[:i]
+		step, err = strconv.Atoi(part[i+1:])
+		if err != nil || step <= 0 {
+			return fmt.Errorf("invalid step in %s %q", f.name, part)
+		}
+	}
+
+	low, high := f.min, f.max
+
+	if rangePart != "*" {
+		bounds := strings.SplitN(rangePart, "-", 2)
+
+		var err error
+		low, err = strconv.Atoi(bounds[0])
+		if err != nil {
+			return fmt.Errorf("invalid %s %q", f.name, part)
+		}
+
+		high = low
+		if len(bounds) == 2 {
+			high, err = strconv.Atoi(bounds[1])
+			if err != nil {
+				return fmt.Errorf("invalid %s %q", f.name, part)
+			}
+		} else if step > 1 {
+			// "5/10" means from 5 to the end with step 10
+			high = f.max
+		}
+	}
+
+	if low < f.min || high > f.max || low > high {
+		return fmt.Errorf("%s %q is out of range %d-%d", f.name, part, f.min, f.max)
+	}
+
+	for value := low; value <= high; value += step {
+		values[value] = true
+	}
+
+	return nil
+}
@@ -0,0 +1,100 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+)
+
+func Test_cronExpr_next(t *testing.T) {
+	// 2021-06-15 is a Tuesday
+	from := time.Date(2021, 6, 15, 10, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		expr    string
+		want    time.Time
+		wantErr bool
+	}{
+		{
+			name: "Every minute",
+			expr: "* * * * *",
+			want: time.Date(2021, 6, 15, 10, 31, 0, 0, time.UTC),
+		},
+		{
+			name: "Daily at 02:00",
+			expr: "0 2 * * *",
+			want: time.Date(2021, 6, 16, 2, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "Every 15 minutes",
+			expr: "*/15 * * * *",
+			want: time.Date(2021, 6, 15, 10, 45, 0, 0, time.UTC),
+		},
+		{
+			name: "List and range",
+			expr: "0 9-11,20 * * *",
+			want: time.Date(2021, 6, 15, 11, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "Sunday as 7",
+			expr: "0 0 * * 7",
+			want: time.Date(2021, 6, 20, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "Day of month or day of week",
+			expr: "0 0 1 * 3",
+			want: time.Date(2021, 6, 16, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "Next year",
+			expr: "0 0 1 1 *",
+			want: time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:    "Never matches",
+			expr:    "0 0 30 2 *",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expr, err := parseCron(tt.expr)
+			if err != nil {
+				t.Fatalf("parseCron() error = %v", err)
+			}
+
+			got, err := expr.next(from)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("next() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("next() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_parseCron(t *testing.T) {
+	tests := []struct {
+		name    string
+		expr    string
+		wantErr bool
+	}{
+		{name: "OK", expr: "0 2 * * 1-5", wantErr: false},
+		{name: "OK step from value", expr: "5/10 * * * *", wantErr: false},
+		{name: "Too few fields", expr: "0 2 * *", wantErr: true},
+		{name: "Out of range", expr: "60 * * * *", wantErr: true},
+		{name: "Reversed range", expr: "0 5-1 * * *", wantErr: true},
+		{name: "Invalid step", expr: "*/0 * * * *", wantErr: true},
+		{name: "Not a number", expr: "a * * * *", wantErr: true},
+		{name: "Empty", expr: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := parseCron(tt.expr); (err != nil) != tt.wantErr {
+				t.Errorf("parseCron() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,469 @@
+package scheduler
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/bmc-toolbox/actor/internal/actions"
+	"github.com/bmc-toolbox/actor/internal/webhook"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	StatusPending = "pending"
+	StatusRunning = "running"
+	StatusDone    = "done"
+
+	tickInterval = time.Second
+	// maxRuns is the number of runs kept per schedule, the oldest runs are dropped first
+	maxRuns = 10
+	// planIdentity is who scheduled plans run on behalf of for the blast-radius limits
+	planIdentity = "scheduler"
+)
+
+// ErrNotFound is returned for unknown schedule IDs
+var ErrNotFound = errors.New("schedule not found")
+
+type (
+	// Schedule is an action sequence to be run against a target once at RunAt or repeatedly following Cron
+	Schedule struct {
+		ID             string         `json:"id"`
+		Target         actions.Target `json:"target"`
+		ActionSequence []string       `json:"action-sequence"`
+		RunAt          *time.Time     `json:"run-at,omitempty"`
+		Cron           string         `json:"cron,omitempty"`
+		Webhook        string         `json:"webhook,omitempty"`
+		NextRun        time.Time      `json:"next-run"`
+		Status         string         `json:"status"`
+		Created        time.Time      `json:"created"`
+		Runs           []Run          `json:"runs"`
+	}
+
+	// Run is the outcome of a single execution of a schedule
+	Run struct {
+		Started  time.Time              `json:"started"`
+		Finished time.Time              `json:"finished"`
+		Status   bool                   `json:"status"`
+		Error    string                 `json:"error"`
+		Results  []actions.ActionRecord `json:"results"`
+	}
+
+	// Scheduler runs schedules through the plan makers and persists them in a state file across restarts
+	Scheduler struct {
+		planMakers *actions.PlanMakers
+		notifier   *webhook.Notifier
+		stateFile  string
+		retention  time.Duration
+
+		lock      sync.Mutex
+		schedules map[string]*Schedule
+		crons     map[string]*cronExpr
+
+		stop chan struct{}
+		wg   sync.WaitGroup
+	}
+)
+
+// New creates a Scheduler and loads the schedules from the state file,
+// finished one-shot schedules are dropped once they are older than retention
+func New(planMakers *actions.PlanMakers, notifier *webhook.Notifier, stateFile string, retention time.Duration) (*Scheduler, error) {
+	s := &Scheduler{
+		planMakers: planMakers,
+		notifier:   notifier,
+		stateFile:  stateFile,
+		retention:  retention,
+		schedules:  make(map[string]*Schedule),
+		crons:      make(map[string]*cronExpr),
+		stop:       make(chan struct{}),
+	}
+
+	if err := s.load(); err != nil {
+		return nil, fmt.Errorf("failed to load schedules from %s: %w", stateFile, err)
+	}
+
+	return s, nil
+}
+
+// Start runs the scheduling loop in the background
+func (s *Scheduler) Start() {
+	s.wg.Add(1)
+
+	go func() {
+		defer s.wg.Done()
+
+		ticker := time.NewTicker(tickInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-s.stop:
+				return
+			case now := <-ticker.C:
+				s.tick(now)
+			}
+		}
+	}()
+}
+
+// Stop stops the scheduling loop and waits for the running schedules
+func (s *Scheduler) Stop() {
+	close(s.stop)
+	s.wg.Wait()
+}
+
+// Add validates and registers a new schedule, exactly one of runAt and cron must be set,
+// webhook is notified about every run in addition to the configured webhooks
+func (s *Scheduler) Add(target actions.Target, actionSequence []string, runAt *time.Time, cron, webhook string) (Schedule, error) {
+	if (runAt == nil) == (cron == "") {
+		return Schedule{}, fmt.Errorf("exactly one of run-at and cron is required")
+	}
+
+	if len(actionSequence) == 0 {
+		return Schedule{}, fmt.Errorf("empty action sequence")
+	}
+
+	if err := s.validatePlan(target, actionSequence); err != nil {
+		return Schedule{}, err
+	}
+
+	now := time.Now().UTC()
+	schedule := &Schedule{
+		Target:         target,
+		ActionSequence: actionSequence,
+		Cron:           cron,
+		Webhook:        webhook,
+		Status:         StatusPending,
+		Created:        now,
+		Runs:           make([]Run, 0),
+	}
+
+	var expr *cronExpr
+	if runAt != nil {
+		runAtUTC := runAt.UTC()
+		schedule.RunAt = &runAtUTC
+		schedule.NextRun = runAtUTC
+	} else {
+		var err error
+		if expr, err = parseCron(cron); err != nil {
+			return Schedule{}, err
+		}
+		if schedule.NextRun, err = expr.next(now); err != nil {
+			return Schedule{}, fmt.Errorf("invalid cron expression %q: %w", cron, err)
+		}
+	}
+
+	id, err := newID()
+	if err != nil {
+		return Schedule{}, err
+	}
+	schedule.ID = id
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.schedules[id] = schedule
+	if expr != nil {
+		s.crons[id] = expr
+	}
+
+	if err := s.save(); err != nil {
+		delete(s.schedules, id)
+		delete(s.crons, id)
+		return Schedule{}, err
+	}
+
+	return copySchedule(schedule), nil
+}
+
+// List returns all schedules ordered by creation time
+func (s *Scheduler) List() []Schedule {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	schedules := make([]Schedule, 0, len(s.schedules))
+	for _, schedule := range s.sorted() {
+		schedules = append(schedules, copySchedule(schedule))
+	}
+
+	return schedules
+}
+
+// Get returns the schedule with its stored runs
+func (s *Scheduler) Get(id string) (Schedule, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	schedule, ok := s.schedules[id]
+	if !ok {
+		return Schedule{}, ErrNotFound
+	}
+
+	return copySchedule(schedule), nil
+}
+
+// Cancel removes a schedule, a run in progress is not interrupted but its result is discarded
+func (s *Scheduler) Cancel(id string) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if _, ok := s.schedules[id]; !ok {
+		return ErrNotFound
+	}
+
+	delete(s.schedules, id)
+	delete(s.crons, id)
+
+	return s.save()
+}
+
+// IsPrivileged tells whether the action sequence has privileged steps, so only authorized clients may schedule it
+func (s *Scheduler) IsPrivileged(target actions.Target, actionSequence []string) (bool, error) {
+	planMaker, err := s.planMakers.For(target)
+	if err != nil {
+		return false, err
+	}
+
+	plan, err := planMaker.MakePlan(actionSequence, target.Params())
+	if err != nil {
+		return false, err
+	}
+	defer plan.Cleanup()
+
+	return plan.IsPrivileged(), nil
+}
+
+func (s *Scheduler) validatePlan(target actions.Target, actionSequence []string) error {
+	planMaker, err := s.planMakers.For(target)
+	if err != nil {
+		return err
+	}
+
+	plan, err := planMaker.MakePlan(actionSequence, target.Params())
+	if err != nil {
+		return err
+	}
+	plan.Cleanup()
+
+	return nil
+}
+
+func (s *Scheduler) tick(now time.Time) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	changed := false
+
+	for id, schedule := range s.schedules {
+		if schedule.Status == StatusDone && s.isExpired(schedule, now) {
+			delete(s.schedules, id)
+			changed = true
+			continue
+		}
+
+		if schedule.Status != StatusPending || schedule.NextRun.After(now) {
+			continue
+		}
+
+		schedule.Status = StatusRunning
+		changed = true
+
+		s.wg.Add(1)
+		go s.run(id, copySchedule(schedule))
+	}
+
+	if !changed {
+		return
+	}
+
+	if err := s.save(); err != nil {
+		log.WithError(err).Error("failed to save schedules")
+	}
+}
+
+func (s *Scheduler) run(id string, schedule Schedule) {
+	defer s.wg.Done()
+
+	logger := log.WithField("schedule", id).WithField("target", schedule.Target.String())
+	run := Run{Started: time.Now().UTC()}
+
+	results, err := s.execute(schedule)
+
+	run.Finished = time.Now().UTC()
+	run.Status = err == nil
+	run.Results = actions.NewActionRecords(results)
+	if err != nil {
+		run.Error = err.Error()
+		logger.WithError(err).Warn("scheduled actions failed")
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	current, ok := s.schedules[id]
+	if !ok {
+		// cancelled while running
+		return
+	}
+
+	s.finish(current, run, run.Finished)
+
+	if err := s.save(); err != nil {
+		logger.WithError(err).Error("failed to save schedules")
+	}
+}
+
+func (s *Scheduler) execute(schedule Schedule) ([]actions.ActionResult, error) {
+	planMaker, err := s.planMakers.For(schedule.Target)
+	if err != nil {
+		return nil, err
+	}
+
+	plan, err := planMaker.MakePlan(schedule.ActionSequence, schedule.Target.Params())
+	if err != nil {
+		return nil, err
+	}
+
+	webhooks := make([]string, 0, 1)
+	if schedule.Webhook != "" {
+		webhooks = append(webhooks, schedule.Webhook)
+	}
+	plan.OnFinish(s.notifier.Observer(webhooks...))
+	plan.As(planIdentity)
+
+	return plan.Run()
+}
+
+// finish records the run and decides what comes next for the schedule, the caller must hold the lock
+func (s *Scheduler) finish(schedule *Schedule, run Run, now time.Time) {
+	schedule.Runs = append(schedule.Runs, run)
+	if len(schedule.Runs) > maxRuns {
+		schedule.Runs = schedule.Runs[len(schedule.Runs)-maxRuns:]
+	}
+
+	expr, ok := s.crons[schedule.ID]
+	if !ok {
+		schedule.Status = StatusDone
+		return
+	}
+
+	next, err := expr.next(now)
+	if err != nil {
+		schedule.Status = StatusDone
+		return
+	}
+
+	schedule.NextRun = next
+	schedule.Status = StatusPending
+}
+
+func (s *Scheduler) isExpired(schedule *Schedule, now time.Time) bool {
+	if len(schedule.Runs) == 0 {
+		return false
+	}
+	return now.Sub(schedule.Runs[len(schedule.Runs)-1].Finished) > s.retention
+}
+
+func (s *Scheduler) load() error {
+	data, err := ioutil.ReadFile(s.stateFile)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	schedules := make([]*Schedule, 0)
+	if err := json.Unmarshal(data, &schedules); err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+
+	for _, schedule := range schedules {
+		if schedule.Cron != "" {
+			expr, err := parseCron(schedule.Cron)
+			if err != nil {
+				return fmt.Errorf("schedule %s: %w", schedule.ID, err)
+			}
+			s.crons[schedule.ID] = expr
+
+			// activations missed while actor was down are skipped
+			if schedule.Status == StatusPending && schedule.NextRun.Before(now) {
+				if schedule.NextRun, err = expr.next(now); err != nil {
+					schedule.Status = StatusDone
+				}
+			}
+		}
+
+		if schedule.Status == StatusRunning {
+			s.finish(schedule, Run{Started: now, Finished: now, Error: "interrupted by a restart of actor"}, now)
+		}
+
+		s.schedules[schedule.ID] = schedule
+	}
+
+	return nil
+}
+
+// save writes the schedules to the state file, the caller must hold the lock
+func (s *Scheduler) save() error {
+	data, err := json.MarshalIndent(s.sorted(), "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal schedules: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(s.stateFile), 0755); err != nil {
+		return fmt.Errorf("failed to create the directory for %s: %w", s.stateFile, err)
+	}
+
+	tmpFile := s.stateFile + ".tmp"
+	if err := ioutil.WriteFile(tmpFile, data, 0600); err != nil {
+		return fmt.Errorf("failed to write schedules: %w", err)
+	}
+
+	if err := os.Rename(tmpFile, s.stateFile); err != nil {
+		return fmt.Errorf("failed to write schedules: %w", err)
+	}
+
+	return nil
+}
+
+func (s *Scheduler) sorted() []*Schedule {
+	schedules := make([]*Schedule, 0, len(s.schedules))
+	for _, schedule := range s.schedules {
+		schedules = append(schedules, schedule)
+	}
+
+	sort.Slice(schedules, func(i, j int) bool {
+		if schedules[i].Created.Equal(schedules[j].Created) {
+			return schedules[i].ID < schedules[j].ID
+		}
+		return schedules[i].Created.Before(schedules[j].Created)
+	})
+
+	return schedules
+}
+
+func copySchedule(schedule *Schedule) Schedule {
+	scheduleCopy := *schedule
+	scheduleCopy.ActionSequence = append([]string{}, schedule.ActionSequence...)
+	scheduleCopy.Runs = append([]Run{}, schedule.Runs...)
+	return scheduleCopy
+}
+
+func newID() (string, error) {
+	buf := make([]byte, 8)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate an ID: %w", err)
+	}
+	return hex.EncodeToString(buf), nil
+}
@@ -0,0 +1,151 @@
+package scheduler
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/bmc-toolbox/actor/internal/actions"
+	"github.com/bmc-toolbox/actor/internal/webhook"
+)
+
+type (
+	testExecutorFactory struct{}
+
+	testExecutor struct{}
+)
+
+func (f *testExecutorFactory) New(_ map[string]interface{}) (actions.Executor, error) {
+	return &testExecutor{}, nil
+}
+
+func (e *testExecutor) Validate(action string) error {
+	if action != actions.IsOn {
+		return fmt.Errorf("unknown action %q", action)
+	}
+	return nil
+}
+
+func (e *testExecutor) Run(action string) actions.ActionResult {
+	return actions.NewActionResult(action, true, "ok", nil)
+}
+
+func (e *testExecutor) Cleanup() {
+}
+
+func newTestScheduler(t *testing.T, stateFile string) *Scheduler {
+	planMaker := actions.NewPlanMaker(&testExecutorFactory{})
+	planMakers := &actions.PlanMakers{Host: planMaker, Chassis: planMaker, BladeByPos: planMaker, BladeBySerial: planMaker}
+
+	s, err := New(planMakers, webhook.New(webhook.Config{}), stateFile, time.Hour)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	return s
+}
+
+func TestScheduler_Add(t *testing.T) {
+	target := actions.Target{Type: actions.TargetHost, Host: "host.example.com"}
+	runAt := time.Now()
+
+	type args struct {
+		target         actions.Target
+		actionSequence []string
+		runAt          *time.Time
+		cron           string
+	}
+	tests := []struct {
+		name    string
+		args    args
+		wantErr bool
+	}{
+		{
+			name:    "OK run-at",
+			args:    args{target: target, actionSequence: []string{actions.IsOn}, runAt: &runAt},
+			wantErr: false,
+		},
+		{
+			name:    "OK cron",
+			args:    args{target: target, actionSequence: []string{actions.IsOn}, cron: "0 2 * * *"},
+			wantErr: false,
+		},
+		{
+			name:    "Both run-at and cron",
+			args:    args{target: target, actionSequence: []string{actions.IsOn}, runAt: &runAt, cron: "0 2 * * *"},
+			wantErr: true,
+		},
+		{
+			name:    "Neither run-at nor cron",
+			args:    args{target: target, actionSequence: []string{actions.IsOn}},
+			wantErr: true,
+		},
+		{
+			name:    "Unknown action",
+			args:    args{target: target, actionSequence: []string{actions.PowerOff}, runAt: &runAt},
+			wantErr: true,
+		},
+		{
+			name:    "Invalid target",
+			args:    args{target: actions.Target{Type: actions.TargetBlade, Host: "host"}, actionSequence: []string{actions.IsOn}, runAt: &runAt},
+			wantErr: true,
+		},
+		{
+			name:    "Invalid cron",
+			args:    args{target: target, actionSequence: []string{actions.IsOn}, cron: "0 25 * * *"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestScheduler(t, filepath.Join(t.TempDir(), "schedules.json"))
+			if _, err := s.Add(tt.args.target, tt.args.actionSequence, tt.args.runAt, tt.args.cron, ""); (err != nil) != tt.wantErr {
+				t.Errorf("Add() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestScheduler_RunAndReload(t *testing.T) {
+	stateFile := filepath.Join(t.TempDir(), "schedules.json")
+	s := newTestScheduler(t, stateFile)
+
+	runAt := time.Now().Add(-time.Minute)
+	target := actions.Target{Type: actions.TargetHost, Host: "host.example.com"}
+
+	once, err := s.Add(target, []string{actions.IsOn}, &runAt, "", "")
+	if err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+	repeated, err := s.Add(target, []string{actions.IsOn}, nil, "0 2 * * *", "")
+	if err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+
+	s.tick(time.Now())
+	s.wg.Wait()
+
+	got, err := s.Get(once.ID)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if got.Status != StatusDone || len(got.Runs) != 1 || !got.Runs[0].Status {
+		t.Errorf("Get() = %+v, want a done schedule with one successful run", got)
+	}
+
+	reloaded := newTestScheduler(t, stateFile)
+	if schedules := reloaded.List(); len(schedules) != 2 {
+		t.Fatalf("List() after reload = %d schedules, want 2", len(schedules))
+	}
+	if _, ok := reloaded.crons[repeated.ID]; !ok {
+		t.Errorf("cron schedule %s is not parsed after reload", repeated.ID)
+	}
+
+	if err := reloaded.Cancel(repeated.ID); err != nil {
+		t.Errorf("Cancel() error = %v", err)
+	}
+	if err := reloaded.Cancel(repeated.ID); err != ErrNotFound {
+		t.Errorf("Cancel() error = %v, want %v", err, ErrNotFound)
+	}
+}
@@ -0,0 +1,222 @@
+package screenshot
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"io/ioutil"
+	"math/bits"
+	"path/filepath"
+	"strings"
+
+	// decoders of the formats returned by the BMCs and used for the references
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+)
+
+const (
+	hashWidth  = 9
+	hashHeight = 8
+)
+
+type (
+	// Analyzer compares screenshots with the previous one of the same host and with a library of reference images
+	Analyzer struct {
+		threshold  int
+		references []reference
+	}
+
+	reference struct {
+		name string
+		hash uint64
+	}
+
+	// Analysis is the outcome of comparing a new screenshot, distances are the number of differing hash bits
+	Analysis struct {
+		URL string
+		// Distance to the previous screenshot of the host, -1 when there is none
+		Distance int
+		// Frozen is set when the screen didn't change since the previous screenshot
+		Frozen bool
+		// Match is the name of the closest reference image within the threshold, empty if none matches
+		Match         string
+		MatchDistance int
+	}
+)
+
+// NewAnalyzer loads the reference images from referenceDir, each file is a reference named after the file
+// without its extension (e.g. kernel-panic.png), images within threshold bits of each other are considered equal
+func NewAnalyzer(referenceDir string, threshold int) (*Analyzer, error) {
+	analyzer := &Analyzer{threshold: threshold, references: make([]reference, 0)}
+	if referenceDir == "" {
+		return analyzer, nil
+	}
+
+	files, err := ioutil.ReadDir(referenceDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read reference images: %w", err)
+	}
+
+	for _, file := range files {
+		if file.IsDir() || strings.HasPrefix(file.Name(), ".") {
+			continue
+		}
+
+		payload, err := ioutil.ReadFile(filepath.Join(referenceDir, file.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read reference image %s: %w", file.Name(), err)
+		}
+
+		hash, err := Hash(payload)
+		if err != nil {
+			return nil, fmt.Errorf("reference image %s: %w", file.Name(), err)
+		}
+
+		name := strings.TrimSuffix(file.Name(), filepath.Ext(file.Name()))
+		analyzer.references = append(analyzer.references, reference{name: name, hash: hash})
+	}
+
+	return analyzer, nil
+}
+
+// Analyze takes a screenshot, puts it to the store and compares it with the previous one and the references
+func (a *Analyzer) Analyze(bmc BmcScreenshoter, host string, store Store) (Analysis, error) {
+	previous, err := a.previousHash(host, store)
+	if err != nil {
+		return Analysis{}, err
+	}
+
+	payload, extension, err := bmc.Screenshot()
+	if err != nil {
+		return Analysis{}, err
+	}
+
+	url, err := put(bmc, host, store, payload, extension)
+	if err != nil {
+		return Analysis{}, err
+	}
+
+	hash, err := Hash(payload)
+	if err != nil {
+		return Analysis{}, fmt.Errorf("failed to analyze screenshot %s: %w", url, err)
+	}
+
+	analysis := Analysis{URL: url, Distance: -1}
+
+	if previous != nil {
+		analysis.Distance = distance(hash, *previous)
+		analysis.Frozen = analysis.Distance <= a.threshold
+	}
+
+	for _, ref := range a.references {
+		d := distance(hash, ref.hash)
+		if d > a.threshold || (analysis.Match != "" && d >= analysis.MatchDistance) {
+			continue
+		}
+		analysis.Match = ref.name
+		analysis.MatchDistance = d
+	}
+
+	return analysis, nil
+}
+
+// previousHash returns the hash of the newest stored screenshot of the host, nil if there is none
+// or it can't be decoded
+func (a *Analyzer) previousHash(host string, store Store) (*uint64, error) {
+	infos, err := ListInfo(store, host)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list the screenshots of %s: %w", host, err)
+	}
+
+	if len(infos) == 0 {
+		return nil, nil
+	}
+	previous := infos[0]
+
+	payload, err := store.Get(previous.Key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the previous screenshot %s: %w", previous.Key, err)
+	}
+
+	hash, err := Hash(payload)
+	if err != nil {
+		return nil, nil
+	}
+
+	return &hash, nil
+}
+
+func (a Analysis) String() string {
+	parts := []string{a.URL}
+
+	switch {
+	case a.Distance < 0:
+		parts = append(parts, "no previous screenshot")
+	case a.Frozen:
+		parts = append(parts, fmt.Sprintf("frozen screen, %d bits differ from the previous screenshot", a.Distance))
+	default:
+		parts = append(parts, fmt.Sprintf("screen changed, %d bits differ from the previous screenshot", a.Distance))
+	}
+
+	if a.Match != "" {
+		parts = append(parts, fmt.Sprintf("matches %s, %d bits differ", a.Match, a.MatchDistance))
+	} else {
+		parts = append(parts, "no reference matches")
+	}
+
+	return strings.Join(parts, "; ")
+}
+
+// Hash returns the difference hash of an image: the image is shrunk to 9x8 grey cells and
+// every bit tells whether a cell is brighter than its right neighbour
+func Hash(payload []byte) (uint64, error) {
+	img, _, err := image.Decode(bytes.NewReader(payload))
+	if err != nil {
+		return 0, fmt.Errorf("failed to decode image: %w", err)
+	}
+
+	bounds := img.Bounds()
+	if bounds.Dx() < hashWidth || bounds.Dy() < hashHeight {
+		return 0, fmt.Errorf("image %dx%d is too small", bounds.Dx(), bounds.Dy())
+	}
+
+	var cells [hashHeight][hashWidth]float64
+	for y := 0; y < hashHeight; y++ {
+		for x := 0; x < hashWidth; x++ {
+			cells[y][x] = averageLuminance(img, image.Rect(
+				bounds.Min.X+x*bounds.Dx()/hashWidth,
+				bounds.Min.Y+y*bounds.Dy()/hashHeight,
+				bounds.Min.X+(x+1)*bounds.Dx()/hashWidth,
+				bounds.Min.Y+(y+1)*bounds.Dy()/hashHeight,
+			))
+		}
+	}
+
+	var hash uint64
+	for y := 0; y < hashHeight; y++ {
+		for x := 0; x < hashWidth-1; x++ {
+			hash <<= 1
+			if cells[y][x] > cells[y][x+1] {
+				hash |= 1
+			}
+		}
+	}
+
+	return hash, nil
+}
+
+func averageLuminance(img image.Image, rect image.Rectangle) float64 {
+	var sum float64
+	for y := rect.Min.Y; y < rect.Max.Y; y++ {
+		for x := rect.Min.X; x < rect.Max.X; x++ {
+			r, g, b, _ := img.At(x, y).RGBA()
+			sum += 0.299*float64(r) + 0.587*float64(g) + 0.114*float64(b)
+		}
+	}
+	return sum / float64(rect.Dx()*rect.Dy())
+}
+
+func distance(a, b uint64) int {
+	return bits.OnesCount64(a ^ b)
+}
@@ -0,0 +1,106 @@
+package screenshot
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+)
+
+type testImageScreenshoter struct {
+	payload []byte
+}
+
+func (s *testImageScreenshoter) Screenshot() ([]byte, string, error) {
+	return s.payload, "png", nil
+}
+
+func (s *testImageScreenshoter) HardwareType() string {
+	return "idrac9"
+}
+
+// testImage draws a horizontal gradient, reversed ones give an opposite hash
+func testImage(t *testing.T, reversed bool) []byte {
+	img := image.NewGray(image.Rect(0, 0, 90, 80))
+	for y := 0; y < 80; y++ {
+		for x := 0; x < 90; x++ {
+			level := uint8(x * 2)
+			if reversed {
+				level = uint8((89 - x) * 2)
+			}
+			img.SetGray(x, y, color.Gray{Y: level})
+		}
+	}
+
+	buf := &bytes.Buffer{}
+	if err := png.Encode(buf, img); err != nil {
+		t.Fatalf("png.Encode() error = %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestHash(t *testing.T) {
+	a, err := Hash(testImage(t, false))
+	if err != nil {
+		t.Fatalf("Hash() error = %v", err)
+	}
+	b, err := Hash(testImage(t, true))
+	if err != nil {
+		t.Fatalf("Hash() error = %v", err)
+	}
+
+	if d := distance(a, a); d != 0 {
+		t.Errorf("distance() of the same image = %d, want 0", d)
+	}
+	if d := distance(a, b); d != 64 {
+		t.Errorf("distance() of reversed images = %d, want 64", d)
+	}
+
+	if _, err := Hash([]byte("png")); err == nil {
+		t.Errorf("Hash() of an invalid image succeeded")
+	}
+}
+
+func TestAnalyzer_Analyze(t *testing.T) {
+	referenceDir := t.TempDir()
+	if err := ioutil.WriteFile(filepath.Join(referenceDir, "bios-post.png"), testImage(t, true), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	analyzer, err := NewAnalyzer(referenceDir, 6)
+	if err != nil {
+		t.Fatalf("NewAnalyzer() error = %v", err)
+	}
+
+	store := NewMemoryStore()
+	bmc := &testImageScreenshoter{payload: testImage(t, false)}
+
+	analysis, err := analyzer.Analyze(bmc, "host1", store)
+	if err != nil {
+		t.Fatalf("Analyze() error = %v", err)
+	}
+	if analysis.Distance != -1 || analysis.Frozen || analysis.Match != "" {
+		t.Errorf("Analyze() = %+v, want no previous screenshot and no match", analysis)
+	}
+
+	// same second, the key is overwritten but the previous payload has been read before
+	analysis, err = analyzer.Analyze(bmc, "host1", store)
+	if err != nil {
+		t.Fatalf("Analyze() error = %v", err)
+	}
+	if analysis.Distance != 0 || !analysis.Frozen {
+		t.Errorf("Analyze() = %+v, want a frozen screen", analysis)
+	}
+
+	bmc.payload = testImage(t, true)
+	analysis, err = analyzer.Analyze(bmc, "host1", store)
+	if err != nil {
+		t.Fatalf("Analyze() error = %v", err)
+	}
+	if analysis.Frozen || analysis.Match != "bios-post" || analysis.MatchDistance != 0 {
+		t.Errorf("Analyze() = %+v, want a changed screen matching bios-post", analysis)
+	}
+}
@@ -0,0 +1,98 @@
+package screenshot
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+type (
+	// LocalStore keeps screenshots in a directory served by actor under urlPrefix
+	LocalStore struct {
+		dir       string
+		urlPrefix string
+	}
+)
+
+func NewLocalStore(dir, urlPrefix string) (*LocalStore, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create screenshot storage %s: %w", dir, err)
+	}
+
+	return &LocalStore{dir: dir, urlPrefix: strings.TrimSuffix(urlPrefix, "/")}, nil
+}
+
+func (s *LocalStore) Put(key string, payload []byte) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(path, payload, 0644)
+}
+
+func (s *LocalStore) Get(key string) ([]byte, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+
+	payload, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, ErrNotFound
+	}
+
+	return payload, err
+}
+
+func (s *LocalStore) List(prefix string) ([]Object, error) {
+	entries, err := ioutil.ReadDir(s.dir)
+	if err != nil {
+		return nil, err
+	}
+
+	objects := make([]Object, 0)
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasPrefix(entry.Name(), prefix) {
+			continue
+		}
+		objects = append(objects, Object{Key: entry.Name(), Size: entry.Size(), Modified: entry.ModTime()})
+	}
+
+	return objects, nil
+}
+
+func (s *LocalStore) Delete(key string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+
+	err = os.Remove(path)
+	if os.IsNotExist(err) {
+		return ErrNotFound
+	}
+
+	return err
+}
+
+func (s *LocalStore) URL(key string) (string, error) {
+	return fmt.Sprintf("%s/%s", s.urlPrefix, key), nil
+}
+
+func (s *LocalStore) path(key string) (string, error) {
+	if err := validateKey(key); err != nil {
+		return "", err
+	}
+	return filepath.Join(s.dir, key), nil
+}
+
+// validateKey rejects keys which could escape the storage, keys are flat file names
+func validateKey(key string) error {
+	if key == "" || key == "." || key == ".." || strings.ContainsAny(key, `/\`) {
+		return fmt.Errorf("invalid screenshot key %q", key)
+	}
+	return nil
+}
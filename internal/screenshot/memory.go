@@ -0,0 +1,84 @@
+package screenshot
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+type (
+	// MemoryStore keeps screenshots in memory, it is meant for tests
+	MemoryStore struct {
+		lock    sync.RWMutex
+		objects map[string]memoryObject
+	}
+
+	memoryObject struct {
+		payload  []byte
+		modified time.Time
+	}
+)
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{objects: make(map[string]memoryObject)}
+}
+
+func (s *MemoryStore) Put(key string, payload []byte) error {
+	if err := validateKey(key); err != nil {
+		return err
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.objects[key] = memoryObject{payload: append([]byte{}, payload...), modified: time.Now()}
+
+	return nil
+}
+
+func (s *MemoryStore) Get(key string) ([]byte, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	object, ok := s.objects[key]
+	if !ok {
+		return nil, ErrNotFound
+	}
+
+	return append([]byte{}, object.payload...), nil
+}
+
+func (s *MemoryStore) List(prefix string) ([]Object, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	objects := make([]Object, 0)
+	for key, object := range s.objects {
+		if strings.HasPrefix(key, prefix) {
+			objects = append(objects, Object{Key: key, Size: int64(len(object.payload)), Modified: object.modified})
+		}
+	}
+
+	sort.Slice(objects, func(i, j int) bool { return objects[i].Key < objects[j].Key })
+
+	return objects, nil
+}
+
+func (s *MemoryStore) Delete(key string) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if _, ok := s.objects[key]; !ok {
+		return ErrNotFound
+	}
+
+	delete(s.objects, key)
+
+	return nil
+}
+
+func (s *MemoryStore) URL(key string) (string, error) {
+	return fmt.Sprintf("memory://%s", key), nil
+}
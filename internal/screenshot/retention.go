@@ -0,0 +1,188 @@
+package screenshot
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+type (
+	// Retention limits the stored screenshots, zero values disable a limit
+	Retention struct {
+		MaxAge        time.Duration
+		MaxPerHost    int
+		MaxTotalBytes int64
+	}
+
+	// Info is a stored screenshot with the details encoded in its key
+	Info struct {
+		Object
+		Host         string
+		HardwareType string
+		Taken        time.Time
+	}
+
+	// Janitor applies the retention policy to a store periodically
+	Janitor struct {
+		store     Store
+		retention Retention
+		interval  time.Duration
+		stop      chan struct{}
+		wg        sync.WaitGroup
+	}
+)
+
+func (r Retention) IsEnabled() bool {
+	return r.MaxAge > 0 || r.MaxPerHost > 0 || r.MaxTotalBytes > 0
+}
+
+// IsSol tells whether the object is a SOL transcript rather than a screenshot
+func (i Info) IsSol() bool {
+	return i.HardwareType == SolHardwareType
+}
+
+// ListInfo returns the screenshots of the host, newest first, keys which don't belong to the host
+// and SOL transcripts are skipped
+func ListInfo(store Store, host string) ([]Info, error) {
+	objects, err := store.List(host + "-")
+	if err != nil {
+		return nil, err
+	}
+
+	infos := make([]Info, 0, len(objects))
+	for _, object := range objects {
+		info := newInfo(object)
+		if info.Host == host && !info.IsSol() {
+			infos = append(infos, info)
+		}
+	}
+
+	sortNewestFirst(infos)
+
+	return infos, nil
+}
+
+// ApplyRetention deletes the screenshots exceeding the retention and returns how many have been deleted,
+// SOL transcripts are only subject to the age and total size limits so they don't push screenshots out
+func ApplyRetention(store Store, retention Retention, now time.Time) (int, error) {
+	objects, err := store.List("")
+	if err != nil {
+		return 0, fmt.Errorf("failed to list screenshots: %w", err)
+	}
+
+	infos := make([]Info, 0, len(objects))
+	for _, object := range objects {
+		infos = append(infos, newInfo(object))
+	}
+	sortNewestFirst(infos)
+
+	expired := make(map[string]bool)
+	perHost := make(map[string]int)
+	var totalBytes int64
+
+	for _, info := range infos {
+		switch {
+		case retention.MaxAge > 0 && now.Sub(info.Taken) > retention.MaxAge:
+			expired[info.Key] = true
+		case retention.MaxPerHost > 0 && info.Host != "" && !info.IsSol() && perHost[info.Host] >= retention.MaxPerHost:
+			expired[info.Key] = true
+		case retention.MaxTotalBytes > 0 && totalBytes+info.Size > retention.MaxTotalBytes:
+			expired[info.Key] = true
+		default:
+			if !info.IsSol() {
+				perHost[info.Host]++
+			}
+			totalBytes += info.Size
+		}
+	}
+
+	deleted := 0
+	for key := range expired {
+		err := store.Delete(key)
+		if errors.Is(err, ErrNotFound) {
+			// deleted meanwhile, e.g. through the API
+			continue
+		}
+		if err != nil {
+			return deleted, fmt.Errorf("failed to delete screenshot %s: %w", key, err)
+		}
+		deleted++
+	}
+
+	return deleted, nil
+}
+
+func NewJanitor(store Store, retention Retention, interval time.Duration) *Janitor {
+	return &Janitor{store: store, retention: retention, interval: interval, stop: make(chan struct{})}
+}
+
+// Start applies the retention in the background every interval
+func (j *Janitor) Start() {
+	j.wg.Add(1)
+
+	go func() {
+		defer j.wg.Done()
+
+		ticker := time.NewTicker(j.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-j.stop:
+				return
+			case now := <-ticker.C:
+				deleted, err := ApplyRetention(j.store, j.retention, now)
+				if err != nil {
+					log.WithError(err).Error("failed to apply screenshot retention")
+				}
+				if deleted > 0 {
+					log.WithField("deleted", deleted).Info("applied screenshot retention")
+				}
+			}
+		}
+	}()
+}
+
+func (j *Janitor) Stop() {
+	close(j.stop)
+	j.wg.Wait()
+}
+
+// newInfo parses keys made by Take, "<host>-<hardware type>-<unix time>.<extension>",
+// the modification time is used for keys in another format
+func newInfo(object Object) Info {
+	info := Info{Object: object, Taken: object.Modified}
+
+	name := object.Key
+	if i := strings.LastIndex(name, "."); i >= 0 {
+		name = name[:i]
+	}
+
+	parts := strings.Split(name, "-")
+	if len(parts) < 3 {
+		return info
+	}
+
+	unix, err := strconv.ParseInt(parts[len(parts)-1], 10, 64)
+	if err != nil {
+		return info
+	}
+
+	info.Host = strings.Join(parts[:len(parts)-2], "-")
+	info.HardwareType = parts[len(parts)-2]
+	info.Taken = time.Unix(unix, 0).UTC()
+
+	return info
+}
+
+func sortNewestFirst(infos []Info) {
+	sort.SliceStable(infos, func(i, j int) bool {
+		return infos[i].Taken.After(infos[j].Taken)
+	})
+}
@@ -0,0 +1,151 @@
+package screenshot
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+)
+
+func Test_newInfo(t *testing.T) {
+	modified := time.Date(2021, 6, 15, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		object Object
+		want   Info
+	}{
+		{
+			name:   "OK",
+			object: Object{Key: "10.0.0.1-idrac9-1623715200.png", Modified: modified},
+			want: Info{
+				Object:       Object{Key: "10.0.0.1-idrac9-1623715200.png", Modified: modified},
+				Host:         "10.0.0.1",
+				HardwareType: "idrac9",
+				Taken:        time.Unix(1623715200, 0).UTC(),
+			},
+		},
+		{
+			name:   "OK host with dashes",
+			object: Object{Key: "bmc-r12-01-ilo5-1623715200.jpg"},
+			want: Info{
+				Object:       Object{Key: "bmc-r12-01-ilo5-1623715200.jpg"},
+				Host:         "bmc-r12-01",
+				HardwareType: "ilo5",
+				Taken:        time.Unix(1623715200, 0).UTC(),
+			},
+		},
+		{
+			name:   "Unknown format",
+			object: Object{Key: "screenshot.png", Modified: modified},
+			want:   Info{Object: Object{Key: "screenshot.png", Modified: modified}, Taken: modified},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := newInfo(tt.object); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("newInfo() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestApplyRetention(t *testing.T) {
+	now := time.Unix(1000000, 0)
+
+	tests := []struct {
+		name      string
+		retention Retention
+		want      []string
+	}{
+		{
+			name:      "Max age",
+			retention: Retention{MaxAge: time.Hour},
+			want:      []string{"a-sol-999900.log", "a-x-1000000.png", "a-x-999000.png", "b-x-999500.png"},
+		},
+		{
+			name:      "Max per host",
+			retention: Retention{MaxPerHost: 1},
+			want:      []string{"a-sol-999900.log", "a-x-1000000.png", "b-x-999500.png"},
+		},
+		{
+			name:      "Max total bytes",
+			retention: Retention{MaxTotalBytes: 2},
+			want:      []string{"a-sol-999900.log", "a-x-1000000.png"},
+		},
+		{
+			name:      "Disabled",
+			retention: Retention{},
+			want:      []string{"a-sol-999900.log", "a-x-1000000.png", "a-x-900000.png", "a-x-999000.png", "b-x-999500.png"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := NewMemoryStore()
+			for _, key := range []string{"a-x-1000000.png", "a-sol-999900.log", "a-x-999000.png", "a-x-900000.png", "b-x-999500.png"} {
+				_ = store.Put(key, []byte("1"))
+			}
+
+			if _, err := ApplyRetention(store, tt.retention, now); err != nil {
+				t.Fatalf("ApplyRetention() error = %v", err)
+			}
+
+			objects, _ := store.List("")
+			got := make([]string, 0, len(objects))
+			for _, object := range objects {
+				got = append(got, object.Key)
+			}
+			sort.Strings(got)
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ApplyRetention() kept %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// goneStore is a store whose objects are deleted meanwhile, it wraps ErrNotFound as the S3 and local stores may
+type goneStore struct {
+	*MemoryStore
+}
+
+func (s goneStore) Delete(key string) error {
+	return fmt.Errorf("failed to delete %s: %w", key, ErrNotFound)
+}
+
+func TestApplyRetention_DeletedMeanwhile(t *testing.T) {
+	store := goneStore{NewMemoryStore()}
+	for _, key := range []string{"a-x-1000000.png", "a-x-900000.png"} {
+		_ = store.Put(key, []byte("1"))
+	}
+
+	deleted, err := ApplyRetention(store, Retention{MaxPerHost: 1}, time.Unix(1000000, 0))
+	if err != nil {
+		t.Fatalf("ApplyRetention() error = %v, want screenshots which are gone to be skipped", err)
+	}
+	if deleted != 0 {
+		t.Errorf("ApplyRetention() deleted %d screenshots, want 0 since it was gone already", deleted)
+	}
+}
+
+func TestListInfo(t *testing.T) {
+	store := NewMemoryStore()
+	for _, key := range []string{"a-x-1.png", "a-x-2.png", "a-sol-5.log", "a-b-x-3.png", "ab-x-4.png"} {
+		_ = store.Put(key, []byte("1"))
+	}
+
+	infos, err := ListInfo(store, "a")
+	if err != nil {
+		t.Fatalf("ListInfo() error = %v", err)
+	}
+
+	got := make([]string, 0, len(infos))
+	for _, info := range infos {
+		got = append(got, info.Key)
+	}
+
+	if want := []string{"a-x-2.png", "a-x-1.png"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ListInfo() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,197 @@
+package screenshot
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+const defaultPresignExpiry = time.Hour
+
+type (
+	S3Config struct {
+		Bucket          string
+		Folder          string
+		Region          string
+		AccessKeyID     string
+		SecretAccessKey string
+		Endpoint        string
+		ACL             string
+		// ServerSideEncryption is AES256 or aws:kms, SSEKMSKeyID selects the key for the latter
+		ServerSideEncryption string
+		SSEKMSKeyID          string
+		StorageClass         string
+		// PresignExpiry is the validity of the URLs returned for screenshots, 1 hour by default
+		PresignExpiry time.Duration
+	}
+
+	// S3Store keeps screenshots in a bucket of a S3 compatible storage
+	S3Store struct {
+		config S3Config
+		client *s3.S3
+	}
+)
+
+func NewS3Store(config S3Config) (*S3Store, error) {
+	s3Config := &aws.Config{
+		Credentials:      credentials.NewStaticCredentials(config.AccessKeyID, config.SecretAccessKey, ""),
+		Endpoint:         aws.String(config.Endpoint),
+		Region:           aws.String(config.Region),
+		DisableSSL:       aws.Bool(false),
+		S3ForcePathStyle: aws.Bool(true),
+	}
+
+	newSession, err := session.NewSession(s3Config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create S3 session: %w", err)
+	}
+
+	store := &S3Store{config: config, client: s3.New(newSession)}
+
+	if _, err := store.client.HeadBucket(&s3.HeadBucketInput{Bucket: aws.String(config.Bucket)}); err != nil {
+		return nil, fmt.Errorf("bucket %s is not accessible: %w", config.Bucket, err)
+	}
+
+	return store, nil
+}
+
+func (s *S3Store) Put(key string, payload []byte) error {
+	if err := validateKey(key); err != nil {
+		return err
+	}
+
+	input := &s3.PutObjectInput{
+		Body:          bytes.NewReader(payload),
+		ContentType:   aws.String(http.DetectContentType(payload)),
+		Bucket:        aws.String(s.config.Bucket),
+		ContentLength: aws.Int64(int64(len(payload))),
+		Key:           aws.String(s.objectKey(key)),
+	}
+	if s.config.ACL != "" {
+		input.ACL = aws.String(s.config.ACL)
+	}
+	if s.config.ServerSideEncryption != "" {
+		input.ServerSideEncryption = aws.String(s.config.ServerSideEncryption)
+	}
+	if s.config.SSEKMSKeyID != "" {
+		input.SSEKMSKeyId = aws.String(s.config.SSEKMSKeyID)
+	}
+	if s.config.StorageClass != "" {
+		input.StorageClass = aws.String(s.config.StorageClass)
+	}
+
+	if _, err := s.client.PutObject(input); err != nil {
+		return fmt.Errorf("failed to upload data to %s/%s: %w", s.config.Bucket, s.objectKey(key), err)
+	}
+
+	return nil
+}
+
+func (s *S3Store) Get(key string) ([]byte, error) {
+	out, err := s.client.GetObject(&s3.GetObjectInput{
+		Bucket: aws.String(s.config.Bucket),
+		Key:    aws.String(s.objectKey(key)),
+	})
+	if err != nil {
+		return nil, convertS3Error(err)
+	}
+	defer out.Body.Close()
+
+	return ioutil.ReadAll(out.Body)
+}
+
+func (s *S3Store) List(prefix string) ([]Object, error) {
+	objects := make([]Object, 0)
+	folderPrefix := s.objectKey("")
+
+	err := s.client.ListObjectsV2Pages(&s3.ListObjectsV2Input{
+		Bucket: aws.String(s.config.Bucket),
+		Prefix: aws.String(folderPrefix + prefix),
+	}, func(page *s3.ListObjectsV2Output, _ bool) bool {
+		for _, object := range page.Contents {
+			key := strings.TrimPrefix(aws.StringValue(object.Key), folderPrefix)
+			if strings.Contains(key, "/") {
+				continue
+			}
+			objects = append(objects, Object{
+				Key:      key,
+				Size:     aws.Int64Value(object.Size),
+				Modified: aws.TimeValue(object.LastModified),
+			})
+		}
+		return true
+	})
+	if err != nil {
+		return nil, convertS3Error(err)
+	}
+
+	return objects, nil
+}
+
+func (s *S3Store) Delete(key string) error {
+	input := &s3.HeadObjectInput{Bucket: aws.String(s.config.Bucket), Key: aws.String(s.objectKey(key))}
+	if _, err := s.client.HeadObject(input); err != nil {
+		return convertS3Error(err)
+	}
+
+	_, err := s.client.DeleteObject(&s3.DeleteObjectInput{
+		Bucket: aws.String(s.config.Bucket),
+		Key:    aws.String(s.objectKey(key)),
+	})
+
+	return convertS3Error(err)
+}
+
+// URL returns a presigned GET URL, the bucket doesn't have to be public
+func (s *S3Store) URL(key string) (string, error) {
+	req, _ := s.client.GetObjectRequest(&s3.GetObjectInput{
+		Bucket: aws.String(s.config.Bucket),
+		Key:    aws.String(s.objectKey(key)),
+	})
+
+	expiry := s.config.PresignExpiry
+	if expiry <= 0 {
+		expiry = defaultPresignExpiry
+	}
+
+	url, err := req.Presign(expiry)
+	if err != nil {
+		return "", fmt.Errorf("failed to presign the URL of %s: %w", key, err)
+	}
+
+	return url, nil
+}
+
+// objectKey places the key in the configured folder, an empty key gives the folder prefix
+func (s *S3Store) objectKey(key string) string {
+	folder := strings.Trim(s.config.Folder, "/")
+	if folder == "" {
+		return key
+	}
+	if key == "" {
+		return folder + "/"
+	}
+	return path.Join(folder, key)
+}
+
+func convertS3Error(err error) error {
+	var awsErr awserr.Error
+	if errors.As(err, &awsErr) {
+		switch awsErr.Code() {
+		case s3.ErrCodeNoSuchKey, "NotFound":
+			return ErrNotFound
+		}
+	}
+	return err
+}
@@ -0,0 +1,122 @@
+package screenshot
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeS3 is a minimal S3 compatible server supporting HEAD on buckets and PUT and GET on objects
+type fakeS3 struct {
+	bucket string
+
+	lock    sync.Mutex
+	objects map[string][]byte
+	headers map[string]http.Header
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	parts := strings.SplitN(strings.TrimPrefix(r.URL.Path, "/"), "/", 2)
+	if parts[0] != f.bucket {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	if len(parts) == 1 {
+		if r.Method == http.MethodHead {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	key := parts[1]
+	switch r.Method {
+	case http.MethodPut:
+		body, _ := ioutil.ReadAll(r.Body)
+		f.objects[key] = body
+		f.headers[key] = r.Header.Clone()
+		w.WriteHeader(http.StatusOK)
+	case http.MethodGet:
+		body, ok := f.objects[key]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write(body)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func newTestS3Store(t *testing.T, bucket string) (*S3Store, *fakeS3, error) {
+	fake := &fakeS3{bucket: "screenshots", objects: make(map[string][]byte), headers: make(map[string]http.Header)}
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+
+	store, err := NewS3Store(S3Config{
+		Bucket:               bucket,
+		Folder:               "/actor/",
+		Region:               "us-east-1",
+		AccessKeyID:          "key",
+		SecretAccessKey:      "secret",
+		Endpoint:             server.URL,
+		ServerSideEncryption: "AES256",
+		StorageClass:         "STANDARD_IA",
+		PresignExpiry:        10 * time.Minute,
+	})
+
+	return store, fake, err
+}
+
+func TestNewS3Store_MissingBucket(t *testing.T) {
+	if _, _, err := newTestS3Store(t, "missing"); err == nil {
+		t.Errorf("NewS3Store() with a missing bucket succeeded")
+	}
+}
+
+func TestS3Store_PutAndURL(t *testing.T) {
+	store, fake, err := newTestS3Store(t, "screenshots")
+	if err != nil {
+		t.Fatalf("NewS3Store() error = %v", err)
+	}
+
+	if err := store.Put("host1-idrac9-1.png", []byte("one")); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+
+	headers := fake.headers["actor/host1-idrac9-1.png"]
+	if got := headers.Get("X-Amz-Server-Side-Encryption"); got != "AES256" {
+		t.Errorf("server side encryption = %q, want %q", got, "AES256")
+	}
+	if got := headers.Get("X-Amz-Storage-Class"); got != "STANDARD_IA" {
+		t.Errorf("storage class = %q, want %q", got, "STANDARD_IA")
+	}
+
+	url, err := store.URL("host1-idrac9-1.png")
+	if err != nil {
+		t.Fatalf("URL() error = %v", err)
+	}
+	if !strings.Contains(url, "X-Amz-Signature=") || !strings.Contains(url, "X-Amz-Expires=600") {
+		t.Errorf("URL() = %s, want a presigned URL valid for 10 minutes", url)
+	}
+
+	resp, err := http.Get(url)
+	if err != nil {
+		t.Fatalf("GET %s error = %v", url, err)
+	}
+	defer resp.Body.Close()
+
+	body, _ := ioutil.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK || string(body) != "one" {
+		t.Errorf("GET %s = %d %q, want %d %q", url, resp.StatusCode, body, http.StatusOK, "one")
+	}
+}
@@ -0,0 +1,29 @@
+package screenshot
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrNotFound is returned by stores for unknown keys
+var ErrNotFound = errors.New("screenshot not found")
+
+type (
+	// Store keeps screenshots under flat keys, new backends only have to implement it
+	Store interface {
+		Put(key string, payload []byte) error
+		Get(key string) ([]byte, error)
+		// List returns the screenshots whose keys start with prefix
+		List(prefix string) ([]Object, error)
+		Delete(key string) error
+		// URL returns the address a client can fetch the screenshot from
+		URL(key string) (string, error)
+	}
+
+	// Object describes a stored screenshot
+	Object struct {
+		Key      string
+		Size     int64
+		Modified time.Time
+	}
+)
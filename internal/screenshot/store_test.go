@@ -0,0 +1,79 @@
+package screenshot
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+type testScreenshoter struct{}
+
+func (s *testScreenshoter) Screenshot() ([]byte, string, error) {
+	return []byte("png"), "png", nil
+}
+
+func (s *testScreenshoter) HardwareType() string {
+	return "idrac9"
+}
+
+func testStores(t *testing.T) map[string]Store {
+	local, err := NewLocalStore(t.TempDir(), "/screenshot")
+	if err != nil {
+		t.Fatalf("NewLocalStore() error = %v", err)
+	}
+
+	return map[string]Store{"local": local, "memory": NewMemoryStore()}
+}
+
+func TestStore(t *testing.T) {
+	for name, store := range testStores(t) {
+		t.Run(name, func(t *testing.T) {
+			if err := store.Put("host1-idrac9-1.png", []byte("one")); err != nil {
+				t.Fatalf("Put() error = %v", err)
+			}
+			if err := store.Put("host2-idrac9-1.png", []byte("two")); err != nil {
+				t.Fatalf("Put() error = %v", err)
+			}
+			if err := store.Put("../escape.png", []byte("bad")); err == nil {
+				t.Errorf("Put() with an invalid key succeeded")
+			}
+
+			payload, err := store.Get("host1-idrac9-1.png")
+			if err != nil || !bytes.Equal(payload, []byte("one")) {
+				t.Errorf("Get() = %q, %v, want %q", payload, err, "one")
+			}
+
+			objects, err := store.List("host1-")
+			if err != nil || len(objects) != 1 || objects[0].Key != "host1-idrac9-1.png" || objects[0].Size != 3 {
+				t.Errorf("List() = %+v, %v", objects, err)
+			}
+
+			if err := store.Delete("host1-idrac9-1.png"); err != nil {
+				t.Errorf("Delete() error = %v", err)
+			}
+			if _, err := store.Get("host1-idrac9-1.png"); !errors.Is(err, ErrNotFound) {
+				t.Errorf("Get() after Delete() error = %v, want %v", err, ErrNotFound)
+			}
+			if err := store.Delete("host1-idrac9-1.png"); !errors.Is(err, ErrNotFound) {
+				t.Errorf("Delete() of a missing key error = %v, want %v", err, ErrNotFound)
+			}
+		})
+	}
+}
+
+func TestTake(t *testing.T) {
+	store := NewMemoryStore()
+
+	url, err := Take(&testScreenshoter{}, "host1", store)
+	if err != nil {
+		t.Fatalf("Take() error = %v", err)
+	}
+	if !strings.HasPrefix(url, "memory://host1-idrac9-") || !strings.HasSuffix(url, ".png") {
+		t.Errorf("Take() url = %v", url)
+	}
+
+	if objects, _ := store.List("host1-"); len(objects) != 1 {
+		t.Errorf("List() = %+v, want one screenshot", objects)
+	}
+}
@@ -0,0 +1,87 @@
+package internal
+
+import (
+	"fmt"
+
+	"github.com/bmc-toolbox/actor/internal/actions"
+	"github.com/bmc-toolbox/actor/internal/providers/ipmi"
+)
+
+type (
+	SelExecutorFactory struct {
+		username  string
+		passwords passwordSource
+	}
+
+	// SelExecutor clears the System Event Log through IPMI, bmclib doesn't expose the SEL
+	SelExecutor struct {
+		username string
+		password string
+		host     string
+	}
+
+	// SelReader reads the System Event Log of hosts through IPMI
+	SelReader struct {
+		username  string
+		passwords passwordSource
+	}
+)
+
+func NewSelExecutorFactory(username string, passwords passwordSource) *SelExecutorFactory {
+	return &SelExecutorFactory{username: username, passwords: passwords}
+}
+
+func (f *SelExecutorFactory) New(params map[string]interface{}) (actions.Executor, error) {
+	if err := validateParam(params, actions.ParamHost); err != nil {
+		return nil, fmt.Errorf("failed to create a new executor: %w", err)
+	}
+
+	host := fmt.Sprintf("%v", params[actions.ParamHost])
+
+	return &SelExecutor{username: f.username, password: f.passwords.Password(host), host: host}, nil
+}
+
+func (e *SelExecutor) Validate(action string) error {
+	if action != actions.SelClear {
+		return fmt.Errorf("unknown action %q", action)
+	}
+	return nil
+}
+
+func (e *SelExecutor) Run(action string) actions.ActionResult {
+	if err := e.Validate(action); err != nil {
+		return actions.NewActionResult(action, false, "failed", err)
+	}
+
+	bmc, err := ipmi.New(e.username, e.password, e.host)
+	if err != nil {
+		return actions.NewActionResult(action, false, "failed", fmt.Errorf("failed to setup IPMI connection: %w", err))
+	}
+
+	status, err := bmc.ClearSel()
+	if err != nil {
+		return actions.NewActionResult(action, status, "failed", err)
+	}
+	return actions.NewActionResult(action, status, "ok", nil)
+}
+
+func (e *SelExecutor) Capabilities() (actions.Capabilities, error) {
+	return actions.Capabilities{Actions: []string{actions.SelClear}}, nil
+}
+
+func (e *SelExecutor) Cleanup() {
+}
+
+func NewSelReader(username string, passwords passwordSource) *SelReader {
+	return &SelReader{username: username, passwords: passwords}
+}
+
+// Read returns the entries of the System Event Log of the host
+func (r *SelReader) Read(host string) ([]ipmi.SelEntry, error) {
+	bmc, err := ipmi.New(r.username, r.passwords.Password(host), host)
+	if err != nil {
+		return nil, fmt.Errorf("failed to setup IPMI connection: %w", err)
+	}
+
+	return bmc.ReadSel()
+}
@@ -0,0 +1,31 @@
+package internal
+
+import (
+	"context"
+
+	"github.com/bmc-toolbox/actor/internal/providers"
+)
+
+// SensorReader reads the sensors of hosts and chassis
+type SensorReader struct {
+	username  string
+	passwords passwordSource
+}
+
+func NewSensorReader(username string, passwords passwordSource) *SensorReader {
+	return &SensorReader{username: username, passwords: passwords}
+}
+
+func (r *SensorReader) HostSensors(host string) ([]providers.Sensor, error) {
+	bmc := providers.NewServerBmcWrapper(r.username, r.passwords.Password(host), host)
+	defer func() { _ = bmc.Close(context.TODO()) }()
+
+	return bmc.Sensors()
+}
+
+func (r *SensorReader) ChassisSensors(host string) ([]providers.Sensor, error) {
+	bmc := providers.NewChassisBmcWrapper(r.username, r.passwords.Password(host), host)
+	defer func() { _ = bmc.Close() }()
+
+	return bmc.Sensors()
+}
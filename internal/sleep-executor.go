@@ -41,6 +41,10 @@ func (e *SleepExecutor) Run(action string) actions.ActionResult {
 	return actions.NewActionResult(action, true, "ok", nil)
 }
 
+func (e *SleepExecutor) Capabilities() (actions.Capabilities, error) {
+	return actions.Capabilities{Actions: []string{actions.Sleep}}, nil
+}
+
 func (e *SleepExecutor) Cleanup() {
 }
 
@@ -0,0 +1,151 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/bmc-toolbox/actor/internal/actions"
+	"github.com/bmc-toolbox/actor/internal/providers/ipmi"
+	"github.com/bmc-toolbox/actor/internal/screenshot"
+)
+
+const (
+	solCapturePrefix = "sol-capture "
+	// maxSolCapture bounds how long a capture holds the SOL session of the BMC
+	maxSolCapture = 30 * time.Minute
+)
+
+type (
+	SolExecutorFactory struct {
+		username  string
+		passwords passwordSource
+		store     screenshot.Store
+	}
+
+	// SolExecutor records the serial console of a host through IPMI SOL, which works regardless of the BMC vendor
+	SolExecutor struct {
+		username string
+		password string
+		host     string
+		store    screenshot.Store
+		// newSolProvider connects to the BMC, it is replaced in tests
+		newSolProvider func(username, password, host string) (solProvider, error)
+	}
+
+	solProvider interface {
+		CaptureSol(context.Context, *regexp.Regexp) ([]byte, bool, error)
+	}
+)
+
+// NewSolExecutorFactory creates a factory of SOL executors, the transcripts are kept in the screenshot store
+func NewSolExecutorFactory(username string, passwords passwordSource, store screenshot.Store) *SolExecutorFactory {
+	return &SolExecutorFactory{username: username, passwords: passwords, store: store}
+}
+
+func (f *SolExecutorFactory) New(params map[string]interface{}) (actions.Executor, error) {
+	if err := validateParam(params, actions.ParamHost); err != nil {
+		return nil, fmt.Errorf("failed to create a new executor: %w", err)
+	}
+
+	host := fmt.Sprintf("%v", params[actions.ParamHost])
+
+	return &SolExecutor{
+		username: f.username,
+		password: f.passwords.Password(host),
+		host:     host,
+		store:    f.store,
+		newSolProvider: func(username, password, host string) (solProvider, error) {
+			provider, err := ipmi.New(username, password, host)
+			if err != nil {
+				return nil, fmt.Errorf("failed to setup IPMI connection: %w", err)
+			}
+			return provider, nil
+		},
+	}, nil
+}
+
+func (e *SolExecutor) Validate(action string) error {
+	_, _, err := parseSolCapture(action)
+	return err
+}
+
+func (e *SolExecutor) Run(action string) actions.ActionResult {
+	duration, until, err := parseSolCapture(action)
+	if err != nil {
+		return actions.NewActionResult(action, false, "failed", err)
+	}
+
+	provider, err := e.newSolProvider(e.username, e.password, e.host)
+	if err != nil {
+		return actions.NewActionResult(action, false, "failed", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), duration)
+	defer cancel()
+
+	transcript, matched, err := provider.CaptureSol(ctx, until)
+	if err != nil && len(transcript) == 0 {
+		return actions.NewActionResult(action, false, "failed", err)
+	}
+
+	key := fmt.Sprintf("%s-%s-%d.%s", e.host, screenshot.SolHardwareType, time.Now().Unix(), screenshot.SolExtension)
+	if putErr := e.store.Put(key, transcript); putErr != nil {
+		return actions.NewActionResult(action, false, "failed", fmt.Errorf("failed to store SOL transcript %s: %w", key, putErr))
+	}
+
+	url, urlErr := e.store.URL(key)
+	if urlErr != nil {
+		return actions.NewActionResult(action, false, "failed", urlErr)
+	}
+
+	if err != nil {
+		return actions.NewActionResult(action, false, url, err)
+	}
+
+	if until != nil && !matched {
+		return actions.NewActionResult(action, false, url, fmt.Errorf("%q not seen on the console within %s", until, duration))
+	}
+
+	return actions.NewActionResult(action, true, url, nil)
+}
+
+func (e *SolExecutor) Capabilities() (actions.Capabilities, error) {
+	return actions.Capabilities{Actions: []string{actions.SolCapture}}, nil
+}
+
+func (e *SolExecutor) Cleanup() {
+}
+
+// parseSolCapture parses "sol-capture <duration> [regexp]", the capture stops early when the regexp matches
+func parseSolCapture(action string) (time.Duration, *regexp.Regexp, error) {
+	if !strings.HasPrefix(action, solCapturePrefix) {
+		return 0, nil, fmt.Errorf("%q is not a sol-capture action", action)
+	}
+
+	args := strings.SplitN(strings.TrimSpace(strings.TrimPrefix(action, solCapturePrefix)), " ", 2)
+
+	duration, err := time.ParseDuration(args[0])
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to parse duration in sol-capture action: %w", err)
+	}
+	if duration <= 0 {
+		return 0, nil, fmt.Errorf("sol-capture duration must be positive, got %s", duration)
+	}
+	if duration > maxSolCapture {
+		return 0, nil, fmt.Errorf("sol-capture duration must be at most %s, got %s", maxSolCapture, duration)
+	}
+
+	if len(args) == 1 || strings.TrimSpace(args[1]) == "" {
+		return duration, nil, nil
+	}
+
+	until, err := regexp.Compile(strings.TrimSpace(args[1]))
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to parse the pattern in sol-capture action: %w", err)
+	}
+
+	return duration, until, nil
+}
@@ -0,0 +1,79 @@
+package internal
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bmc-toolbox/actor/internal/screenshot"
+)
+
+type testSolProvider struct {
+	transcript string
+}
+
+func (p *testSolProvider) CaptureSol(_ context.Context, until *regexp.Regexp) ([]byte, bool, error) {
+	return []byte(p.transcript), until != nil && until.MatchString(p.transcript), nil
+}
+
+func Test_parseSolCapture(t *testing.T) {
+	tests := []struct {
+		name      string
+		action    string
+		want      time.Duration
+		wantUntil string
+		wantErr   bool
+	}{
+		{name: "Duration", action: "sol-capture 60s", want: time.Minute},
+		{name: "Duration and pattern", action: "sol-capture 5m login: ?$", want: 5 * time.Minute, wantUntil: "login: ?$"},
+		{name: "No duration", action: "sol-capture ", wantErr: true},
+		{name: "Negative duration", action: "sol-capture -1s", wantErr: true},
+		{name: "Too long", action: "sol-capture 2h", wantErr: true},
+		{name: "Invalid pattern", action: "sol-capture 1s (", wantErr: true},
+		{name: "Other action", action: "sleep 1s", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, until, err := parseSolCapture(tt.action)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseSolCapture() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if got != tt.want {
+				t.Errorf("parseSolCapture() = %v, want %v", got, tt.want)
+			}
+			if (until == nil && tt.wantUntil != "") || (until != nil && until.String() != tt.wantUntil) {
+				t.Errorf("parseSolCapture() until = %v, want %q", until, tt.wantUntil)
+			}
+		})
+	}
+}
+
+func TestSolExecutor_Run(t *testing.T) {
+	store := screenshot.NewMemoryStore()
+	e := &SolExecutor{
+		host:  "host1",
+		store: store,
+		newSolProvider: func(_, _, _ string) (solProvider, error) {
+			return &testSolProvider{transcript: "Booting...\nhost1 login: "}, nil
+		},
+	}
+
+	result := e.Run("sol-capture 1s login:")
+	if !result.Status || !strings.HasPrefix(result.Message, "memory://host1-sol-") {
+		t.Fatalf("Run() = %+v, want a stored transcript", result)
+	}
+
+	payload, err := store.Get(strings.TrimPrefix(result.Message, "memory://"))
+	if err != nil || !strings.Contains(string(payload), "login:") {
+		t.Errorf("stored transcript = %q, %v", payload, err)
+	}
+
+	if result := e.Run("sol-capture 1s kernel panic"); result.Status || result.Error == nil {
+		t.Errorf("Run() = %+v, want a failure when the pattern isn't seen", result)
+	}
+}
@@ -0,0 +1,131 @@
+package internal
+
+import (
+	"fmt"
+
+	"github.com/bmc-toolbox/actor/internal/providers"
+	"github.com/bmc-toolbox/actor/internal/providers/ipmi"
+)
+
+type (
+	// UserManager manages the local users of host BMCs through IPMI and of blade BMCs through their chassis,
+	// passwords are read from the credential store
+	UserManager struct {
+		username    string
+		passwords   passwordSource
+		credentials passwordStore
+	}
+
+	passwordStore interface {
+		Password(ref string) (string, error)
+	}
+)
+
+func NewUserManager(username string, passwords passwordSource, credentials passwordStore) *UserManager {
+	return &UserManager{username: username, passwords: passwords, credentials: credentials}
+}
+
+func (m *UserManager) HostUsers(host string) ([]ipmi.User, error) {
+	bmc, err := m.ipmi(host)
+	if err != nil {
+		return nil, err
+	}
+	return bmc.Users()
+}
+
+func (m *UserManager) CreateHostUser(host, name, passwordRef, role string) error {
+	password, err := m.credentials.Password(passwordRef)
+	if err != nil {
+		return err
+	}
+
+	bmc, err := m.ipmi(host)
+	if err != nil {
+		return err
+	}
+	return bmc.CreateUser(name, password, role)
+}
+
+// UpdateHostUser changes the password and the role of the user, an empty reference or role leaves them unchanged
+func (m *UserManager) UpdateHostUser(host, name, passwordRef, role string) error {
+	password := ""
+	if passwordRef != "" {
+		var err error
+		if password, err = m.credentials.Password(passwordRef); err != nil {
+			return err
+		}
+	}
+
+	bmc, err := m.ipmi(host)
+	if err != nil {
+		return err
+	}
+	return bmc.UpdateUser(name, password, role)
+}
+
+func (m *UserManager) DeleteHostUser(host, name string) error {
+	bmc, err := m.ipmi(host)
+	if err != nil {
+		return err
+	}
+	return bmc.DisableUser(name)
+}
+
+// BladeUsers lists the users of the BMC of the blade at the position, the chassis reports the address of the BMC
+func (m *UserManager) BladeUsers(host string, bladePos int) ([]ipmi.User, error) {
+	chassis := providers.NewBladeBmcWrapper(m.username, m.passwords.Password(host), host)
+	defer func() { _ = chassis.Close() }()
+
+	address, err := chassis.BladeBmcAddress(bladePos)
+	if err != nil {
+		return nil, err
+	}
+
+	return m.HostUsers(address)
+}
+
+// CreateBladeUsers creates an admin user on all the blade BMCs of the chassis
+func (m *UserManager) CreateBladeUsers(host, name, passwordRef string) error {
+	password, err := m.credentials.Password(passwordRef)
+	if err != nil {
+		return err
+	}
+
+	chassis := providers.NewBladeBmcWrapper(m.username, m.passwords.Password(host), host)
+	defer func() { _ = chassis.Close() }()
+
+	return chassis.AddBladeBmcAdmin(name, password)
+}
+
+// UpdateBladeUsers changes the password of a user on all the blade BMCs of the chassis
+func (m *UserManager) UpdateBladeUsers(host, name, passwordRef string) error {
+	password, err := m.credentials.Password(passwordRef)
+	if err != nil {
+		return err
+	}
+
+	chassis := providers.NewBladeBmcWrapper(m.username, m.passwords.Password(host), host)
+	defer func() { _ = chassis.Close() }()
+
+	return chassis.ModBladeBmcUser(name, password)
+}
+
+// DeleteBladeUsers removes a user from all the blade BMCs of the chassis
+func (m *UserManager) DeleteBladeUsers(host, name string) error {
+	if name == m.username {
+		return fmt.Errorf("refusing to remove %s, the user actor connects with", name)
+	}
+
+	chassis := providers.NewBladeBmcWrapper(m.username, m.passwords.Password(host), host)
+	defer func() { _ = chassis.Close() }()
+
+	return chassis.RemoveBladeBmcUser(name)
+}
+
+func (m *UserManager) ipmi(host string) (*ipmi.Ipmi, error) {
+	bmc, err := ipmi.New(m.username, m.passwords.Password(host), host)
+	if err != nil {
+		return nil, fmt.Errorf("failed to setup IPMI connection: %w", err)
+	}
+	return bmc, nil
+}
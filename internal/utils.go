@@ -1,6 +1,24 @@
 package internal
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+
+	"github.com/bmc-toolbox/actor/internal/actions"
+	"github.com/bmc-toolbox/actor/internal/providers"
+	"github.com/bmc-toolbox/actor/internal/screenshot"
+)
+
+// passwordSource returns the BMC password of a host
+type passwordSource interface {
+	Password(host string) string
+}
+
+type screenshotProvider interface {
+	Screenshot() ([]byte, string, error)
+	HardwareType() string
+	SupportsScreenshot() (bool, error)
+}
 
 func validateParam(params map[string]interface{}, param ...string) error {
 	for _, p := range param {
@@ -10,3 +28,70 @@ func validateParam(params map[string]interface{}, param ...string) error {
 	}
 	return nil
 }
+
+// parseToggle parses "<name> on" and "<name> off", ok is false for other actions
+func parseToggle(action, name string) (enable, ok bool, err error) {
+	args := strings.Fields(action)
+	if len(args) == 0 || args[0] != name {
+		return false, false, nil
+	}
+
+	if len(args) == 2 {
+		switch args[1] {
+		case "on":
+			return true, true, nil
+		case "off":
+			return false, true, nil
+		}
+	}
+
+	return false, true, fmt.Errorf("invalid action %q: %s on or %s off is expected", action, name, name)
+}
+
+func newCapabilities(supported []string, info providers.BmcInfo) actions.Capabilities {
+	return actions.Capabilities{
+		Actions:         supported,
+		Vendor:          info.Vendor,
+		HardwareType:    info.HardwareType,
+		Provider:        info.Provider,
+		FirmwareVersion: info.FirmwareVersion,
+	}
+}
+
+// takeScreenshot checks that the BMC supports screenshots before taking one, so hosts behind
+// the IPMI fallback get a clear error
+func takeScreenshot(bmc screenshotProvider, host string, store screenshot.Store) (string, error) {
+	if err := checkScreenshotSupport(bmc); err != nil {
+		return "", err
+	}
+
+	return screenshot.Take(bmc, host, store)
+}
+
+// analyzeScreenshot takes a screenshot and describes how it compares to the previous one and the references
+func analyzeScreenshot(bmc screenshotProvider, host string, store screenshot.Store, analyzer *screenshot.Analyzer) (string, error) {
+	if err := checkScreenshotSupport(bmc); err != nil {
+		return "", err
+	}
+
+	analysis, err := analyzer.Analyze(bmc, host, store)
+	if err != nil {
+		return "", err
+	}
+
+	return analysis.String(), nil
+}
+
+// checkScreenshotSupport relies on the hardware type, so a screenshot costs no more round-trips than the screenshot itself
+func checkScreenshotSupport(bmc screenshotProvider) error {
+	supported, err := bmc.SupportsScreenshot()
+	if err != nil {
+		return err
+	}
+
+	if !supported {
+		return fmt.Errorf("%w (hardware type %s)", screenshot.ErrUnsupported, bmc.HardwareType())
+	}
+
+	return nil
+}
@@ -1,6 +1,61 @@
 package internal
 
-import "testing"
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/bmc-toolbox/actor/internal/actions"
+	"github.com/bmc-toolbox/actor/internal/credentials"
+	"github.com/bmc-toolbox/actor/internal/providers"
+	"github.com/bmc-toolbox/actor/internal/screenshot"
+)
+
+type (
+	testScreenshotProvider struct {
+		info providers.BmcInfo
+	}
+
+	// testBladeBmcProvider implements only the chassis methods used by screenshots and IPMI over LAN
+	testBladeBmcProvider struct {
+		bladeBmcProvider
+		addresses map[int]string
+		ipmiLan   map[int]bool
+	}
+)
+
+func (p *testScreenshotProvider) Screenshot() ([]byte, string, error) {
+	if !p.info.SupportsScreenshot {
+		return nil, "", screenshot.ErrUnsupported
+	}
+	return []byte("png"), "png", nil
+}
+
+func (p *testScreenshotProvider) HardwareType() string {
+	return p.info.HardwareType
+}
+
+func (p *testScreenshotProvider) SupportsScreenshot() (bool, error) {
+	return p.info.SupportsScreenshot, nil
+}
+
+func (p *testScreenshotProvider) Close(context.Context) error {
+	return nil
+}
+
+func (p *testBladeBmcProvider) BladeBmcAddress(position int) (string, error) {
+	address, ok := p.addresses[position]
+	if !ok {
+		return "", errors.New("no blade")
+	}
+	return address, nil
+}
+
+func (p *testBladeBmcProvider) SetIpmiOverLan(position int, enable bool) (bool, error) {
+	p.ipmiLan[position] = enable
+	return true, nil
+}
 
 func Test_validateParam(t *testing.T) {
 	type args struct {
@@ -95,3 +150,93 @@ func Test_validateParam(t *testing.T) {
 		})
 	}
 }
+
+func Test_takeScreenshot(t *testing.T) {
+	store := screenshot.NewMemoryStore()
+
+	ipmi := &testScreenshotProvider{info: providers.BmcInfo{Provider: providers.ProviderIpmi}}
+	if _, err := takeScreenshot(ipmi, "host", store); !errors.Is(err, screenshot.ErrUnsupported) {
+		t.Errorf("takeScreenshot() on IPMI error = %v, want %v", err, screenshot.ErrUnsupported)
+	}
+
+	bmclib := &testScreenshotProvider{info: providers.BmcInfo{Provider: providers.ProviderBmclib, HardwareType: "idrac9", SupportsScreenshot: true}}
+	url, err := takeScreenshot(bmclib, "host", store)
+	if err != nil {
+		t.Fatalf("takeScreenshot() error = %v", err)
+	}
+	if !strings.HasPrefix(url, "memory://host-idrac9-") {
+		t.Errorf("takeScreenshot() = %s, want a memory:// URL of host", url)
+	}
+}
+
+func Test_baseBladeExecutor_doScreenshot(t *testing.T) {
+	store := screenshot.NewMemoryStore()
+
+	var connected string
+	e := &baseBladeExecutor{
+		bmc:             &testBladeBmcProvider{addresses: map[int]string{1: "blade1-bmc"}},
+		passwords:       credentials.NewKeyring(credentials.NewFileStore(""), "secret"),
+		screenshotStore: store,
+		newBladeBmc: func(_, _, host string) bladeScreenshotProvider {
+			connected = host
+			return &testScreenshotProvider{info: providers.BmcInfo{HardwareType: "ilo5", SupportsScreenshot: true}}
+		},
+	}
+
+	if err := e.Validate(actions.Screenshot); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+
+	result := e.doAction(actions.Screenshot, 1)
+	if result.Error != nil || !strings.HasPrefix(result.Message, "memory://blade1-bmc-ilo5-") {
+		t.Errorf("doAction() = %+v, want a screenshot of blade1-bmc", result)
+	}
+	if connected != "blade1-bmc" {
+		t.Errorf("connected to %q, want %q", connected, "blade1-bmc")
+	}
+
+	if result := e.doAction(actions.Screenshot, 2); result.Error == nil {
+		t.Errorf("doAction() on an empty slot succeeded")
+	}
+}
+
+func Test_parseToggle(t *testing.T) {
+	tests := []struct {
+		action     string
+		wantEnable bool
+		wantOk     bool
+		wantErr    bool
+	}{
+		{action: "ipmilan on", wantEnable: true, wantOk: true},
+		{action: "ipmilan off", wantOk: true},
+		{action: "ipmilan", wantOk: true, wantErr: true},
+		{action: "ipmilan yes", wantOk: true, wantErr: true},
+		{action: "ipmilan on now", wantOk: true, wantErr: true},
+		{action: "ipmilanon"},
+		{action: "poweron"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.action, func(t *testing.T) {
+			enable, ok, err := parseToggle(tt.action, ipmiLanToggle)
+			if enable != tt.wantEnable || ok != tt.wantOk || (err != nil) != tt.wantErr {
+				t.Errorf("parseToggle() = %v, %v, %v, want %v, %v, error %v", enable, ok, err, tt.wantEnable, tt.wantOk, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_baseBladeExecutor_ipmiLan(t *testing.T) {
+	bmc := &testBladeBmcProvider{ipmiLan: map[int]bool{}}
+	e := &baseBladeExecutor{bmc: bmc}
+
+	if err := e.Validate("ipmilan maybe"); err == nil {
+		t.Errorf("Validate() accepted an invalid toggle")
+	}
+
+	if result := e.doAction("ipmilan off", 3); result.Error != nil || !result.Status {
+		t.Errorf("doAction() = %+v", result)
+	}
+	if enable, ok := bmc.ipmiLan[3]; !ok || enable {
+		t.Errorf("IPMI over LAN of blade 3 = %v, %v, want switched off", enable, ok)
+	}
+}
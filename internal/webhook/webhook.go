@@ -0,0 +1,231 @@
+package webhook
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/bmc-toolbox/actor/internal/actions"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	EventCompleted = "completed"
+	EventFailed    = "failed"
+
+	// SignatureHeader carries the hex encoded HMAC-SHA256 of the body, keyed with the configured secret
+	SignatureHeader = "X-Actor-Signature"
+	EventHeader     = "X-Actor-Event"
+)
+
+type (
+	Config struct {
+		// URLs are notified about every finished action sequence
+		URLs []string
+		// Secret signs the payloads, nothing is signed if it is empty
+		Secret string
+		// AllowPerRequest allows requests to name their own webhook URL
+		AllowPerRequest bool
+		MaxRetries      int
+		Backoff         time.Duration
+		Timeout         time.Duration
+		// DeadLetterFile collects the payloads which could not be delivered, as JSON lines
+		DeadLetterFile string
+	}
+
+	// Notifier delivers the outcome of execution plans to webhooks in the background
+	Notifier struct {
+		config Config
+		client *http.Client
+
+		deadLetterLock sync.Mutex
+		wg             sync.WaitGroup
+	}
+
+	Payload struct {
+		Event          string                 `json:"event"`
+		Target         map[string]interface{} `json:"target"`
+		ActionSequence []string               `json:"action-sequence"`
+		Status         bool                   `json:"status"`
+		Error          string                 `json:"error"`
+		Results        []actions.ActionRecord `json:"results"`
+		Started        time.Time              `json:"started"`
+		Finished       time.Time              `json:"finished"`
+		Duration       string                 `json:"duration"`
+	}
+
+	deadLetter struct {
+		Time    time.Time `json:"time"`
+		URL     string    `json:"url"`
+		Error   string    `json:"error"`
+		Payload Payload   `json:"payload"`
+	}
+)
+
+func New(config Config) *Notifier {
+	return &Notifier{config: config, client: &http.Client{Timeout: config.Timeout}}
+}
+
+// ValidateURL checks a webhook URL given in a request
+func (n *Notifier) ValidateURL(rawURL string) error {
+	if !n.config.AllowPerRequest {
+		return fmt.Errorf("webhooks per request are not allowed")
+	}
+
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return fmt.Errorf("invalid webhook %q: %w", rawURL, err)
+	}
+
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("invalid webhook %q: an absolute http(s) URL is required", rawURL)
+	}
+
+	return nil
+}
+
+// Observer returns a plan observer notifying the configured webhooks and the extra ones
+func (n *Notifier) Observer(extraURLs ...string) actions.PlanObserver {
+	urls := append(append([]string{}, n.config.URLs...), extraURLs...)
+
+	return func(report actions.PlanReport) {
+		if len(urls) == 0 {
+			return
+		}
+
+		payload := newPayload(report)
+		for _, u := range urls {
+			n.wg.Add(1)
+			go func(u string) {
+				defer n.wg.Done()
+				n.deliver(u, payload)
+			}(u)
+		}
+	}
+}
+
+// Wait blocks until the pending deliveries are done
+func (n *Notifier) Wait() {
+	n.wg.Wait()
+}
+
+func (n *Notifier) deliver(u string, payload Payload) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		n.deadLetter(u, payload, fmt.Errorf("failed to marshal payload: %w", err))
+		return
+	}
+
+	backoff := n.config.Backoff
+	for attempt := 0; ; attempt++ {
+		err = n.post(u, payload.Event, body)
+		if err == nil {
+			return
+		}
+
+		if attempt >= n.config.MaxRetries {
+			break
+		}
+
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+
+	n.deadLetter(u, payload, err)
+}
+
+func (n *Notifier) post(u, event string, body []byte) error {
+	req, err := http.NewRequest(http.MethodPost, u, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(EventHeader, event)
+	if n.config.Secret != "" {
+		req.Header.Set(SignatureHeader, "sha256="+Sign(n.config.Secret, body))
+	}
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+func (n *Notifier) deadLetter(u string, payload Payload, err error) {
+	logger := log.WithField("webhook", u).WithError(err)
+
+	if n.config.DeadLetterFile == "" {
+		logger.Error("failed to deliver webhook, dropping it")
+		return
+	}
+
+	logger.Error("failed to deliver webhook, writing it to the dead letter file")
+
+	line, err := json.Marshal(deadLetter{Time: time.Now().UTC(), URL: u, Error: err.Error(), Payload: payload})
+	if err != nil {
+		logger.WithError(err).Error("failed to marshal dead letter")
+		return
+	}
+
+	n.deadLetterLock.Lock()
+	defer n.deadLetterLock.Unlock()
+
+	f, err := os.OpenFile(n.config.DeadLetterFile, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		logger.WithError(err).Error("failed to open the dead letter file")
+		return
+	}
+	defer f.Close()
+
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		logger.WithError(err).Error("failed to write the dead letter file")
+	}
+}
+
+// Sign returns the hex encoded HMAC-SHA256 of the body
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	_, _ = mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func newPayload(report actions.PlanReport) Payload {
+	target := map[string]interface{}{"type": report.TargetType}
+	for key, value := range report.Params {
+		target[key] = value
+	}
+
+	payload := Payload{
+		Event:          EventCompleted,
+		Target:         target,
+		ActionSequence: report.Actions,
+		Status:         report.Error == nil,
+		Results:        actions.NewActionRecords(report.Results),
+		Started:        report.Started.UTC(),
+		Finished:       report.Finished.UTC(),
+		Duration:       report.Finished.Sub(report.Started).String(),
+	}
+
+	if report.Error != nil {
+		payload.Event = EventFailed
+		payload.Error = report.Error.Error()
+	}
+
+	return payload
+}
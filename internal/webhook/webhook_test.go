@@ -0,0 +1,111 @@
+package webhook
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/bmc-toolbox/actor/internal/actions"
+)
+
+func testReport(err error) actions.PlanReport {
+	started := time.Date(2021, 6, 15, 2, 0, 0, 0, time.UTC)
+	return actions.PlanReport{
+		TargetType: actions.TargetHost,
+		Params:     map[string]interface{}{"host": "host.example.com"},
+		Actions:    []string{actions.PowerOff},
+		Results:    []actions.ActionResult{actions.NewActionResult(actions.PowerOff, err == nil, "ok", err)},
+		Error:      err,
+		Started:    started,
+		Finished:   started.Add(time.Second),
+	}
+}
+
+func TestNotifier_Observer(t *testing.T) {
+	var received Payload
+	var signature string
+
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		signature = r.Header.Get(SignatureHeader)
+		if signature != "sha256="+Sign("secret", body) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_ = json.Unmarshal(body, &received)
+	}))
+	defer receiver.Close()
+
+	notifier := New(Config{URLs: []string{receiver.URL}, Secret: "secret"})
+	notifier.Observer()(testReport(fmt.Errorf("power off failed")))
+	notifier.Wait()
+
+	if signature == "" {
+		t.Fatalf("no signature received")
+	}
+	if received.Event != EventFailed || received.Status || received.Error != "power off failed" {
+		t.Errorf("received = %+v, want a failed event", received)
+	}
+	if received.Target["host"] != "host.example.com" || received.Target["type"] != actions.TargetHost {
+		t.Errorf("received target = %v", received.Target)
+	}
+	if received.Duration != "1s" {
+		t.Errorf("received duration = %v, want 1s", received.Duration)
+	}
+}
+
+func TestNotifier_DeadLetter(t *testing.T) {
+	var attempts int32
+
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&attempts, 1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer receiver.Close()
+
+	deadLetterFile := filepath.Join(t.TempDir(), "dead-letter.jsonl")
+	notifier := New(Config{MaxRetries: 2, Backoff: time.Millisecond, DeadLetterFile: deadLetterFile})
+	notifier.Observer(receiver.URL)(testReport(nil))
+	notifier.Wait()
+
+	if got := atomic.LoadInt32(&attempts); got != 3 {
+		t.Errorf("attempts = %d, want 3", got)
+	}
+
+	data, err := ioutil.ReadFile(deadLetterFile)
+	if err != nil {
+		t.Fatalf("failed to read the dead letter file: %v", err)
+	}
+	if lines := strings.Split(strings.TrimSpace(string(data)), "\n"); len(lines) != 1 {
+		t.Errorf("dead letters = %d, want 1", len(lines))
+	}
+}
+
+func TestNotifier_ValidateURL(t *testing.T) {
+	tests := []struct {
+		name            string
+		allowPerRequest bool
+		url             string
+		wantErr         bool
+	}{
+		{name: "OK", allowPerRequest: true, url: "https://hooks.example.com/actor", wantErr: false},
+		{name: "Not allowed", allowPerRequest: false, url: "https://hooks.example.com/actor", wantErr: true},
+		{name: "Relative", allowPerRequest: true, url: "/actor", wantErr: true},
+		{name: "Not http", allowPerRequest: true, url: "file:///etc/passwd", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			n := New(Config{AllowPerRequest: tt.allowPerRequest})
+			if err := n.ValidateURL(tt.url); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateURL() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,60 @@
+package routes
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"net"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ErrUnauthorized is returned for privileged requests without a valid token
+var ErrUnauthorized = errors.New("privileged actions require a valid bearer token")
+
+// Authorizer checks the bearer tokens of requests for privileged actions, without tokens nothing is authorized
+type Authorizer struct {
+	tokens []string
+}
+
+func NewAuthorizer(tokens []string) *Authorizer {
+	return &Authorizer{tokens: tokens}
+}
+
+// Authorize checks the "Authorization: Bearer <token>" header of the request
+func (a *Authorizer) Authorize(ctx *gin.Context) error {
+	token := bearerToken(ctx)
+	if token == "" {
+		return ErrUnauthorized
+	}
+
+	for _, t := range a.tokens {
+		if subtle.ConstantTimeCompare([]byte(t), []byte(token)) == 1 {
+			return nil
+		}
+	}
+
+	return ErrUnauthorized
+}
+
+// Identity names the client of the request for the blast-radius limits: the hash of its token when it is valid,
+// otherwise the address of the peer, so neither made-up tokens nor forwarding headers, which any client can set,
+// can be used to get around the limits
+func (a *Authorizer) Identity(ctx *gin.Context) string {
+	if a.Authorize(ctx) == nil {
+		sum := sha256.Sum256([]byte(bearerToken(ctx)))
+		return "token-" + hex.EncodeToString(sum[:4])
+	}
+
+	host, _, err := net.SplitHostPort(ctx.Request.RemoteAddr)
+	if err != nil {
+		host = ctx.Request.RemoteAddr
+	}
+	return "ip-" + host
+}
+
+func bearerToken(ctx *gin.Context) string {
+	return strings.TrimSpace(strings.TrimPrefix(ctx.GetHeader("Authorization"), "Bearer "))
+}
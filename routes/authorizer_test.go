@@ -0,0 +1,59 @@
+package routes
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestAuthorizer_Authorize(t *testing.T) {
+	tests := []struct {
+		name          string
+		tokens        []string
+		authorization string
+		wantErr       bool
+	}{
+		{name: "Valid token", tokens: []string{"one", "two"}, authorization: "Bearer two", wantErr: false},
+		{name: "Invalid token", tokens: []string{"one"}, authorization: "Bearer two", wantErr: true},
+		{name: "No header", tokens: []string{"one"}, wantErr: true},
+		{name: "No tokens configured", authorization: "Bearer one", wantErr: true},
+		{name: "Empty bearer", tokens: []string{""}, authorization: "Bearer ", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
+			ctx.Request = httptest.NewRequest(http.MethodPost, "/host/1.1.1.1", nil)
+			ctx.Request.Header.Set("Authorization", tt.authorization)
+
+			if err := NewAuthorizer(tt.tokens).Authorize(ctx); (err != nil) != tt.wantErr {
+				t.Errorf("Authorize() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestAuthorizer_Identity(t *testing.T) {
+	authorizer := NewAuthorizer([]string{"one"})
+	identity := func(authorization string, headers ...string) string {
+		ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
+		ctx.Request = httptest.NewRequest(http.MethodPost, "/host/1.1.1.1", nil)
+		ctx.Request.RemoteAddr = "10.0.0.9:4242"
+		ctx.Request.Header.Set("Authorization", authorization)
+		for _, header := range headers {
+			ctx.Request.Header.Set(header, "10.9.9.9")
+		}
+		return authorizer.Identity(ctx)
+	}
+
+	if got := identity("Bearer one"); got != "token-7692c3ad" {
+		t.Errorf("Identity() = %s, want the hash of the token", got)
+	}
+	if got := identity("Bearer made-up"); got != "ip-10.0.0.9" {
+		t.Errorf("Identity() = %s, want the address for an invalid token", got)
+	}
+	if got := identity("", "X-Forwarded-For", "X-Real-IP"); got != "ip-10.0.0.9" {
+		t.Errorf("Identity() = %s, want the address of the peer whatever the forwarding headers", got)
+	}
+}
@@ -1,17 +1,21 @@
 package routes
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 
 	"github.com/bmc-toolbox/actor/internal/actions"
+	"github.com/bmc-toolbox/actor/internal/webhook"
 	"github.com/gin-gonic/gin"
 	"github.com/sirupsen/logrus"
 )
 
 type (
 	baseAPI struct {
-		planMaker *actions.PlanMaker
+		planMaker  *actions.PlanMaker
+		notifier   *webhook.Notifier
+		authorizer *Authorizer
 	}
 )
 
@@ -40,6 +44,17 @@ func (ba baseAPI) powerStatus(ctx *gin.Context, params map[string]interface{}, l
 	ctx.JSON(http.StatusOK, response)
 }
 
+func (ba baseAPI) capabilities(ctx *gin.Context, params map[string]interface{}, logger *logrus.Entry) {
+	capabilities, err := ba.planMaker.Capabilities(params)
+	if err != nil {
+		logger.WithError(err).Error("failed to collect capabilities")
+		ctx.JSON(http.StatusPreconditionFailed, newErrorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newCapabilitiesResponse(capabilities))
+}
+
 func (ba baseAPI) executeActions(ctx *gin.Context, params map[string]interface{}, logger *logrus.Entry) {
 	req, err := unmarshalRequest(ctx)
 	if err != nil {
@@ -48,9 +63,16 @@ func (ba baseAPI) executeActions(ctx *gin.Context, params map[string]interface{}
 		return
 	}
 
-	plan, err := ba.planMaker.MakePlan(req.ActionSequence, params)
+	plan, err := ba.makeNotifyingPlan(ctx, req.ActionSequence, params, req.Webhook)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, newErrorResponse(err))
+		ctx.JSON(planErrorStatus(err), newErrorResponse(err))
+		return
+	}
+
+	if isStreamRequested(ctx) {
+		streamPlan(ctx, plan, func(results []actions.ActionResult, _ error) interface{} {
+			return actionResultsToResponses(results)
+		})
 		return
 	}
 
@@ -64,3 +86,103 @@ func (ba baseAPI) executeActions(ctx *gin.Context, params map[string]interface{}
 
 	ctx.JSON(http.StatusOK, responses)
 }
+
+func (ba baseAPI) executeWorkflow(ctx *gin.Context, params map[string]interface{}, logger *logrus.Entry) {
+	name := ctx.Param("name")
+	if err := validateWorkflowName(name); err != nil {
+		logger.Warn(err)
+		ctx.JSON(http.StatusBadRequest, newErrorResponse(err))
+		return
+	}
+
+	req, err := unmarshalWorkflowRequest(ctx)
+	if err != nil {
+		logger.WithError(err).Error("failed to unmarshal request")
+		ctx.JSON(http.StatusBadRequest, newErrorResponse(fmt.Errorf("failed to unmarshal request: %w", err)))
+		return
+	}
+
+	plan, err := ba.makeNotifyingPlan(ctx, []string{actions.WorkflowAction(name, req.Params)}, params, req.Webhook)
+	if err != nil {
+		ctx.JSON(planErrorStatus(err), newErrorResponse(err))
+		return
+	}
+
+	if isStreamRequested(ctx) {
+		streamPlan(ctx, plan, func(results []actions.ActionResult, err error) interface{} {
+			return workflowResponse{Workflow: name, Status: err == nil, Steps: actionResultsToResponses(results)}
+		})
+		return
+	}
+
+	results, err := plan.Run()
+	resp := workflowResponse{Workflow: name, Status: err == nil, Steps: actionResultsToResponses(results)}
+
+	if err != nil {
+		ctx.JSON(http.StatusExpectationFailed, resp)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, resp)
+}
+
+// makeNotifyingPlan makes a plan which reports its outcome to the configured webhooks and to the one of the request,
+// plans with privileged actions are only made for authorized requests
+func (ba baseAPI) makeNotifyingPlan(ctx *gin.Context, actionSequence []string, params map[string]interface{}, url string) (*actions.ExecutionPlan, error) {
+	urls := make([]string, 0, 1)
+	if url != "" {
+		if err := ba.notifier.ValidateURL(url); err != nil {
+			return nil, err
+		}
+		urls = append(urls, url)
+	}
+
+	plan, err := ba.planMaker.MakePlan(actionSequence, params)
+	if err != nil {
+		return nil, err
+	}
+
+	if plan.IsPrivileged() {
+		if err := ba.authorizer.Authorize(ctx); err != nil {
+			plan.Cleanup()
+			return nil, err
+		}
+	}
+
+	plan.OnFinish(ba.notifier.Observer(urls...))
+	plan.As(ba.authorizer.Identity(ctx))
+
+	return plan, nil
+}
+
+// streamPlan runs the plan sending Server-Sent Events as every step starts and finishes,
+// the last event is "done" or "failed" with the data built by finalResponse
+func streamPlan(ctx *gin.Context, plan *actions.ExecutionPlan, finalResponse func([]actions.ActionResult, error) interface{}) {
+	ctx.Header("Cache-Control", "no-cache")
+	ctx.Header("X-Accel-Buffering", "no")
+
+	plan.OnStep(func(event actions.StepEvent) {
+		name := sseEventStepStarted
+		if event.Result != nil {
+			name = sseEventStepFinished
+		}
+		ctx.SSEvent(name, newStepResponse(event))
+		ctx.Writer.Flush()
+	})
+
+	results, err := plan.Run()
+
+	name := sseEventDone
+	if err != nil {
+		name = sseEventFailed
+	}
+	ctx.SSEvent(name, finalResponse(results, err))
+	ctx.Writer.Flush()
+}
+
+func planErrorStatus(err error) int {
+	if errors.Is(err, ErrUnauthorized) {
+		return http.StatusForbidden
+	}
+	return http.StatusBadRequest
+}
@@ -0,0 +1,50 @@
+package routes
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/bmc-toolbox/actor/internal/providers"
+	"github.com/gin-gonic/gin"
+	log "github.com/sirupsen/logrus"
+)
+
+type (
+	// BladeAPI locates blades without knowing their chassis
+	BladeAPI struct {
+		locator bladeLocator
+	}
+
+	bladeLocator interface {
+		Locate(serial string, refresh bool) (providers.BladeLocation, error)
+	}
+)
+
+func NewBladeAPI(locator bladeLocator) *BladeAPI {
+	return &BladeAPI{locator: locator}
+}
+
+// LocateBlade returns the chassis and the position of a blade given its serial, ?refresh=true scans the chassis again
+func (ba BladeAPI) LocateBlade(ctx *gin.Context) {
+	logger := log.WithField("method", "LocateBlade")
+
+	serial := ctx.Param("serial")
+	if err := validateBladeSerial(serial); err != nil {
+		invalid(ctx, logger, "bmc", err)
+		return
+	}
+	logger = logger.WithField("serial", serial)
+
+	location, err := ba.locator.Locate(serial, ctx.Query("refresh") == "true")
+	if err != nil {
+		logger.Warn(err)
+		status := http.StatusPreconditionFailed
+		if errors.Is(err, providers.ErrBladeNotFound) {
+			status = http.StatusNotFound
+		}
+		ctx.JSON(status, newErrorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, location)
+}
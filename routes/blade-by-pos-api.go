@@ -1,11 +1,10 @@
 package routes
 
 import (
-	"net/http"
 	"strconv"
 
 	"github.com/bmc-toolbox/actor/internal/actions"
-	metrics "github.com/bmc-toolbox/gin-go-metrics"
+	"github.com/bmc-toolbox/actor/internal/webhook"
 	"github.com/gin-gonic/gin"
 	log "github.com/sirupsen/logrus"
 )
@@ -16,8 +15,8 @@ type (
 	}
 )
 
-func NewBladeByPosAPI(planMaker *actions.PlanMaker) *BladeByPosAPI {
-	return &BladeByPosAPI{baseAPI{planMaker: planMaker}}
+func NewBladeByPosAPI(planMaker *actions.PlanMaker, notifier *webhook.Notifier, authorizer *Authorizer) *BladeByPosAPI {
+	return &BladeByPosAPI{baseAPI{planMaker: planMaker, notifier: notifier, authorizer: authorizer}}
 }
 
 // ChassisBladePowerStatusByPosition checks the current power status of a blade in a given chassis
@@ -26,15 +25,43 @@ func (ba BladeByPosAPI) ChassisBladePowerStatusByPosition(ctx *gin.Context) {
 
 	host, bladePos, err := ba.getAndValidateParams(ctx)
 	if err != nil {
-		logger.Warn(err)
-		metrics.IncrCounter([]string{"errors", "cmc", "user_request_invalid"}, 1)
-		ctx.JSON(http.StatusBadRequest, newErrorResponse(err))
+		invalid(ctx, logger, "cmc", err)
 		return
 	}
 
 	logger = log.WithField("ip", host).WithField("pos", bladePos)
 
-	ba.powerStatus(ctx, map[string]interface{}{"host": host, "bladePos": bladePos}, logger)
+	ba.powerStatus(ctx, map[string]interface{}{actions.ParamHost: host, actions.ParamBladePosition: bladePos}, logger)
+}
+
+// ChassisBladeCapabilitiesByPosition reports the actions supported by a blade in a given chassis
+func (ba BladeByPosAPI) ChassisBladeCapabilitiesByPosition(ctx *gin.Context) {
+	logger := log.WithField("method", "ChassisBladeCapabilitiesByPosition")
+
+	host, bladePos, err := ba.getAndValidateParams(ctx)
+	if err != nil {
+		invalid(ctx, logger, "cmc", err)
+		return
+	}
+
+	logger = log.WithField("ip", host).WithField("pos", bladePos)
+
+	ba.capabilities(ctx, map[string]interface{}{actions.ParamHost: host, actions.ParamBladePosition: bladePos}, logger)
+}
+
+// ChassisBladeExecuteWorkflowByPosition carries out the execution of a named workflow for a blade in a given chassis
+func (ba BladeByPosAPI) ChassisBladeExecuteWorkflowByPosition(ctx *gin.Context) {
+	logger := log.WithField("method", "ChassisBladeExecuteWorkflowByPosition")
+
+	host, bladePos, err := ba.getAndValidateParams(ctx)
+	if err != nil {
+		invalid(ctx, logger, "cmc", err)
+		return
+	}
+
+	logger = log.WithField("ip", host).WithField("pos", bladePos)
+
+	ba.executeWorkflow(ctx, map[string]interface{}{actions.ParamHost: host, actions.ParamBladePosition: bladePos}, logger)
 }
 
 // ChassisBladeExecuteActionsByPosition carries out the execution of the requested action-list for a blade in a given chassis
@@ -43,15 +70,13 @@ func (ba BladeByPosAPI) ChassisBladeExecuteActionsByPosition(ctx *gin.Context) {
 
 	host, bladePos, err := ba.getAndValidateParams(ctx)
 	if err != nil {
-		logger.Warn(err)
-		metrics.IncrCounter([]string{"errors", "cmc", "user_request_invalid"}, 1)
-		ctx.JSON(http.StatusBadRequest, newErrorResponse(err))
+		invalid(ctx, logger, "cmc", err)
 		return
 	}
 
 	logger = log.WithField("ip", host).WithField("pos", bladePos)
 
-	ba.executeActions(ctx, map[string]interface{}{"host": host, "bladePos": bladePos}, logger)
+	ba.executeActions(ctx, map[string]interface{}{actions.ParamHost: host, actions.ParamBladePosition: bladePos}, logger)
 }
 
 func (ba BladeByPosAPI) getAndValidateParams(ctx *gin.Context) (string, int, error) {
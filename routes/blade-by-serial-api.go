@@ -1,10 +1,8 @@
 package routes
 
 import (
-	"net/http"
-
 	"github.com/bmc-toolbox/actor/internal/actions"
-	metrics "github.com/bmc-toolbox/gin-go-metrics"
+	"github.com/bmc-toolbox/actor/internal/webhook"
 	"github.com/gin-gonic/gin"
 	log "github.com/sirupsen/logrus"
 )
@@ -15,8 +13,8 @@ type (
 	}
 )
 
-func NewBladeBySerialAPI(planMaker *actions.PlanMaker) *BladeBySerialAPI {
-	return &BladeBySerialAPI{baseAPI{planMaker: planMaker}}
+func NewBladeBySerialAPI(planMaker *actions.PlanMaker, notifier *webhook.Notifier, authorizer *Authorizer) *BladeBySerialAPI {
+	return &BladeBySerialAPI{baseAPI{planMaker: planMaker, notifier: notifier, authorizer: authorizer}}
 }
 
 // ChassisBladePowerStatusBySerial checks the current power status of a blade in a given chassis
@@ -25,15 +23,43 @@ func (ba BladeBySerialAPI) ChassisBladePowerStatusBySerial(ctx *gin.Context) {
 
 	host, bladeSerial, err := ba.getAndValidateParams(ctx)
 	if err != nil {
-		logger.Warn(err)
-		metrics.IncrCounter([]string{"errors", "cmc", "user_request_invalid"}, 1)
-		ctx.JSON(http.StatusBadRequest, newErrorResponse(err))
+		invalid(ctx, logger, "cmc", err)
+		return
+	}
+
+	logger = log.WithField("ip", host).WithField("serial", bladeSerial)
+
+	ba.powerStatus(ctx, map[string]interface{}{actions.ParamHost: host, actions.ParamBladeSerial: bladeSerial}, logger)
+}
+
+// ChassisBladeCapabilitiesBySerial reports the actions supported by a blade in a given chassis
+func (ba BladeBySerialAPI) ChassisBladeCapabilitiesBySerial(ctx *gin.Context) {
+	logger := log.WithField("method", "ChassisBladeCapabilitiesBySerial")
+
+	host, bladeSerial, err := ba.getAndValidateParams(ctx)
+	if err != nil {
+		invalid(ctx, logger, "cmc", err)
+		return
+	}
+
+	logger = log.WithField("ip", host).WithField("serial", bladeSerial)
+
+	ba.capabilities(ctx, map[string]interface{}{actions.ParamHost: host, actions.ParamBladeSerial: bladeSerial}, logger)
+}
+
+// ChassisBladeExecuteWorkflowBySerial carries out the execution of a named workflow for a blade in a given chassis
+func (ba BladeBySerialAPI) ChassisBladeExecuteWorkflowBySerial(ctx *gin.Context) {
+	logger := log.WithField("method", "ChassisBladeExecuteWorkflowBySerial")
+
+	host, bladeSerial, err := ba.getAndValidateParams(ctx)
+	if err != nil {
+		invalid(ctx, logger, "cmc", err)
 		return
 	}
 
 	logger = log.WithField("ip", host).WithField("serial", bladeSerial)
 
-	ba.powerStatus(ctx, map[string]interface{}{"host": host, "bladeSerial": bladeSerial}, logger)
+	ba.executeWorkflow(ctx, map[string]interface{}{actions.ParamHost: host, actions.ParamBladeSerial: bladeSerial}, logger)
 }
 
 // ChassisBladeExecuteActionsBySerial carries out the execution of the requested action-list for a blade in a given chassis
@@ -42,15 +68,13 @@ func (ba BladeBySerialAPI) ChassisBladeExecuteActionsBySerial(ctx *gin.Context)
 
 	host, bladeSerial, err := ba.getAndValidateParams(ctx)
 	if err != nil {
-		logger.Warn(err)
-		metrics.IncrCounter([]string{"errors", "cmc", "user_request_invalid"}, 1)
-		ctx.JSON(http.StatusBadRequest, newErrorResponse(err))
+		invalid(ctx, logger, "cmc", err)
 		return
 	}
 
 	logger = log.WithField("ip", host).WithField("serial", bladeSerial)
 
-	ba.executeActions(ctx, map[string]interface{}{"host": host, "bladeSerial": bladeSerial}, logger)
+	ba.executeActions(ctx, map[string]interface{}{actions.ParamHost: host, actions.ParamBladeSerial: bladeSerial}, logger)
 }
 
 func (ba BladeBySerialAPI) getAndValidateParams(ctx *gin.Context) (string, string, error) {
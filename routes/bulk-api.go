@@ -0,0 +1,141 @@
+package routes
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/bmc-toolbox/actor/internal/actions"
+	"github.com/bmc-toolbox/actor/internal/bulk"
+	"github.com/bmc-toolbox/actor/internal/registry"
+	"github.com/bmc-toolbox/actor/internal/webhook"
+	"github.com/gin-gonic/gin"
+	log "github.com/sirupsen/logrus"
+)
+
+type (
+	// BulkAPI carries out action sequences on the registered targets matching label selectors
+	BulkAPI struct {
+		registry    *registry.Registry
+		planMakers  *actions.PlanMakers
+		notifier    *webhook.Notifier
+		authorizer  *Authorizer
+		runner      *bulk.Runner
+		concurrency int
+	}
+)
+
+func NewBulkAPI(registry *registry.Registry, planMakers *actions.PlanMakers, notifier *webhook.Notifier, authorizer *Authorizer, concurrency int) *BulkAPI {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	return &BulkAPI{registry: registry, planMakers: planMakers, notifier: notifier, authorizer: authorizer, runner: bulk.NewRunner(), concurrency: concurrency}
+}
+
+// ExecuteActions starts carrying out the requested action-list on every registered target matching the selector
+// in the background, only authorized clients may run it, a dry-run is open to everyone
+func (ba BulkAPI) ExecuteActions(ctx *gin.Context) {
+	logger := log.WithField("method", "BulkExecuteActions")
+
+	req := &bulkRequest{}
+	if err := ctx.ShouldBindJSON(req); err != nil {
+		invalid(ctx, logger, "registry", fmt.Errorf("failed to unmarshal request: %w", err))
+		return
+	}
+
+	selector, err := registry.ParseSelector(req.Selector)
+	if err != nil {
+		invalid(ctx, logger, "registry", err)
+		return
+	}
+	if len(selector) == 0 {
+		invalid(ctx, logger, "registry", fmt.Errorf("no selector: an empty selector would address every target"))
+		return
+	}
+	if len(req.ActionSequence) == 0 {
+		invalid(ctx, logger, "registry", fmt.Errorf("no action-sequence"))
+		return
+	}
+	if req.Webhook != "" {
+		if err := ba.notifier.ValidateURL(req.Webhook); err != nil {
+			invalid(ctx, logger, "registry", err)
+			return
+		}
+	}
+
+	resp := bulkResponse{Selector: selector.String(), Targets: make([]string, 0), DryRun: req.DryRun}
+	targets := make([]actions.Target, 0)
+	for _, target := range ba.registry.List(selector) {
+		targets = append(targets, target.ActionTarget())
+		resp.Targets = append(resp.Targets, target.Host)
+	}
+	logger = logger.WithField("selector", resp.Selector).WithField("targets", len(targets))
+
+	job, err := bulk.NewJob(ba.planMakers, targets, req.ActionSequence)
+	if err != nil {
+		invalid(ctx, logger, "registry", err)
+		return
+	}
+
+	if req.DryRun {
+		job.Cleanup()
+		resp.Status = true
+		ctx.JSON(http.StatusOK, resp)
+		return
+	}
+
+	if len(targets) == 0 {
+		invalid(ctx, logger, "registry", fmt.Errorf("selector %q matches no target", resp.Selector))
+		return
+	}
+
+	// a single request reaches every matching target, so any action needs a token, not only the privileged ones
+	if err := ba.authorizer.Authorize(ctx); err != nil {
+		job.Cleanup()
+		logger.Warn(err)
+		ctx.JSON(http.StatusForbidden, newErrorResponse(err))
+		return
+	}
+
+	urls := make([]string, 0, 1)
+	if req.Webhook != "" {
+		urls = append(urls, req.Webhook)
+	}
+	job.OnFinish(ba.notifier.Observer(urls...))
+	job.As(ba.authorizer.Identity(ctx))
+
+	concurrency := ba.concurrency
+	if req.Concurrency > 0 && req.Concurrency < concurrency {
+		concurrency = req.Concurrency
+	}
+
+	run, err := ba.runner.Start(job, resp.Selector, req.ActionSequence, concurrency)
+	if err != nil {
+		logger.Error(err)
+		ctx.JSON(http.StatusInternalServerError, newErrorResponse(err))
+		return
+	}
+
+	logger.WithField("bulk", run.ID).Info("bulk run started")
+	ctx.JSON(http.StatusAccepted, run)
+}
+
+// ListRuns returns the recent bulk runs with their results
+func (ba BulkAPI) ListRuns(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, ba.runner.List())
+}
+
+// GetRun returns the progress of a given bulk run and, once it is finished, the results per target
+func (ba BulkAPI) GetRun(ctx *gin.Context) {
+	run, err := ba.runner.Get(ctx.Param("id"))
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, bulk.ErrNotFound) {
+			status = http.StatusNotFound
+		}
+		ctx.JSON(status, newErrorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, run)
+}
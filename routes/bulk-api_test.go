@@ -0,0 +1,172 @@
+package routes
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/bmc-toolbox/actor/internal/actions"
+	"github.com/bmc-toolbox/actor/internal/bulk"
+	"github.com/bmc-toolbox/actor/internal/registry"
+	"github.com/bmc-toolbox/actor/internal/webhook"
+	"github.com/gin-gonic/gin"
+)
+
+type (
+	testBulkExecutorFactory struct{}
+	testBulkExecutor        struct{}
+)
+
+func (testBulkExecutorFactory) New(_ map[string]interface{}) (actions.Executor, error) {
+	return testBulkExecutor{}, nil
+}
+
+func (testBulkExecutor) Validate(action string) error {
+	if action != actions.IsOn && action != actions.SelClear {
+		return fmt.Errorf("unknown action %q", action)
+	}
+	return nil
+}
+
+func (testBulkExecutor) Run(action string) actions.ActionResult {
+	return actions.NewActionResult(action, true, "ok", nil)
+}
+
+func (testBulkExecutor) Cleanup() {}
+
+func TestBulkAPI_ExecuteActions(t *testing.T) {
+	dir, err := ioutil.TempDir("", "registry")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	fleet, err := registry.New(filepath.Join(dir, "targets.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for host, rack := range map[string]string{"10.0.0.1": "r1", "10.0.0.2": "r2", "10.0.0.3": "r3"} {
+		if err := fleet.Put(registry.Target{Host: host, Labels: map[string]string{"site": "ams1", "rack": rack}}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	planMakers := &actions.PlanMakers{Host: actions.NewPlanMaker(testBulkExecutorFactory{})}
+	api := NewBulkAPI(fleet, planMakers, webhook.New(webhook.Config{}), newTestAuthorizer(), 2)
+
+	tests := []struct {
+		name          string
+		authorization string
+		body          string
+		wantCode      int
+		wantTargets   []string
+		wantResults   int
+	}{
+		{
+			name:        "Dry-run",
+			body:        `{"selector": "site=ams1,rack in (r1,r2)", "action-sequence": ["ison"], "dry-run": true}`,
+			wantCode:    http.StatusOK,
+			wantTargets: []string{"10.0.0.1", "10.0.0.2"},
+		},
+		{
+			name:          "Execute",
+			authorization: testAuthorization,
+			body:          `{"selector": "rack notin (r1)", "action-sequence": ["ison"], "concurrency": 5}`,
+			wantCode:      http.StatusAccepted,
+			wantTargets:   []string{"10.0.0.2", "10.0.0.3"},
+			wantResults:   2,
+		},
+		{
+			name:     "Without authorization",
+			body:     `{"selector": "rack notin (r1)", "action-sequence": ["ison"]}`,
+			wantCode: http.StatusForbidden,
+		},
+		{
+			name:          "Privileged",
+			authorization: testAuthorization,
+			body:          `{"selector": "rack=r1", "action-sequence": ["selclear"]}`,
+			wantCode:      http.StatusAccepted,
+			wantTargets:   []string{"10.0.0.1"},
+			wantResults:   1,
+		},
+		{
+			name:     "No selector",
+			body:     `{"action-sequence": ["ison"]}`,
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name:     "No target",
+			body:     `{"selector": "rack=r9", "action-sequence": ["ison"]}`,
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name:     "Unknown action",
+			body:     `{"selector": "rack=r1", "action-sequence": ["powerup"], "dry-run": true}`,
+			wantCode: http.StatusBadRequest,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := testRequest{
+				method:        http.MethodPost,
+				path:          "/targets/actions",
+				authorization: tt.authorization,
+				body:          strings.NewReader(tt.body),
+			}.serve(api.ExecuteActions)
+
+			if !checkResponse(t, "ExecuteActions", recorder, tt.wantCode) {
+				return
+			}
+
+			switch tt.wantCode {
+			case http.StatusOK:
+				resp := bulkResponse{}
+				if err := json.Unmarshal(recorder.Body.Bytes(), &resp); err != nil {
+					t.Fatal(err)
+				}
+				if !reflect.DeepEqual(resp.Targets, tt.wantTargets) {
+					t.Errorf("ExecuteActions() targets = %v, want %v", resp.Targets, tt.wantTargets)
+				}
+			case http.StatusAccepted:
+				run := bulk.Run{}
+				if err := json.Unmarshal(recorder.Body.Bytes(), &run); err != nil {
+					t.Fatal(err)
+				}
+				api.runner.Wait()
+
+				recorder = testRequest{
+					method: http.MethodGet,
+					path:   "/targets/actions/" + run.ID,
+					params: gin.Params{{Key: "id", Value: run.ID}},
+				}.serve(api.GetRun)
+				if !checkResponse(t, "GetRun", recorder, http.StatusOK) {
+					return
+				}
+
+				run = bulk.Run{}
+				if err := json.Unmarshal(recorder.Body.Bytes(), &run); err != nil {
+					t.Fatal(err)
+				}
+				if !reflect.DeepEqual(run.Targets, tt.wantTargets) {
+					t.Errorf("GetRun() targets = %v, want %v", run.Targets, tt.wantTargets)
+				}
+				if run.Status != bulk.StatusDone || len(run.Results) != tt.wantResults || run.Finished != tt.wantResults {
+					t.Errorf("GetRun() = %+v, want %d results", run, tt.wantResults)
+				}
+			}
+		})
+	}
+
+	recorder := testRequest{
+		method: http.MethodGet,
+		path:   "/targets/actions/unknown",
+		params: gin.Params{{Key: "id", Value: "unknown"}},
+	}.serve(api.GetRun)
+	checkResponse(t, "GetRun", recorder, http.StatusNotFound)
+}
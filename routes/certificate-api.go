@@ -0,0 +1,237 @@
+package routes
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/bmc-toolbox/actor/internal/actions"
+	"github.com/bmc-toolbox/actor/internal/certificates"
+	"github.com/bmc-toolbox/bmclib/cfgresources"
+	"github.com/gin-gonic/gin"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	certificateFormField = "certificate"
+	keyFormField         = "key"
+
+	// maxReportHosts bounds the hosts scanned by a single report
+	maxReportHosts = 5000
+)
+
+type (
+	// CertificateAPI reports the HTTPS certificates of BMCs and replaces them, changes require authorization
+	CertificateAPI struct {
+		manager       certificateManager
+		authorizer    *Authorizer
+		defaultWithin time.Duration
+		policy        HostPolicy
+	}
+
+	certificateManager interface {
+		Certificate(host string) (certificates.Info, error)
+		Report(hosts []string, within time.Duration) []certificates.ReportEntry
+		GenerateCSR(targetType, host string, attributes *cfgresources.HTTPSCertAttributes) ([]byte, error)
+		UploadCertificate(targetType, host string, cert, key []byte) (bool, error)
+	}
+
+	// uploadCertificateResponse tells whether the BMC resets to serve the new certificate
+	uploadCertificateResponse struct {
+		Reset bool `json:"reset"`
+	}
+)
+
+func NewCertificateAPI(manager certificateManager, authorizer *Authorizer, defaultWithin time.Duration) *CertificateAPI {
+	return &CertificateAPI{manager: manager, authorizer: authorizer, defaultWithin: defaultWithin}
+}
+
+// WithPolicy refuses reports on the hosts the policy doesn't allow
+func (ca *CertificateAPI) WithPolicy(policy HostPolicy) *CertificateAPI {
+	ca.policy = policy
+	return ca
+}
+
+// Certificate returns the certificate presented by the BMC of a given host or chassis
+func (ca CertificateAPI) Certificate(ctx *gin.Context) {
+	logger := log.WithField("method", "Certificate")
+
+	host := ctx.Param("host")
+	if err := validateHost(host); err != nil {
+		invalid(ctx, logger, "bmc", err)
+		return
+	}
+
+	info, err := ca.manager.Certificate(host)
+	if err != nil {
+		logger.WithField("ip", host).WithError(err).Error("failed to read certificate")
+		ctx.JSON(http.StatusPreconditionFailed, newErrorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, info)
+}
+
+// HostCSR has the BMC of a given host generate a CSR
+func (ca CertificateAPI) HostCSR(ctx *gin.Context) {
+	ca.csr(ctx, "HostCSR", actions.TargetHost)
+}
+
+// ChassisCSR has a given chassis generate a CSR
+func (ca CertificateAPI) ChassisCSR(ctx *gin.Context) {
+	ca.csr(ctx, "ChassisCSR", actions.TargetChassis)
+}
+
+// UploadHostCertificate replaces the certificate of the BMC of a given host
+func (ca CertificateAPI) UploadHostCertificate(ctx *gin.Context) {
+	ca.upload(ctx, "UploadHostCertificate", actions.TargetHost)
+}
+
+// UploadChassisCertificate replaces the certificate of a given chassis
+func (ca CertificateAPI) UploadChassisCertificate(ctx *gin.Context) {
+	ca.upload(ctx, "UploadChassisCertificate", actions.TargetChassis)
+}
+
+// Report reads the certificates of the given hosts in parallel, sorted by expiry, only authorized clients
+// may have actor connect to that many addresses
+func (ca CertificateAPI) Report(ctx *gin.Context) {
+	logger := log.WithField("method", "CertificateReport")
+
+	if !ca.authorize(ctx, logger) {
+		return
+	}
+
+	req := &certificateReportRequest{}
+	if err := ctx.ShouldBindJSON(req); err != nil {
+		invalid(ctx, logger, "bmc", fmt.Errorf("failed to unmarshal request: %w", err))
+		return
+	}
+
+	if len(req.Hosts) == 0 || len(req.Hosts) > maxReportHosts {
+		invalid(ctx, logger, "bmc", fmt.Errorf("between 1 and %d hosts are expected", maxReportHosts))
+		return
+	}
+	for _, host := range req.Hosts {
+		if err := validateHost(host); err != nil {
+			invalid(ctx, logger, "bmc", err)
+			return
+		}
+	}
+	if !allowHosts(ctx, logger, ca.policy, req.Hosts...) {
+		return
+	}
+
+	within := ca.defaultWithin
+	if req.Within != "" {
+		var err error
+		if within, err = time.ParseDuration(req.Within); err != nil {
+			invalid(ctx, logger, "bmc", fmt.Errorf("invalid within %q: %w", req.Within, err))
+			return
+		}
+	}
+
+	ctx.JSON(http.StatusOK, ca.manager.Report(req.Hosts, within))
+}
+
+func (ca CertificateAPI) csr(ctx *gin.Context, method, targetType string) {
+	logger := log.WithField("method", method)
+
+	if !ca.authorize(ctx, logger) {
+		return
+	}
+
+	host := ctx.Param("host")
+	if err := validateHost(host); err != nil {
+		invalid(ctx, logger, "bmc", err)
+		return
+	}
+	logger = logger.WithField("ip", host)
+
+	req := &csrRequest{}
+	if err := ctx.ShouldBindJSON(req); err != nil {
+		invalid(ctx, logger, "bmc", fmt.Errorf("failed to unmarshal request: %w", err))
+		return
+	}
+	attributes, err := req.attributes()
+	if err != nil {
+		invalid(ctx, logger, "bmc", err)
+		return
+	}
+
+	csr, err := ca.manager.GenerateCSR(targetType, host, attributes)
+	if err != nil {
+		logger.WithError(err).Error("failed to generate CSR")
+		ctx.JSON(http.StatusPreconditionFailed, newErrorResponse(err))
+		return
+	}
+
+	logger.Info("CSR generated")
+	ctx.Data(http.StatusOK, "application/pkcs10", csr)
+}
+
+func (ca CertificateAPI) upload(ctx *gin.Context, method, targetType string) {
+	logger := log.WithField("method", method)
+
+	if !ca.authorize(ctx, logger) {
+		return
+	}
+
+	host := ctx.Param("host")
+	if err := validateHost(host); err != nil {
+		invalid(ctx, logger, "bmc", err)
+		return
+	}
+	logger = logger.WithField("ip", host)
+
+	cert, err := formFile(ctx, certificateFormField)
+	if err != nil {
+		invalid(ctx, logger, "bmc", err)
+		return
+	}
+	key, err := formFile(ctx, keyFormField)
+	if err != nil {
+		invalid(ctx, logger, "bmc", err)
+		return
+	}
+
+	if err := certificates.CheckKeyPair(cert, key); err != nil {
+		invalid(ctx, logger, "bmc", err)
+		return
+	}
+
+	reset, err := ca.manager.UploadCertificate(targetType, host, cert, key)
+	if err != nil {
+		logger.WithError(err).Error("failed to upload certificate")
+		ctx.JSON(http.StatusPreconditionFailed, newErrorResponse(err))
+		return
+	}
+
+	logger.Info("certificate uploaded")
+	ctx.JSON(http.StatusOK, uploadCertificateResponse{Reset: reset})
+}
+
+// authorize refuses the request with 403 unless it carries a valid token
+func (ca CertificateAPI) authorize(ctx *gin.Context, logger *log.Entry) bool {
+	if err := ca.authorizer.Authorize(ctx); err != nil {
+		logger.Warn(err)
+		ctx.JSON(http.StatusForbidden, newErrorResponse(err))
+		return false
+	}
+	return true
+}
+
+func formFile(ctx *gin.Context, field string) ([]byte, error) {
+	header, err := ctx.FormFile(field)
+	if err != nil {
+		return nil, fmt.Errorf("no %q form file: %w", field, err)
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = file.Close() }()
+
+	return ioutil.ReadAll(file)
+}
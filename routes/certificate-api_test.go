@@ -0,0 +1,161 @@
+package routes
+
+import (
+	"bytes"
+	"mime/multipart"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bmc-toolbox/actor/internal/certificates"
+	"github.com/bmc-toolbox/actor/internal/certificates/certtest"
+	"github.com/bmc-toolbox/actor/internal/registry"
+)
+
+type testCertificateManager struct {
+	certificateManager
+	uploaded []string
+	within   time.Duration
+}
+
+func (m *testCertificateManager) UploadCertificate(targetType, host string, cert, key []byte) (bool, error) {
+	m.uploaded = append(m.uploaded, targetType+" "+host)
+	return true, nil
+}
+
+func (m *testCertificateManager) Report(hosts []string, within time.Duration) []certificates.ReportEntry {
+	m.within = within
+	return []certificates.ReportEntry{}
+}
+
+func TestCertificateAPI_UploadHostCertificate(t *testing.T) {
+	cert, key := certtest.NewPair(t, time.Now().Add(time.Hour))
+	_, otherKey := certtest.NewPair(t, time.Now().Add(time.Hour))
+
+	tests := []struct {
+		name          string
+		authorization string
+		key           []byte
+		wantCode      int
+		wantUploaded  string
+	}{
+		{
+			name:          "Uploaded",
+			authorization: testAuthorization,
+			key:           key,
+			wantCode:      http.StatusOK,
+			wantUploaded:  "host 1.1.1.1",
+		},
+		{
+			name:     "Unauthorized",
+			key:      key,
+			wantCode: http.StatusForbidden,
+		},
+		{
+			name:          "Mismatching key",
+			authorization: testAuthorization,
+			key:           otherKey,
+			wantCode:      http.StatusBadRequest,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body := &bytes.Buffer{}
+			form := multipart.NewWriter(body)
+			for field, content := range map[string][]byte{"certificate": cert, "key": tt.key} {
+				part, err := form.CreateFormFile(field, field+".pem")
+				if err != nil {
+					t.Fatal(err)
+				}
+				_, _ = part.Write(content)
+			}
+			_ = form.Close()
+
+			manager := &testCertificateManager{}
+			recorder := testRequest{
+				method:        http.MethodPut,
+				path:          "/host/1.1.1.1/certificate",
+				authorization: tt.authorization,
+				contentType:   form.FormDataContentType(),
+				body:          body,
+				params:        hostParams(),
+			}.serve(NewCertificateAPI(manager, newTestAuthorizer(), time.Hour).UploadHostCertificate)
+
+			checkResponse(t, "UploadHostCertificate", recorder, tt.wantCode)
+			checkCalls(t, "UploadHostCertificate", manager.uploaded, tt.wantUploaded)
+		})
+	}
+}
+
+func TestCertificateAPI_Report(t *testing.T) {
+	tests := []struct {
+		name          string
+		authorization string
+		body          string
+		wantCode      int
+		wantWithin    time.Duration
+	}{
+		{name: "Default within", authorization: testAuthorization, body: `{"hosts":["1.1.1.1"]}`, wantCode: http.StatusOK, wantWithin: time.Hour},
+		{name: "Unauthorized", body: `{"hosts":["1.1.1.1"]}`, wantCode: http.StatusForbidden},
+		{name: "Within", authorization: testAuthorization, body: `{"hosts":["1.1.1.1"],"within":"48h"}`, wantCode: http.StatusOK, wantWithin: 48 * time.Hour},
+		{name: "No hosts", authorization: testAuthorization, body: `{"hosts":[]}`, wantCode: http.StatusBadRequest},
+		{name: "Empty host", authorization: testAuthorization, body: `{"hosts":["1.1.1.1",""]}`, wantCode: http.StatusBadRequest},
+		{name: "Invalid within", authorization: testAuthorization, body: `{"hosts":["1.1.1.1"],"within":"soon"}`, wantCode: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			manager := &testCertificateManager{}
+			recorder := testRequest{
+				method:        http.MethodPost,
+				path:          "/certificates/report",
+				authorization: tt.authorization,
+				body:          strings.NewReader(tt.body),
+			}.serve(NewCertificateAPI(manager, newTestAuthorizer(), time.Hour).Report)
+
+			checkResponse(t, "Report", recorder, tt.wantCode)
+			if manager.within != tt.wantWithin {
+				t.Errorf("Report() within = %v, want %v", manager.within, tt.wantWithin)
+			}
+		})
+	}
+}
+
+func TestCertificateAPI_ReportPolicy(t *testing.T) {
+	fleet, err := registry.New(filepath.Join(t.TempDir(), "targets.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := fleet.Put(registry.Target{Host: "1.1.1.1"}); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name               string
+		rejectUnregistered bool
+		body               string
+		wantCode           int
+	}{
+		{name: "Registered", rejectUnregistered: true, body: `{"hosts":["1.1.1.1"]}`, wantCode: http.StatusOK},
+		{name: "Unregistered", rejectUnregistered: true, body: `{"hosts":["1.1.1.1","2.2.2.2"]}`, wantCode: http.StatusForbidden},
+		{name: "Not enforced", body: `{"hosts":["2.2.2.2"]}`, wantCode: http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			manager := &testCertificateManager{}
+			policy := NewRegistryAPI(fleet, newTestAuthorizer(), tt.rejectUnregistered)
+			recorder := testRequest{
+				method:        http.MethodPost,
+				path:          "/certificates/report",
+				authorization: testAuthorization,
+				body:          strings.NewReader(tt.body),
+			}.serve(NewCertificateAPI(manager, newTestAuthorizer(), time.Hour).WithPolicy(policy).Report)
+
+			checkResponse(t, "Report", recorder, tt.wantCode)
+			if reported := manager.within != 0; reported != (tt.wantCode == http.StatusOK) {
+				t.Errorf("Report() reported = %v with a %d response", reported, recorder.Code)
+			}
+		})
+	}
+}
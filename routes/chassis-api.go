@@ -1,10 +1,8 @@
 package routes
 
 import (
-	"net/http"
-
 	"github.com/bmc-toolbox/actor/internal/actions"
-	metrics "github.com/bmc-toolbox/gin-go-metrics"
+	"github.com/bmc-toolbox/actor/internal/webhook"
 	"github.com/gin-gonic/gin"
 	log "github.com/sirupsen/logrus"
 )
@@ -15,8 +13,8 @@ type (
 	}
 )
 
-func NewChassisAPI(planMaker *actions.PlanMaker) *ChassisAPI {
-	return &ChassisAPI{baseAPI{planMaker: planMaker}}
+func NewChassisAPI(planMaker *actions.PlanMaker, notifier *webhook.Notifier, authorizer *Authorizer) *ChassisAPI {
+	return &ChassisAPI{baseAPI{planMaker: planMaker, notifier: notifier, authorizer: authorizer}}
 }
 
 // ChassisPowerStatus checks the current power status of a given host
@@ -25,14 +23,40 @@ func (ca ChassisAPI) ChassisPowerStatus(ctx *gin.Context) {
 
 	host := ctx.Param("host")
 	if err := validateHost(host); err != nil {
-		logger.Warn(err)
-		metrics.IncrCounter([]string{"errors", "cmc", "user_request_invalid"}, 1)
-		ctx.JSON(http.StatusBadRequest, newErrorResponse(err))
+		invalid(ctx, logger, "cmc", err)
+		return
+	}
+	logger = log.WithField("ip", host)
+
+	ca.powerStatus(ctx, map[string]interface{}{actions.ParamHost: host}, logger)
+}
+
+// ChassisCapabilities reports the actions supported by a given chassis
+func (ca ChassisAPI) ChassisCapabilities(ctx *gin.Context) {
+	logger := log.WithField("method", "ChassisCapabilities")
+
+	host := ctx.Param("host")
+	if err := validateHost(host); err != nil {
+		invalid(ctx, logger, "cmc", err)
+		return
+	}
+	logger = log.WithField("ip", host)
+
+	ca.capabilities(ctx, map[string]interface{}{actions.ParamHost: host}, logger)
+}
+
+// ChassisExecuteWorkflow carries out the execution of a named workflow for a given chassis
+func (ca ChassisAPI) ChassisExecuteWorkflow(ctx *gin.Context) {
+	logger := log.WithField("method", "ChassisExecuteWorkflow")
+
+	host := ctx.Param("host")
+	if err := validateHost(host); err != nil {
+		invalid(ctx, logger, "cmc", err)
 		return
 	}
 	logger = log.WithField("ip", host)
 
-	ca.powerStatus(ctx, map[string]interface{}{"host": host}, logger)
+	ca.executeWorkflow(ctx, map[string]interface{}{actions.ParamHost: host}, logger)
 }
 
 // ChassisExecuteActions carries out the execution of the requested action-list for a given chassis
@@ -41,12 +65,10 @@ func (ca ChassisAPI) ChassisExecuteActions(ctx *gin.Context) {
 
 	host := ctx.Param("host")
 	if err := validateHost(host); err != nil {
-		logger.Warn(err)
-		metrics.IncrCounter([]string{"errors", "cmc", "user_request_invalid"}, 1)
-		ctx.JSON(http.StatusBadRequest, newErrorResponse(err))
+		invalid(ctx, logger, "cmc", err)
 		return
 	}
 	logger = log.WithField("ip", host)
 
-	ca.executeActions(ctx, map[string]interface{}{"host": host}, logger)
+	ca.executeActions(ctx, map[string]interface{}{actions.ParamHost: host}, logger)
 }
@@ -0,0 +1,115 @@
+package routes
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+
+	"github.com/bmc-toolbox/actor/internal/bmcconfig"
+	"github.com/bmc-toolbox/bmclib/cfgresources"
+	"github.com/gin-gonic/gin"
+	log "github.com/sirupsen/logrus"
+)
+
+type (
+	// ConfigAPI applies ResourcesConfig documents to BMCs, applying requires authorization while diffs don't
+	ConfigAPI struct {
+		manager    configManager
+		authorizer *Authorizer
+	}
+
+	configManager interface {
+		Profile(name string) (*cfgresources.ResourcesConfig, error)
+		ConfigureHost(host string, cfg *cfgresources.ResourcesConfig, diff bool) ([]bmcconfig.Result, error)
+		ConfigureChassis(host string, cfg *cfgresources.ResourcesConfig, diff bool) ([]bmcconfig.Result, error)
+	}
+)
+
+func NewConfigAPI(manager configManager, authorizer *Authorizer) *ConfigAPI {
+	return &ConfigAPI{manager: manager, authorizer: authorizer}
+}
+
+// ConfigureHost applies a configuration document or profile to the BMC of a given host
+func (ca ConfigAPI) ConfigureHost(ctx *gin.Context) {
+	ca.configure(ctx, "ConfigureHost", ca.manager.ConfigureHost)
+}
+
+// ConfigureChassis applies a configuration document or profile to a given chassis
+func (ca ConfigAPI) ConfigureChassis(ctx *gin.Context) {
+	ca.configure(ctx, "ConfigureChassis", ca.manager.ConfigureChassis)
+}
+
+func (ca ConfigAPI) configure(
+	ctx *gin.Context,
+	method string,
+	configure func(string, *cfgresources.ResourcesConfig, bool) ([]bmcconfig.Result, error),
+) {
+	logger := log.WithField("method", method)
+
+	host := ctx.Param("host")
+	if err := validateHost(host); err != nil {
+		invalid(ctx, logger, "bmc", err)
+		return
+	}
+	logger = logger.WithField("ip", host)
+
+	diff := false
+	if value := ctx.Query("diff"); value != "" {
+		var err error
+		if diff, err = strconv.ParseBool(value); err != nil {
+			invalid(ctx, logger, "bmc", fmt.Errorf("invalid diff %q", value))
+			return
+		}
+	}
+
+	if !diff {
+		if err := ca.authorizer.Authorize(ctx); err != nil {
+			logger.Warn(err)
+			ctx.JSON(http.StatusForbidden, newErrorResponse(err))
+			return
+		}
+	}
+
+	cfg, err := ca.document(ctx)
+	if err != nil {
+		if errors.Is(err, bmcconfig.ErrProfileNotFound) {
+			logger.Warn(err)
+			ctx.JSON(http.StatusNotFound, newErrorResponse(err))
+			return
+		}
+		invalid(ctx, logger, "bmc", err)
+		return
+	}
+
+	results, err := configure(host, cfg, diff)
+	if err != nil {
+		logger.WithError(err).Error("failed to configure BMC")
+		ctx.JSON(http.StatusPreconditionFailed, newErrorResponse(err))
+		return
+	}
+
+	if !diff {
+		logger.Info("BMC configuration applied")
+	}
+	ctx.JSON(http.StatusOK, results)
+}
+
+// document reads the profile named in the query or else the document in the body
+func (ca ConfigAPI) document(ctx *gin.Context) (*cfgresources.ResourcesConfig, error) {
+	if profile := ctx.Query("profile"); profile != "" {
+		return ca.manager.Profile(profile)
+	}
+
+	body, err := ioutil.ReadAll(ctx.Request.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read request: %w", err)
+	}
+
+	cfg, err := bmcconfig.Parse(body)
+	if err != nil {
+		return nil, fmt.Errorf("invalid configuration: %w", err)
+	}
+	return cfg, nil
+}
@@ -0,0 +1,93 @@
+package routes
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/bmc-toolbox/actor/internal/bmcconfig"
+	"github.com/bmc-toolbox/bmclib/cfgresources"
+)
+
+type testConfigManager struct {
+	configManager
+	configured []string
+}
+
+func (m *testConfigManager) Profile(name string) (*cfgresources.ResourcesConfig, error) {
+	if name != "ntp" {
+		return nil, fmt.Errorf("%w: %s", bmcconfig.ErrProfileNotFound, name)
+	}
+	return &cfgresources.ResourcesConfig{Ntp: &cfgresources.Ntp{Server1: "ntp.example.com"}}, nil
+}
+
+func (m *testConfigManager) ConfigureHost(host string, cfg *cfgresources.ResourcesConfig, diff bool) ([]bmcconfig.Result, error) {
+	m.configured = append(m.configured, fmt.Sprintf("%s ntp=%v syslog=%v diff=%v", host, cfg.Ntp != nil, cfg.Syslog != nil, diff))
+	return []bmcconfig.Result{}, nil
+}
+
+func TestConfigAPI_ConfigureHost(t *testing.T) {
+	tests := []struct {
+		name           string
+		query          string
+		authorization  string
+		body           string
+		wantCode       int
+		wantConfigured string
+	}{
+		{
+			name:           "Document",
+			authorization:  testAuthorization,
+			body:           `{"syslog": {"server": "syslog.example.com"}}`,
+			wantCode:       http.StatusOK,
+			wantConfigured: "1.1.1.1 ntp=false syslog=true diff=false",
+		},
+		{
+			name:           "Profile",
+			query:          "?profile=ntp",
+			authorization:  testAuthorization,
+			wantCode:       http.StatusOK,
+			wantConfigured: "1.1.1.1 ntp=true syslog=false diff=false",
+		},
+		{
+			name:     "Unauthorized",
+			body:     `{"syslog": {"server": "syslog.example.com"}}`,
+			wantCode: http.StatusForbidden,
+		},
+		{
+			name:           "Diff without authorization",
+			query:          "?diff=true",
+			body:           "syslog:\n  server: syslog.example.com\n",
+			wantCode:       http.StatusOK,
+			wantConfigured: "1.1.1.1 ntp=false syslog=true diff=true",
+		},
+		{
+			name:          "Unknown profile",
+			query:         "?profile=ldap",
+			authorization: testAuthorization,
+			wantCode:      http.StatusNotFound,
+		},
+		{
+			name:          "Unknown resource",
+			authorization: testAuthorization,
+			body:          `{"sylog": {"server": "syslog.example.com"}}`,
+			wantCode:      http.StatusBadRequest,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			manager := &testConfigManager{}
+			recorder := testRequest{
+				method:        http.MethodPost,
+				path:          "/host/1.1.1.1/config" + tt.query,
+				authorization: tt.authorization,
+				body:          strings.NewReader(tt.body),
+				params:        hostParams(),
+			}.serve(NewConfigAPI(manager, newTestAuthorizer()).ConfigureHost)
+
+			checkResponse(t, "ConfigureHost", recorder, tt.wantCode)
+			checkCalls(t, "ConfigureHost", manager.configured, tt.wantConfigured)
+		})
+	}
+}
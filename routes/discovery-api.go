@@ -0,0 +1,69 @@
+package routes
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/bmc-toolbox/actor/internal/discovery"
+	"github.com/gin-gonic/gin"
+	log "github.com/sirupsen/logrus"
+)
+
+type (
+	DiscoveryAPI struct {
+		scanner    *discovery.Scanner
+		authorizer *Authorizer
+	}
+)
+
+func NewDiscoveryAPI(scanner *discovery.Scanner, authorizer *Authorizer) *DiscoveryAPI {
+	return &DiscoveryAPI{scanner: scanner, authorizer: authorizer}
+}
+
+// CreateDiscovery starts scanning a CIDR range for BMCs, only authorized clients may scan since the scan tries
+// actor's credentials on every BMC found
+func (da DiscoveryAPI) CreateDiscovery(ctx *gin.Context) {
+	logger := log.WithField("method", "CreateDiscovery")
+
+	if err := da.authorizer.Authorize(ctx); err != nil {
+		logger.Warn(err)
+		ctx.JSON(http.StatusForbidden, newErrorResponse(err))
+		return
+	}
+
+	req := &discoveryRequest{}
+	if err := ctx.ShouldBindJSON(req); err != nil {
+		invalid(ctx, logger, "discovery", fmt.Errorf("failed to unmarshal request: %w", err))
+		return
+	}
+
+	job, err := da.scanner.Start(req.CIDR, req.Register, req.Labels)
+	if err != nil {
+		invalid(ctx, logger, "discovery", err)
+		return
+	}
+
+	logger.WithField("discovery", job.ID).WithField("cidr", job.CIDR).Info("BMC discovery started")
+	ctx.JSON(http.StatusAccepted, job)
+}
+
+// ListDiscoveries returns the recent discoveries with the BMCs they found
+func (da DiscoveryAPI) ListDiscoveries(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, da.scanner.List())
+}
+
+// GetDiscovery returns the progress of a given discovery and the BMCs found so far
+func (da DiscoveryAPI) GetDiscovery(ctx *gin.Context) {
+	job, err := da.scanner.Get(ctx.Param("id"))
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, discovery.ErrNotFound) {
+			status = http.StatusNotFound
+		}
+		ctx.JSON(status, newErrorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, job)
+}
@@ -0,0 +1,125 @@
+package routes
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/bmc-toolbox/actor/internal/firmware"
+	"github.com/gin-gonic/gin"
+	log "github.com/sirupsen/logrus"
+)
+
+const artifactFormField = "file"
+
+type (
+	FirmwareAPI struct {
+		reader     firmwareReader
+		artifacts  artifactStore
+		authorizer *Authorizer
+		maxUpload  int64
+	}
+
+	firmwareReader interface {
+		HostFirmware(host string) (firmware.Status, error)
+		ChassisFirmware(host string) (firmware.Status, error)
+	}
+
+	artifactStore interface {
+		Save(name string, r io.Reader) (firmware.Artifact, error)
+		List() ([]firmware.Artifact, error)
+	}
+)
+
+// NewFirmwareAPI refuses uploads larger than maxUpload bytes, there is no limit when it isn't positive
+func NewFirmwareAPI(reader firmwareReader, artifacts artifactStore, authorizer *Authorizer, maxUpload int64) *FirmwareAPI {
+	return &FirmwareAPI{reader: reader, artifacts: artifacts, authorizer: authorizer, maxUpload: maxUpload}
+}
+
+// HostFirmware returns the firmware version of a given host and its last update
+func (fa FirmwareAPI) HostFirmware(ctx *gin.Context) {
+	fa.firmware(ctx, "HostFirmware", fa.reader.HostFirmware)
+}
+
+// ChassisFirmware returns the firmware version of a given chassis and its last update
+func (fa FirmwareAPI) ChassisFirmware(ctx *gin.Context) {
+	fa.firmware(ctx, "ChassisFirmware", fa.reader.ChassisFirmware)
+}
+
+func (fa FirmwareAPI) firmware(ctx *gin.Context, method string, read func(host string) (firmware.Status, error)) {
+	logger := log.WithField("method", method)
+
+	host := ctx.Param("host")
+	if err := validateHost(host); err != nil {
+		invalid(ctx, logger, "bmc", err)
+		return
+	}
+	logger = log.WithField("ip", host)
+
+	status, err := read(host)
+	if err != nil {
+		logger.WithError(err).Error("failed to read firmware")
+		ctx.JSON(http.StatusPreconditionFailed, newErrorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, status)
+}
+
+// ListArtifacts returns the uploaded firmware artifacts
+func (fa FirmwareAPI) ListArtifacts(ctx *gin.Context) {
+	artifacts, err := fa.artifacts.List()
+	if err != nil {
+		log.WithField("method", "ListArtifacts").WithError(err).Error("failed to list firmware artifacts")
+		ctx.JSON(http.StatusInternalServerError, newErrorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, artifacts)
+}
+
+// UploadArtifact stores the firmware artifact sent as the "file" field of a multipart form, only authorized
+// clients may upload artifacts and the body can't exceed the upload limit
+func (fa FirmwareAPI) UploadArtifact(ctx *gin.Context) {
+	logger := log.WithField("method", "UploadArtifact")
+
+	if err := fa.authorizer.Authorize(ctx); err != nil {
+		logger.Warn(err)
+		ctx.JSON(http.StatusForbidden, newErrorResponse(err))
+		return
+	}
+
+	if fa.maxUpload > 0 {
+		if ctx.Request.ContentLength > fa.maxUpload {
+			err := fmt.Errorf("firmware artifact exceeds the upload limit of %d bytes", fa.maxUpload)
+			logger.Warn(err)
+			ctx.JSON(http.StatusRequestEntityTooLarge, newErrorResponse(err))
+			return
+		}
+		ctx.Request.Body = http.MaxBytesReader(ctx.Writer, ctx.Request.Body, fa.maxUpload)
+	}
+
+	header, err := ctx.FormFile(artifactFormField)
+	if err != nil {
+		invalid(ctx, logger, "bmc", fmt.Errorf("no %q form file: %w", artifactFormField, err))
+		return
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		logger.WithError(err).Error("failed to read firmware artifact")
+		ctx.JSON(http.StatusBadRequest, newErrorResponse(err))
+		return
+	}
+	defer func() { _ = file.Close() }()
+
+	artifact, err := fa.artifacts.Save(header.Filename, file)
+	if err != nil {
+		logger.WithError(err).Error("failed to store firmware artifact")
+		ctx.JSON(http.StatusBadRequest, newErrorResponse(err))
+		return
+	}
+
+	logger.WithField("artifact", artifact.Name).Info("firmware artifact uploaded")
+	ctx.JSON(http.StatusCreated, artifact)
+}
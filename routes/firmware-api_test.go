@@ -0,0 +1,93 @@
+package routes
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"mime/multipart"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/bmc-toolbox/actor/internal/firmware"
+)
+
+type testArtifactStore struct {
+	artifactStore
+	saved []string
+}
+
+func (s *testArtifactStore) Save(name string, r io.Reader) (firmware.Artifact, error) {
+	if _, err := ioutil.ReadAll(r); err != nil {
+		return firmware.Artifact{}, err
+	}
+	s.saved = append(s.saved, name)
+	return firmware.Artifact{Name: name}, nil
+}
+
+func TestFirmwareAPI_UploadArtifact(t *testing.T) {
+	tests := []struct {
+		name          string
+		authorization string
+		size          int
+		unknownLength bool
+		wantCode      int
+		wantSaved     string
+	}{
+		{
+			name:          "Uploaded",
+			authorization: testAuthorization,
+			size:          512,
+			wantCode:      http.StatusCreated,
+			wantSaved:     "idrac.bin",
+		},
+		{
+			name:     "Unauthorized",
+			size:     512,
+			wantCode: http.StatusForbidden,
+		},
+		{
+			name:          "Too large",
+			authorization: testAuthorization,
+			size:          4096,
+			wantCode:      http.StatusRequestEntityTooLarge,
+		},
+		{
+			name:          "Too large without a length",
+			authorization: testAuthorization,
+			size:          4096,
+			unknownLength: true,
+			wantCode:      http.StatusBadRequest,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body := &bytes.Buffer{}
+			form := multipart.NewWriter(body)
+			part, err := form.CreateFormFile(artifactFormField, "idrac.bin")
+			if err != nil {
+				t.Fatal(err)
+			}
+			_, _ = part.Write([]byte(strings.Repeat("f", tt.size)))
+			_ = form.Close()
+
+			// the length of a plain reader is unknown, as for a chunked request
+			var reader io.Reader = body
+			if tt.unknownLength {
+				reader = io.MultiReader(body)
+			}
+
+			store := &testArtifactStore{}
+			recorder := testRequest{
+				method:        http.MethodPost,
+				path:          "/firmware",
+				authorization: tt.authorization,
+				contentType:   form.FormDataContentType(),
+				body:          reader,
+			}.serve(NewFirmwareAPI(nil, store, newTestAuthorizer(), 2048).UploadArtifact)
+
+			checkResponse(t, "UploadArtifact", recorder, tt.wantCode)
+			checkCalls(t, "UploadArtifact", store.saved, tt.wantSaved)
+		})
+	}
+}
@@ -0,0 +1,72 @@
+package routes
+
+import (
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// testAuthorization is the header of the token newTestAuthorizer accepts
+const testAuthorization = "Bearer token"
+
+func newTestAuthorizer() *Authorizer {
+	return NewAuthorizer([]string{"token"})
+}
+
+// testRequest is served to a single handler, without a router, so the path params are given along
+type testRequest struct {
+	method        string
+	path          string
+	authorization string
+	contentType   string
+	body          io.Reader
+	params        gin.Params
+}
+
+// serve runs the handler on the request and returns the response it wrote
+func (r testRequest) serve(handler gin.HandlerFunc) *httptest.ResponseRecorder {
+	recorder := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(recorder)
+
+	ctx.Request = httptest.NewRequest(r.method, r.path, r.body)
+	if r.authorization != "" {
+		ctx.Request.Header.Set("Authorization", r.authorization)
+	}
+	if r.contentType != "" {
+		ctx.Request.Header.Set("Content-Type", r.contentType)
+	}
+	ctx.Params = r.params
+
+	handler(ctx)
+	ctx.Writer.WriteHeaderNow()
+
+	return recorder
+}
+
+// hostParams are the path params of the /host/1.1.1.1 routes
+func hostParams() gin.Params {
+	return gin.Params{{Key: "host", Value: "1.1.1.1"}}
+}
+
+// checkResponse reports a response code other than want, it tells whether the code matched
+func checkResponse(t *testing.T, method string, recorder *httptest.ResponseRecorder, want int) bool {
+	t.Helper()
+
+	if recorder.Code != want {
+		t.Errorf("%s() = %d %s, want %d", method, recorder.Code, recorder.Body.String(), want)
+		return false
+	}
+	return true
+}
+
+// checkCalls compares the calls recorded by a fake, joined by commas, with want
+func checkCalls(t *testing.T, method string, calls []string, want string) {
+	t.Helper()
+
+	if got := strings.Join(calls, ","); got != want {
+		t.Errorf("%s() called %q, want %q", method, got, want)
+	}
+}
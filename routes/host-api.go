@@ -1,10 +1,8 @@
 package routes
 
 import (
-	"net/http"
-
 	"github.com/bmc-toolbox/actor/internal/actions"
-	metrics "github.com/bmc-toolbox/gin-go-metrics"
+	"github.com/bmc-toolbox/actor/internal/webhook"
 	"github.com/gin-gonic/gin"
 	log "github.com/sirupsen/logrus"
 )
@@ -15,8 +13,8 @@ type (
 	}
 )
 
-func NewHostAPI(planMaker *actions.PlanMaker) *HostAPI {
-	return &HostAPI{baseAPI{planMaker: planMaker}}
+func NewHostAPI(planMaker *actions.PlanMaker, notifier *webhook.Notifier, authorizer *Authorizer) *HostAPI {
+	return &HostAPI{baseAPI{planMaker: planMaker, notifier: notifier, authorizer: authorizer}}
 }
 
 // HostPowerStatus checks the current power status of a given host
@@ -25,14 +23,40 @@ func (ha HostAPI) HostPowerStatus(ctx *gin.Context) {
 
 	host := ctx.Param("host")
 	if err := validateHost(host); err != nil {
-		logger.Warn(err)
-		metrics.IncrCounter([]string{"errors", "bmc", "user_request_invalid"}, 1)
-		ctx.JSON(http.StatusBadRequest, newErrorResponse(err))
+		invalid(ctx, logger, "bmc", err)
+		return
+	}
+	logger = log.WithField("ip", host)
+
+	ha.powerStatus(ctx, map[string]interface{}{actions.ParamHost: host}, logger)
+}
+
+// HostCapabilities reports the actions supported by a given host
+func (ha HostAPI) HostCapabilities(ctx *gin.Context) {
+	logger := log.WithField("method", "HostCapabilities")
+
+	host := ctx.Param("host")
+	if err := validateHost(host); err != nil {
+		invalid(ctx, logger, "bmc", err)
+		return
+	}
+	logger = log.WithField("ip", host)
+
+	ha.capabilities(ctx, map[string]interface{}{actions.ParamHost: host}, logger)
+}
+
+// HostExecuteWorkflow carries out the execution of a named workflow for a given host
+func (ha HostAPI) HostExecuteWorkflow(ctx *gin.Context) {
+	logger := log.WithField("method", "HostExecuteWorkflow")
+
+	host := ctx.Param("host")
+	if err := validateHost(host); err != nil {
+		invalid(ctx, logger, "bmc", err)
 		return
 	}
 	logger = log.WithField("ip", host)
 
-	ha.powerStatus(ctx, map[string]interface{}{"host": host}, logger)
+	ha.executeWorkflow(ctx, map[string]interface{}{actions.ParamHost: host}, logger)
 }
 
 // HostExecuteActions carries out the execution of the requested action-list for a given host
@@ -41,12 +65,10 @@ func (ha HostAPI) HostExecuteActions(ctx *gin.Context) {
 
 	host := ctx.Param("host")
 	if err := validateHost(host); err != nil {
-		logger.Warn(err)
-		metrics.IncrCounter([]string{"errors", "bmc", "user_request_invalid"}, 1)
-		ctx.JSON(http.StatusBadRequest, newErrorResponse(err))
+		invalid(ctx, logger, "bmc", err)
 		return
 	}
 	logger = log.WithFields(log.Fields{"ip": host})
 
-	ha.executeActions(ctx, map[string]interface{}{"host": host}, logger)
+	ha.executeActions(ctx, map[string]interface{}{actions.ParamHost: host}, logger)
 }
@@ -0,0 +1,153 @@
+package routes
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/bmc-toolbox/actor/internal/registry"
+	metrics "github.com/bmc-toolbox/gin-go-metrics"
+	"github.com/gin-gonic/gin"
+	log "github.com/sirupsen/logrus"
+)
+
+type (
+	// RegistryAPI manages the fleet registry, changes require authorization
+	RegistryAPI struct {
+		registry   *registry.Registry
+		authorizer *Authorizer
+		// rejectUnregistered refuses requests for hosts which aren't registered
+		rejectUnregistered bool
+	}
+
+	// HostPolicy refuses the hosts requests may not act on, the APIs taking hosts in their body apply it
+	// as Policy does for the :host path param
+	HostPolicy interface {
+		Allow(hosts ...string) error
+	}
+)
+
+func NewRegistryAPI(registry *registry.Registry, authorizer *Authorizer, rejectUnregistered bool) *RegistryAPI {
+	return &RegistryAPI{registry: registry, authorizer: authorizer, rejectUnregistered: rejectUnregistered}
+}
+
+// ListTargets returns the registered targets matching the label selector of the query, e.g. ?selector=rack=r12,role=compute
+func (ra RegistryAPI) ListTargets(ctx *gin.Context) {
+	logger := log.WithField("method", "ListTargets")
+
+	selector, err := registry.ParseSelector(ctx.Query("selector"))
+	if err != nil {
+		invalid(ctx, logger, "registry", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, ra.registry.List(selector))
+}
+
+func (ra RegistryAPI) GetTarget(ctx *gin.Context) {
+	target, err := ra.registry.Get(ctx.Param("target"))
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, newErrorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, target)
+}
+
+// PutTarget registers a target or replaces its registration
+func (ra RegistryAPI) PutTarget(ctx *gin.Context) {
+	logger := log.WithField("method", "PutTarget")
+
+	if err := ra.authorizer.Authorize(ctx); err != nil {
+		logger.Warn(err)
+		ctx.JSON(http.StatusForbidden, newErrorResponse(err))
+		return
+	}
+
+	target := registry.Target{}
+	if err := ctx.ShouldBindJSON(&target); err != nil {
+		invalid(ctx, logger, "registry", fmt.Errorf("failed to unmarshal request: %w", err))
+		return
+	}
+	target.Host = ctx.Param("target")
+
+	if err := target.Validate(); err != nil {
+		invalid(ctx, logger, "registry", err)
+		return
+	}
+
+	if err := ra.registry.Put(target); err != nil {
+		logger.WithError(err).Error("failed to register target")
+		ctx.JSON(http.StatusInternalServerError, newErrorResponse(err))
+		return
+	}
+
+	logger.WithField("ip", target.Host).Info("target registered")
+	ctx.JSON(http.StatusOK, target)
+}
+
+func (ra RegistryAPI) DeleteTarget(ctx *gin.Context) {
+	logger := log.WithField("method", "DeleteTarget")
+
+	if err := ra.authorizer.Authorize(ctx); err != nil {
+		logger.Warn(err)
+		ctx.JSON(http.StatusForbidden, newErrorResponse(err))
+		return
+	}
+
+	host := ctx.Param("target")
+	if err := ra.registry.Delete(host); err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, registry.ErrNotFound) {
+			status = http.StatusNotFound
+		}
+		ctx.JSON(status, newErrorResponse(err))
+		return
+	}
+
+	logger.WithField("ip", host).Info("target unregistered")
+	ctx.Status(http.StatusNoContent)
+}
+
+// Policy refuses requests naming an unregistered host in their path when the registry is enforced,
+// the registry routes name targets with :target so they are never refused
+func (ra RegistryAPI) Policy(ctx *gin.Context) {
+	host := ctx.Param("host")
+	if err := ra.Allow(host); err != nil {
+		refuseHost(ctx, log.WithField("path", ctx.FullPath()), err)
+		ctx.Abort()
+	}
+}
+
+// Allow refuses the hosts which aren't registered when unregistered hosts are rejected
+func (ra RegistryAPI) Allow(hosts ...string) error {
+	if !ra.rejectUnregistered {
+		return nil
+	}
+
+	for _, host := range hosts {
+		if host != "" && !ra.registry.IsRegistered(host) {
+			return fmt.Errorf("%w: %s", registry.ErrNotFound, host)
+		}
+	}
+	return nil
+}
+
+// allowHosts applies the policy, when there is one, to the hosts of a request body
+func allowHosts(ctx *gin.Context, logger *log.Entry, policy HostPolicy, hosts ...string) bool {
+	if policy == nil {
+		return true
+	}
+
+	if err := policy.Allow(hosts...); err != nil {
+		refuseHost(ctx, logger, err)
+		return false
+	}
+	return true
+}
+
+func refuseHost(ctx *gin.Context, logger *log.Entry, err error) {
+	logger.WithError(err).Warn("request for an unregistered host refused")
+	metrics.IncrCounter([]string{"errors", "registry", "unregistered_host"}, 1)
+	ctx.JSON(http.StatusForbidden, newErrorResponse(err))
+}
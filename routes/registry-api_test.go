@@ -0,0 +1,75 @@
+package routes
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/bmc-toolbox/actor/internal/registry"
+	"github.com/gin-gonic/gin"
+)
+
+func TestRegistryAPI_Policy(t *testing.T) {
+	dir, err := ioutil.TempDir("", "registry")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	fleet, err := registry.New(filepath.Join(dir, "targets.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	api := NewRegistryAPI(fleet, newTestAuthorizer(), true)
+	router := gin.New()
+	router.Use(api.Policy)
+	router.PUT("/targets/:target", api.PutTarget)
+	router.GET("/host/:host", func(ctx *gin.Context) { ctx.Status(http.StatusOK) })
+
+	tests := []struct {
+		name          string
+		method        string
+		path          string
+		authorization string
+		body          string
+		wantCode      int
+	}{
+		{name: "Unregistered host", method: http.MethodGet, path: "/host/1.1.1.1", wantCode: http.StatusForbidden},
+		{name: "Register without authorization", method: http.MethodPut, path: "/targets/1.1.1.1", body: `{}`, wantCode: http.StatusForbidden},
+		{
+			name:          "Invalid label",
+			method:        http.MethodPut,
+			path:          "/targets/1.1.1.1",
+			authorization: testAuthorization,
+			body:          `{"labels": {"rack": "r 12"}}`,
+			wantCode:      http.StatusBadRequest,
+		},
+		{
+			name:          "Register",
+			method:        http.MethodPut,
+			path:          "/targets/1.1.1.1",
+			authorization: testAuthorization,
+			body:          `{"labels": {"rack": "r12"}}`,
+			wantCode:      http.StatusOK,
+		},
+		{name: "Registered host", method: http.MethodGet, path: "/host/1.1.1.1", wantCode: http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			request := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			request.Header.Set("Authorization", tt.authorization)
+
+			router.ServeHTTP(recorder, request)
+
+			if recorder.Code != tt.wantCode {
+				t.Errorf("%s %s = %d %s, want %d", tt.method, tt.path, recorder.Code, recorder.Body.String(), tt.wantCode)
+			}
+		})
+	}
+}
@@ -1,6 +1,173 @@
 package routes
 
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/bmc-toolbox/actor/internal/actions"
+	"github.com/bmc-toolbox/actor/internal/providers/ipmi"
+	"github.com/bmc-toolbox/bmclib/cfgresources"
+)
+
 // request describes the action to be carried out by actor
 type request struct {
 	ActionSequence []string `json:"action-sequence"`
+	// Webhook is notified about the outcome in addition to the configured webhooks
+	Webhook string `json:"webhook"`
+}
+
+// workflowRequest describes the parameters of a workflow invocation
+type workflowRequest struct {
+	Params  map[string]string `json:"params"`
+	Webhook string            `json:"webhook"`
+}
+
+// userRequest describes a BMC user, the password is referenced in the credential store,
+// Password is only there to refuse requests carrying one
+type userRequest struct {
+	Name        string `json:"name"`
+	PasswordRef string `json:"password-ref"`
+	Role        string `json:"role"`
+	Password    string `json:"password"`
+}
+
+// rotationRequest describes the hosts whose BMC password is rotated to the password referenced in the credential store
+type rotationRequest struct {
+	Hosts       []string `json:"hosts"`
+	PasswordRef string   `json:"password-ref"`
+}
+
+// csrRequest describes the subject of the certificate the BMC requests
+type csrRequest struct {
+	CommonName       string `json:"common-name"`
+	OrganizationName string `json:"organization-name"`
+	OrganizationUnit string `json:"organization-unit"`
+	Locality         string `json:"locality"`
+	StateName        string `json:"state-name"`
+	CountryCode      string `json:"country-code"`
+	Email            string `json:"email"`
+	SubjectAltName   string `json:"subject-alt-name"`
+}
+
+func (r *csrRequest) attributes() (*cfgresources.HTTPSCertAttributes, error) {
+	if r.CommonName == "" {
+		return nil, fmt.Errorf("no common-name")
+	}
+
+	return &cfgresources.HTTPSCertAttributes{
+		CommonName:       r.CommonName,
+		OrganizationName: r.OrganizationName,
+		OrganizationUnit: r.OrganizationUnit,
+		Locality:         r.Locality,
+		StateName:        r.StateName,
+		CountryCode:      r.CountryCode,
+		Email:            r.Email,
+		SubjectAltName:   r.SubjectAltName,
+	}, nil
+}
+
+// certificateReportRequest describes the BMCs whose certificates are reported,
+// within (e.g. 720h) flags the certificates expiring soon and defaults to the configured duration
+type certificateReportRequest struct {
+	Hosts  []string `json:"hosts"`
+	Within string   `json:"within"`
+}
+
+// bulkRequest describes the action sequence to be carried out on the registered targets matching the selector,
+// with dry-run the targets are only resolved and the sequence validated
+type bulkRequest struct {
+	Selector       string   `json:"selector"`
+	ActionSequence []string `json:"action-sequence"`
+	DryRun         bool     `json:"dry-run"`
+	// Concurrency is capped by the configured concurrency, which is also the default
+	Concurrency int    `json:"concurrency"`
+	Webhook     string `json:"webhook"`
+}
+
+// discoveryRequest describes the CIDR range scanned for BMCs, with register the BMCs found are added to the registry
+// with the labels
+type discoveryRequest struct {
+	CIDR     string            `json:"cidr"`
+	Register bool              `json:"register"`
+	Labels   map[string]string `json:"labels"`
+}
+
+// scheduleRequest describes the action sequence to be carried out later,
+// exactly one of run-at (RFC 3339), in (a delay, e.g. 30m) and cron (5 fields, UTC) is required
+type scheduleRequest struct {
+	Target         actions.Target `json:"target"`
+	ActionSequence []string       `json:"action-sequence"`
+	RunAt          *time.Time     `json:"run-at"`
+	In             string         `json:"in"`
+	Cron           string         `json:"cron"`
+	Webhook        string         `json:"webhook"`
+}
+
+func (r *scheduleRequest) runAt(now time.Time) (*time.Time, error) {
+	if r.In == "" {
+		return r.RunAt, nil
+	}
+
+	if r.RunAt != nil {
+		return nil, fmt.Errorf("only one of run-at and in is allowed")
+	}
+
+	delay, err := time.ParseDuration(r.In)
+	if err != nil {
+		return nil, fmt.Errorf("invalid delay %q: %w", r.In, err)
+	}
+
+	runAt := now.Add(delay)
+	return &runAt, nil
+}
+
+// validate checks a host user request, the password and the role are required for new users
+func (r *userRequest) validate(create bool) error {
+	if err := r.validateName(); err != nil {
+		return err
+	}
+
+	switch r.Role {
+	case ipmi.RoleAdmin, ipmi.RoleOperator, ipmi.RoleUser:
+	case "":
+		if create {
+			return fmt.Errorf("no role: one of admin, operator and user is expected")
+		}
+	default:
+		return fmt.Errorf("unknown role %q: one of admin, operator and user is expected", r.Role)
+	}
+
+	if r.PasswordRef == "" && create {
+		return fmt.Errorf("no password-ref")
+	}
+	if r.PasswordRef == "" && r.Role == "" {
+		return fmt.Errorf("nothing to update: password-ref and role are empty")
+	}
+
+	return nil
+}
+
+// validateBlade checks a blade user request, the chassis only manage the passwords of admin users
+func (r *userRequest) validateBlade() error {
+	if err := r.validateName(); err != nil {
+		return err
+	}
+	if r.Role != "" && r.Role != ipmi.RoleAdmin {
+		return fmt.Errorf("only admin users are supported on blade BMCs")
+	}
+	if r.PasswordRef == "" {
+		return fmt.Errorf("no password-ref")
+	}
+	return nil
+}
+
+func (r *userRequest) validateName() error {
+	if r.Password != "" {
+		return fmt.Errorf("passwords are read from the credential store, use password-ref")
+	}
+	if r.Name == "" || strings.ContainsAny(r.Name, " \t:") {
+		return fmt.Errorf("invalid user name %q", r.Name)
+	}
+	return nil
 }
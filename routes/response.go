@@ -1,7 +1,11 @@
 package routes
 
 import (
+	"time"
+
 	"github.com/bmc-toolbox/actor/internal/actions"
+	"github.com/bmc-toolbox/actor/internal/providers"
+	"github.com/bmc-toolbox/actor/internal/screenshot"
 )
 
 // response represents an action response
@@ -10,6 +14,58 @@ type response struct {
 	Status  bool   `json:"status"`
 	Message string `json:"message"`
 	Error   string `json:"error"`
+	// Workflow is set when the action is a step of a workflow
+	Workflow string `json:"workflow,omitempty"`
+}
+
+// workflowResponse represents the outcome of a workflow and its expanded steps
+type workflowResponse struct {
+	Workflow string     `json:"workflow"`
+	Status   bool       `json:"status"`
+	Steps    []response `json:"steps"`
+}
+
+// stepResponse represents the progress of an action sequence, Result is set once the step has finished
+type stepResponse struct {
+	Index    int       `json:"index"`
+	Total    int       `json:"total"`
+	Action   string    `json:"action"`
+	Workflow string    `json:"workflow,omitempty"`
+	Result   *response `json:"result,omitempty"`
+}
+
+// screenshotResponse represents a stored screenshot
+type screenshotResponse struct {
+	Key          string    `json:"key"`
+	URL          string    `json:"url"`
+	HardwareType string    `json:"hardware-type"`
+	Taken        time.Time `json:"taken"`
+	Size         int64     `json:"size"`
+}
+
+// sensorResponse represents a normalized sensor reading, missing values and thresholds are null
+type sensorResponse struct {
+	Name       string                  `json:"name"`
+	Type       string                  `json:"type"`
+	Value      *float64                `json:"value"`
+	Unit       string                  `json:"unit"`
+	Status     string                  `json:"status"`
+	Thresholds sensorThresholdResponse `json:"thresholds"`
+}
+
+type sensorThresholdResponse struct {
+	LowerCritical *float64 `json:"lower-critical"`
+	LowerWarning  *float64 `json:"lower-warning"`
+	UpperWarning  *float64 `json:"upper-warning"`
+	UpperCritical *float64 `json:"upper-critical"`
+}
+
+// bulkResponse represents the targets resolved from a selector by a dry-run
+type bulkResponse struct {
+	Selector string   `json:"selector"`
+	Targets  []string `json:"targets"`
+	DryRun   bool     `json:"dry-run"`
+	Status   bool     `json:"status"`
 }
 
 // errorResponse represents not an action error, i.e. BadRequest, StatusPreconditionFailed
@@ -17,6 +73,15 @@ type errorResponse struct {
 	Error string `json:"error"`
 }
 
+// capabilitiesResponse represents the actions supported by a target and the BMC behind it
+type capabilitiesResponse struct {
+	Actions         []string `json:"actions"`
+	Vendor          string   `json:"vendor"`
+	HardwareType    string   `json:"hardware-type"`
+	Provider        string   `json:"provider"`
+	FirmwareVersion string   `json:"firmware-version"`
+}
+
 func newResponse(action string, status bool, message string, err error) response {
 	resp := response{
 		Action:  action,
@@ -37,11 +102,62 @@ func newErrorResponse(err error) errorResponse {
 	}
 }
 
+func newCapabilitiesResponse(capabilities *actions.Capabilities) capabilitiesResponse {
+	return capabilitiesResponse{
+		Actions:         capabilities.Actions,
+		Vendor:          capabilities.Vendor,
+		HardwareType:    capabilities.HardwareType,
+		Provider:        capabilities.Provider,
+		FirmwareVersion: capabilities.FirmwareVersion,
+	}
+}
+
+func newSensorResponse(sensor providers.Sensor) sensorResponse {
+	return sensorResponse{
+		Name:   sensor.Name,
+		Type:   sensor.Type,
+		Value:  sensor.Value,
+		Unit:   sensor.Unit,
+		Status: sensor.Status,
+		Thresholds: sensorThresholdResponse{
+			LowerCritical: sensor.Thresholds.LowerCritical,
+			LowerWarning:  sensor.Thresholds.LowerWarning,
+			UpperWarning:  sensor.Thresholds.UpperWarning,
+			UpperCritical: sensor.Thresholds.UpperCritical,
+		},
+	}
+}
+
+func newStepResponse(event actions.StepEvent) stepResponse {
+	resp := stepResponse{
+		Index:    event.Index,
+		Total:    event.Total,
+		Action:   event.Action,
+		Workflow: event.Workflow,
+	}
+	if event.Result != nil {
+		result := actionResultsToResponses([]actions.ActionResult{*event.Result})[0]
+		resp.Result = &result
+	}
+	return resp
+}
+
+func newScreenshotResponse(info screenshot.Info, url string) screenshotResponse {
+	return screenshotResponse{
+		Key:          info.Key,
+		URL:          url,
+		HardwareType: info.HardwareType,
+		Taken:        info.Taken,
+		Size:         info.Size,
+	}
+}
+
 func actionResultsToResponses(results []actions.ActionResult) []response {
 	responses := make([]response, 0)
 
 	for _, result := range results {
 		resp := newResponse(result.Action, result.Status, result.Message, result.Error)
+		resp.Workflow = result.Workflow
 		responses = append(responses, resp)
 	}
 
@@ -0,0 +1,113 @@
+package routes
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/bmc-toolbox/actor/internal/credentials"
+	"github.com/bmc-toolbox/actor/internal/rotation"
+	"github.com/gin-gonic/gin"
+	log "github.com/sirupsen/logrus"
+)
+
+type (
+	RotationAPI struct {
+		rotator    *rotation.Rotator
+		authorizer *Authorizer
+		policy     HostPolicy
+	}
+)
+
+func NewRotationAPI(rotator *rotation.Rotator, authorizer *Authorizer) *RotationAPI {
+	return &RotationAPI{rotator: rotator, authorizer: authorizer}
+}
+
+// WithPolicy refuses rotations of the hosts the policy doesn't allow
+func (ra *RotationAPI) WithPolicy(policy HostPolicy) *RotationAPI {
+	ra.policy = policy
+	return ra
+}
+
+// CreateRotation starts rotating the BMC password of the given hosts, only authorized clients may rotate passwords
+func (ra RotationAPI) CreateRotation(ctx *gin.Context) {
+	logger := log.WithField("method", "CreateRotation")
+
+	if err := ra.authorizer.Authorize(ctx); err != nil {
+		logger.Warn(err)
+		ctx.JSON(http.StatusForbidden, newErrorResponse(err))
+		return
+	}
+
+	req := &rotationRequest{}
+	if err := ctx.ShouldBindJSON(req); err != nil {
+		invalid(ctx, logger, "rotation", fmt.Errorf("failed to unmarshal request: %w", err))
+		return
+	}
+
+	if !allowHosts(ctx, logger, ra.policy, req.Hosts...) {
+		return
+	}
+
+	job, err := ra.rotator.Start(req.Hosts, req.PasswordRef)
+	if errors.Is(err, rotation.ErrRunning) {
+		logger.Warn(err)
+		ctx.JSON(http.StatusConflict, newErrorResponse(err))
+		return
+	}
+	if err != nil {
+		invalid(ctx, logger, "rotation", err)
+		return
+	}
+
+	logger.WithField("rotation", job.ID).Info("BMC password rotation started")
+	ctx.JSON(http.StatusAccepted, job)
+}
+
+// ListRotations returns all rotations with the progress of their hosts
+func (ra RotationAPI) ListRotations(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, ra.rotator.List())
+}
+
+// GetRotation returns a given rotation with the progress of its hosts
+func (ra RotationAPI) GetRotation(ctx *gin.Context) {
+	job, err := ra.rotator.Get(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(rotationErrorStatus(err), newErrorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, job)
+}
+
+// ResumeRotation runs a given rotation again for the hosts which aren't done
+func (ra RotationAPI) ResumeRotation(ctx *gin.Context) {
+	logger := log.WithField("method", "ResumeRotation").WithField("rotation", ctx.Param("id"))
+
+	if err := ra.authorizer.Authorize(ctx); err != nil {
+		logger.Warn(err)
+		ctx.JSON(http.StatusForbidden, newErrorResponse(err))
+		return
+	}
+
+	job, err := ra.rotator.Resume(ctx.Param("id"))
+	if err != nil {
+		logger.Warn(err)
+		ctx.JSON(rotationErrorStatus(err), newErrorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusAccepted, job)
+}
+
+func rotationErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, rotation.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, rotation.ErrRunning):
+		return http.StatusConflict
+	case errors.Is(err, credentials.ErrNotFound):
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
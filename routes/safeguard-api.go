@@ -0,0 +1,42 @@
+package routes
+
+import (
+	"net/http"
+
+	"github.com/bmc-toolbox/actor/internal/safeguard"
+	"github.com/gin-gonic/gin"
+	log "github.com/sirupsen/logrus"
+)
+
+type (
+	// SafeguardAPI reports the blast-radius limits of destructive actions and resets their circuit breaker
+	SafeguardAPI struct {
+		safeguard  *safeguard.Safeguard
+		authorizer *Authorizer
+	}
+)
+
+func NewSafeguardAPI(safeguard *safeguard.Safeguard, authorizer *Authorizer) *SafeguardAPI {
+	return &SafeguardAPI{safeguard: safeguard, authorizer: authorizer}
+}
+
+// SafeguardStatus returns the limits, the destructive actions started within the last minute and the breaker
+func (sa SafeguardAPI) SafeguardStatus(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, sa.safeguard.Status())
+}
+
+// ResetBreaker closes the circuit breaker, only authorized clients may let destructive actions through again
+func (sa SafeguardAPI) ResetBreaker(ctx *gin.Context) {
+	logger := log.WithField("method", "ResetBreaker")
+
+	if err := sa.authorizer.Authorize(ctx); err != nil {
+		logger.Warn(err)
+		ctx.JSON(http.StatusForbidden, newErrorResponse(err))
+		return
+	}
+
+	sa.safeguard.Reset()
+	logger.WithField("identity", sa.authorizer.Identity(ctx)).Info("circuit breaker reset requested")
+
+	ctx.JSON(http.StatusOK, sa.safeguard.Status())
+}
@@ -0,0 +1,117 @@
+package routes
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/bmc-toolbox/actor/internal/scheduler"
+	"github.com/bmc-toolbox/actor/internal/webhook"
+	"github.com/gin-gonic/gin"
+	log "github.com/sirupsen/logrus"
+)
+
+type (
+	ScheduleAPI struct {
+		scheduler  *scheduler.Scheduler
+		notifier   *webhook.Notifier
+		authorizer *Authorizer
+		policy     HostPolicy
+	}
+)
+
+func NewScheduleAPI(scheduler *scheduler.Scheduler, notifier *webhook.Notifier, authorizer *Authorizer) *ScheduleAPI {
+	return &ScheduleAPI{scheduler: scheduler, notifier: notifier, authorizer: authorizer}
+}
+
+// WithPolicy refuses schedules on the hosts the policy doesn't allow
+func (sa *ScheduleAPI) WithPolicy(policy HostPolicy) *ScheduleAPI {
+	sa.policy = policy
+	return sa
+}
+
+// CreateSchedule registers an action sequence to be carried out later on a given target
+func (sa ScheduleAPI) CreateSchedule(ctx *gin.Context) {
+	logger := log.WithField("method", "CreateSchedule")
+
+	req := &scheduleRequest{}
+	if err := ctx.ShouldBindJSON(req); err != nil {
+		invalid(ctx, logger, "scheduler", fmt.Errorf("failed to unmarshal request: %w", err))
+		return
+	}
+
+	if !allowHosts(ctx, logger, sa.policy, req.Target.Host) {
+		return
+	}
+
+	runAt, err := req.runAt(time.Now())
+	if err != nil {
+		invalid(ctx, logger, "scheduler", err)
+		return
+	}
+
+	if req.Webhook != "" {
+		if err := sa.notifier.ValidateURL(req.Webhook); err != nil {
+			invalid(ctx, logger, "scheduler", err)
+			return
+		}
+	}
+
+	privileged, err := sa.scheduler.IsPrivileged(req.Target, req.ActionSequence)
+	if err != nil {
+		invalid(ctx, logger, "scheduler", err)
+		return
+	}
+	if privileged {
+		if err := sa.authorizer.Authorize(ctx); err != nil {
+			logger.Warn(err)
+			ctx.JSON(http.StatusForbidden, newErrorResponse(err))
+			return
+		}
+	}
+
+	schedule, err := sa.scheduler.Add(req.Target, req.ActionSequence, runAt, req.Cron, req.Webhook)
+	if err != nil {
+		invalid(ctx, logger, "scheduler", err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, schedule)
+}
+
+// ListSchedules returns all schedules with their results
+func (sa ScheduleAPI) ListSchedules(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, sa.scheduler.List())
+}
+
+// GetSchedule returns a given schedule with its results
+func (sa ScheduleAPI) GetSchedule(ctx *gin.Context) {
+	schedule, err := sa.scheduler.Get(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(scheduleErrorStatus(err), newErrorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, schedule)
+}
+
+// CancelSchedule removes a given schedule
+func (sa ScheduleAPI) CancelSchedule(ctx *gin.Context) {
+	logger := log.WithField("method", "CancelSchedule").WithField("schedule", ctx.Param("id"))
+
+	if err := sa.scheduler.Cancel(ctx.Param("id")); err != nil {
+		logger.Warn(err)
+		ctx.JSON(scheduleErrorStatus(err), newErrorResponse(err))
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+func scheduleErrorStatus(err error) int {
+	if errors.Is(err, scheduler.ErrNotFound) {
+		return http.StatusNotFound
+	}
+	return http.StatusInternalServerError
+}
@@ -0,0 +1,118 @@
+package routes
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/bmc-toolbox/actor/internal/screenshot"
+	"github.com/gin-gonic/gin"
+	log "github.com/sirupsen/logrus"
+)
+
+type (
+	ScreenshotAPI struct {
+		store screenshot.Store
+	}
+)
+
+func NewScreenshotAPI(store screenshot.Store) *ScreenshotAPI {
+	return &ScreenshotAPI{store: store}
+}
+
+// HostScreenshots lists the stored screenshots of a given host, newest first
+func (sa ScreenshotAPI) HostScreenshots(ctx *gin.Context) {
+	logger := log.WithField("method", "HostScreenshots")
+
+	host := ctx.Param("host")
+	if err := validateHost(host); err != nil {
+		invalid(ctx, logger, "bmc", err)
+		return
+	}
+	logger = log.WithField("ip", host)
+
+	infos, err := screenshot.ListInfo(sa.store, host)
+	if err != nil {
+		logger.WithError(err).Error("failed to list screenshots")
+		ctx.JSON(http.StatusInternalServerError, newErrorResponse(err))
+		return
+	}
+
+	responses := make([]screenshotResponse, 0, len(infos))
+	for _, info := range infos {
+		url, err := sa.store.URL(info.Key)
+		if err != nil {
+			logger.WithError(err).Error("failed to get screenshot URL")
+			ctx.JSON(http.StatusInternalServerError, newErrorResponse(err))
+			return
+		}
+		responses = append(responses, newScreenshotResponse(info, url))
+	}
+
+	ctx.JSON(http.StatusOK, responses)
+}
+
+// DeleteHostScreenshot deletes a stored screenshot of a given host
+func (sa ScreenshotAPI) DeleteHostScreenshot(ctx *gin.Context) {
+	logger := log.WithField("method", "DeleteHostScreenshot")
+
+	host := ctx.Param("host")
+	if err := validateHost(host); err != nil {
+		invalid(ctx, logger, "bmc", err)
+		return
+	}
+	logger = log.WithField("ip", host)
+
+	infos, err := screenshot.ListInfo(sa.store, host)
+	if err != nil {
+		logger.WithError(err).Error("failed to list screenshots")
+		ctx.JSON(http.StatusInternalServerError, newErrorResponse(err))
+		return
+	}
+
+	key := ctx.Param("key")
+	for _, info := range infos {
+		if info.Key != key {
+			continue
+		}
+
+		if err := sa.store.Delete(key); err != nil && !errors.Is(err, screenshot.ErrNotFound) {
+			logger.WithError(err).Error("failed to delete screenshot")
+			ctx.JSON(http.StatusInternalServerError, newErrorResponse(err))
+			return
+		}
+
+		ctx.Status(http.StatusNoContent)
+		return
+	}
+
+	ctx.JSON(http.StatusNotFound, newErrorResponse(screenshot.ErrNotFound))
+}
+
+// DeleteHostScreenshots deletes all stored screenshots of a given host
+func (sa ScreenshotAPI) DeleteHostScreenshots(ctx *gin.Context) {
+	logger := log.WithField("method", "DeleteHostScreenshots")
+
+	host := ctx.Param("host")
+	if err := validateHost(host); err != nil {
+		invalid(ctx, logger, "bmc", err)
+		return
+	}
+	logger = log.WithField("ip", host)
+
+	infos, err := screenshot.ListInfo(sa.store, host)
+	if err != nil {
+		logger.WithError(err).Error("failed to list screenshots")
+		ctx.JSON(http.StatusInternalServerError, newErrorResponse(err))
+		return
+	}
+
+	for _, info := range infos {
+		if err := sa.store.Delete(info.Key); err != nil && !errors.Is(err, screenshot.ErrNotFound) {
+			logger.WithError(err).Error("failed to delete screenshot")
+			ctx.JSON(http.StatusInternalServerError, newErrorResponse(err))
+			return
+		}
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
@@ -0,0 +1,104 @@
+package routes
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/bmc-toolbox/actor/internal/providers/ipmi"
+	"github.com/gin-gonic/gin"
+	log "github.com/sirupsen/logrus"
+)
+
+type (
+	SelAPI struct {
+		reader selReader
+	}
+
+	selReader interface {
+		Read(host string) ([]ipmi.SelEntry, error)
+	}
+
+	// selFilter keeps the entries logged within [since, until] with at least the given severity
+	selFilter struct {
+		since    time.Time
+		until    time.Time
+		severity string
+	}
+)
+
+func NewSelAPI(reader selReader) *SelAPI {
+	return &SelAPI{reader: reader}
+}
+
+// HostSel returns the System Event Log of a given host, filtered by ?since=, ?until= (RFC 3339) and ?severity=
+func (sa SelAPI) HostSel(ctx *gin.Context) {
+	logger := log.WithField("method", "HostSel")
+
+	host := ctx.Param("host")
+	if err := validateHost(host); err != nil {
+		invalid(ctx, logger, "bmc", err)
+		return
+	}
+	logger = log.WithField("ip", host)
+
+	filter, err := newSelFilter(ctx)
+	if err != nil {
+		invalid(ctx, logger, "bmc", err)
+		return
+	}
+
+	entries, err := sa.reader.Read(host)
+	if err != nil {
+		logger.WithError(err).Error("failed to read SEL")
+		ctx.JSON(http.StatusPreconditionFailed, newErrorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, filter.apply(entries))
+}
+
+func newSelFilter(ctx *gin.Context) (selFilter, error) {
+	filter := selFilter{severity: ctx.Query("severity")}
+
+	switch filter.severity {
+	case "", ipmi.SeverityInfo, ipmi.SeverityWarning, ipmi.SeverityCritical:
+	default:
+		return selFilter{}, fmt.Errorf("invalid severity %q: one of info, warning and critical is expected", filter.severity)
+	}
+
+	for param, t := range map[string]*time.Time{"since": &filter.since, "until": &filter.until} {
+		value := ctx.Query(param)
+		if value == "" {
+			continue
+		}
+
+		parsed, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return selFilter{}, fmt.Errorf("invalid %s: %w", param, err)
+		}
+		*t = parsed
+	}
+
+	return filter, nil
+}
+
+// apply filters the entries, entries without a time are only kept when no time range is given
+func (f selFilter) apply(entries []ipmi.SelEntry) []ipmi.SelEntry {
+	filtered := make([]ipmi.SelEntry, 0, len(entries))
+
+	for _, entry := range entries {
+		if ipmi.SeverityRank(entry.Severity) < ipmi.SeverityRank(f.severity) {
+			continue
+		}
+		if !f.since.IsZero() && (entry.Time.IsZero() || entry.Time.Before(f.since)) {
+			continue
+		}
+		if !f.until.IsZero() && (entry.Time.IsZero() || entry.Time.After(f.until)) {
+			continue
+		}
+		filtered = append(filtered, entry)
+	}
+
+	return filtered
+}
@@ -0,0 +1,59 @@
+package routes
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/bmc-toolbox/actor/internal/providers/ipmi"
+	"github.com/gin-gonic/gin"
+)
+
+func Test_selFilter(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2021, 6, d, 0, 0, 0, 0, time.UTC) }
+	entries := []ipmi.SelEntry{
+		{ID: 1, Severity: ipmi.SeverityInfo},
+		{ID: 2, Time: day(1), Severity: ipmi.SeverityCritical},
+		{ID: 3, Time: day(2), Severity: ipmi.SeverityWarning},
+		{ID: 4, Time: day(3), Severity: ipmi.SeverityInfo},
+	}
+
+	tests := []struct {
+		name    string
+		query   string
+		wantIDs []int
+		wantErr bool
+	}{
+		{name: "No filter", query: "", wantIDs: []int{1, 2, 3, 4}},
+		{name: "Severity", query: "?severity=warning", wantIDs: []int{2, 3}},
+		{name: "Since", query: "?since=2021-06-02T00:00:00Z", wantIDs: []int{3, 4}},
+		{name: "Range and severity", query: "?since=2021-06-01T00:00:00Z&until=2021-06-02T12:00:00Z&severity=critical", wantIDs: []int{2}},
+		{name: "Invalid severity", query: "?severity=bad", wantErr: true},
+		{name: "Invalid time", query: "?until=yesterday", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
+			ctx.Request = httptest.NewRequest(http.MethodGet, "/host/1.1.1.1/sel"+tt.query, nil)
+
+			filter, err := newSelFilter(ctx)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("newSelFilter() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			got := filter.apply(entries)
+			if len(got) != len(tt.wantIDs) {
+				t.Fatalf("apply() = %+v, want IDs %v", got, tt.wantIDs)
+			}
+			for i, entry := range got {
+				if entry.ID != tt.wantIDs[i] {
+					t.Errorf("apply() = %+v, want IDs %v", got, tt.wantIDs)
+				}
+			}
+		})
+	}
+}
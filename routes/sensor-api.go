@@ -0,0 +1,59 @@
+package routes
+
+import (
+	"net/http"
+
+	"github.com/bmc-toolbox/actor/internal/providers"
+	"github.com/gin-gonic/gin"
+	log "github.com/sirupsen/logrus"
+)
+
+type (
+	SensorAPI struct {
+		reader sensorReader
+	}
+
+	sensorReader interface {
+		HostSensors(host string) ([]providers.Sensor, error)
+		ChassisSensors(host string) ([]providers.Sensor, error)
+	}
+)
+
+func NewSensorAPI(reader sensorReader) *SensorAPI {
+	return &SensorAPI{reader: reader}
+}
+
+// HostSensors returns the sensor readings of a given host
+func (sa SensorAPI) HostSensors(ctx *gin.Context) {
+	sa.sensors(ctx, "HostSensors", sa.reader.HostSensors)
+}
+
+// ChassisSensors returns the sensor readings of a given chassis
+func (sa SensorAPI) ChassisSensors(ctx *gin.Context) {
+	sa.sensors(ctx, "ChassisSensors", sa.reader.ChassisSensors)
+}
+
+func (sa SensorAPI) sensors(ctx *gin.Context, method string, read func(string) ([]providers.Sensor, error)) {
+	logger := log.WithField("method", method)
+
+	host := ctx.Param("host")
+	if err := validateHost(host); err != nil {
+		invalid(ctx, logger, "bmc", err)
+		return
+	}
+	logger = log.WithField("ip", host)
+
+	sensors, err := read(host)
+	if err != nil {
+		logger.WithError(err).Error("failed to read sensors")
+		ctx.JSON(http.StatusPreconditionFailed, newErrorResponse(err))
+		return
+	}
+
+	responses := make([]sensorResponse, 0, len(sensors))
+	for _, sensor := range sensors {
+		responses = append(responses, newSensorResponse(sensor))
+	}
+
+	ctx.JSON(http.StatusOK, responses)
+}
@@ -0,0 +1,249 @@
+package routes
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/bmc-toolbox/actor/internal/credentials"
+	"github.com/bmc-toolbox/actor/internal/providers/ipmi"
+	"github.com/gin-gonic/gin"
+	log "github.com/sirupsen/logrus"
+)
+
+type (
+	// UserAPI manages the local users of BMCs, every change requires authorization
+	UserAPI struct {
+		manager    userManager
+		authorizer *Authorizer
+	}
+
+	userManager interface {
+		HostUsers(host string) ([]ipmi.User, error)
+		CreateHostUser(host, name, passwordRef, role string) error
+		UpdateHostUser(host, name, passwordRef, role string) error
+		DeleteHostUser(host, name string) error
+
+		BladeUsers(host string, bladePos int) ([]ipmi.User, error)
+		CreateBladeUsers(host, name, passwordRef string) error
+		UpdateBladeUsers(host, name, passwordRef string) error
+		DeleteBladeUsers(host, name string) error
+	}
+)
+
+func NewUserAPI(manager userManager, authorizer *Authorizer) *UserAPI {
+	return &UserAPI{manager: manager, authorizer: authorizer}
+}
+
+// HostUsers lists the users of the BMC of a given host
+func (ua UserAPI) HostUsers(ctx *gin.Context) {
+	logger := log.WithField("method", "HostUsers")
+
+	host := ctx.Param("host")
+	if err := validateHost(host); err != nil {
+		invalid(ctx, logger, "bmc", err)
+		return
+	}
+
+	users, err := ua.manager.HostUsers(host)
+	if err != nil {
+		logger.WithField("ip", host).WithError(err).Error("failed to list BMC users")
+		ctx.JSON(userErrorStatus(err), newErrorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, users)
+}
+
+// CreateHostUser creates a user on the BMC of a given host
+func (ua UserAPI) CreateHostUser(ctx *gin.Context) {
+	logger := log.WithField("method", "CreateHostUser")
+
+	if !ua.authorize(ctx, logger) {
+		return
+	}
+
+	req, ok := ua.userRequest(ctx, logger, "")
+	if !ok {
+		return
+	}
+	if err := req.validate(true); err != nil {
+		invalid(ctx, logger, "bmc", err)
+		return
+	}
+
+	ua.change(ctx, logger, http.StatusCreated, func(host string) error {
+		return ua.manager.CreateHostUser(host, req.Name, req.PasswordRef, req.Role)
+	})
+}
+
+// UpdateHostUser changes the password and the role of a user on the BMC of a given host
+func (ua UserAPI) UpdateHostUser(ctx *gin.Context) {
+	logger := log.WithField("method", "UpdateHostUser")
+
+	if !ua.authorize(ctx, logger) {
+		return
+	}
+
+	req, ok := ua.userRequest(ctx, logger, ctx.Param("name"))
+	if !ok {
+		return
+	}
+	if err := req.validate(false); err != nil {
+		invalid(ctx, logger, "bmc", err)
+		return
+	}
+
+	ua.change(ctx, logger, http.StatusNoContent, func(host string) error {
+		return ua.manager.UpdateHostUser(host, req.Name, req.PasswordRef, req.Role)
+	})
+}
+
+// DeleteHostUser disables a user on the BMC of a given host
+func (ua UserAPI) DeleteHostUser(ctx *gin.Context) {
+	logger := log.WithField("method", "DeleteHostUser")
+
+	if !ua.authorize(ctx, logger) {
+		return
+	}
+
+	ua.change(ctx, logger, http.StatusNoContent, func(host string) error {
+		return ua.manager.DeleteHostUser(host, ctx.Param("name"))
+	})
+}
+
+// BladeUsers lists the users of the BMC of a blade in a given chassis
+func (ua UserAPI) BladeUsers(ctx *gin.Context) {
+	logger := log.WithField("method", "BladeUsers")
+
+	host, pos := ctx.Param("host"), ctx.Param("pos")
+	if err := validateHost(host); err != nil {
+		invalid(ctx, logger, "bmc", err)
+		return
+	}
+	if err := validateBladePos(pos); err != nil {
+		invalid(ctx, logger, "bmc", err)
+		return
+	}
+	bladePos, _ := strconv.Atoi(pos)
+
+	users, err := ua.manager.BladeUsers(host, bladePos)
+	if err != nil {
+		logger.WithField("ip", host).WithField("pos", bladePos).WithError(err).Error("failed to list BMC users")
+		ctx.JSON(userErrorStatus(err), newErrorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, users)
+}
+
+// CreateBladeUsers creates an admin user on all the blade BMCs of a given chassis
+func (ua UserAPI) CreateBladeUsers(ctx *gin.Context) {
+	logger := log.WithField("method", "CreateBladeUsers")
+
+	if !ua.authorize(ctx, logger) {
+		return
+	}
+
+	req, ok := ua.userRequest(ctx, logger, "")
+	if !ok {
+		return
+	}
+	if err := req.validateBlade(); err != nil {
+		invalid(ctx, logger, "bmc", err)
+		return
+	}
+
+	ua.change(ctx, logger, http.StatusCreated, func(host string) error {
+		return ua.manager.CreateBladeUsers(host, req.Name, req.PasswordRef)
+	})
+}
+
+// UpdateBladeUsers changes the password of a user on all the blade BMCs of a given chassis
+func (ua UserAPI) UpdateBladeUsers(ctx *gin.Context) {
+	logger := log.WithField("method", "UpdateBladeUsers")
+
+	if !ua.authorize(ctx, logger) {
+		return
+	}
+
+	req, ok := ua.userRequest(ctx, logger, ctx.Param("name"))
+	if !ok {
+		return
+	}
+	if err := req.validateBlade(); err != nil {
+		invalid(ctx, logger, "bmc", err)
+		return
+	}
+
+	ua.change(ctx, logger, http.StatusNoContent, func(host string) error {
+		return ua.manager.UpdateBladeUsers(host, req.Name, req.PasswordRef)
+	})
+}
+
+// DeleteBladeUsers removes a user from all the blade BMCs of a given chassis
+func (ua UserAPI) DeleteBladeUsers(ctx *gin.Context) {
+	logger := log.WithField("method", "DeleteBladeUsers")
+
+	if !ua.authorize(ctx, logger) {
+		return
+	}
+
+	ua.change(ctx, logger, http.StatusNoContent, func(host string) error {
+		return ua.manager.DeleteBladeUsers(host, ctx.Param("name"))
+	})
+}
+
+// userRequest reads the request body, the name in the path wins over the one of the body
+func (ua UserAPI) userRequest(ctx *gin.Context, logger *log.Entry, name string) (*userRequest, bool) {
+	req := &userRequest{}
+	if err := ctx.ShouldBindJSON(req); err != nil {
+		invalid(ctx, logger, "bmc", err)
+		return nil, false
+	}
+	if name != "" {
+		req.Name = name
+	}
+	return req, true
+}
+
+// authorize refuses the request with 403 unless it carries a valid token
+func (ua UserAPI) authorize(ctx *gin.Context, logger *log.Entry) bool {
+	if err := ua.authorizer.Authorize(ctx); err != nil {
+		logger.Warn(err)
+		ctx.JSON(http.StatusForbidden, newErrorResponse(err))
+		return false
+	}
+	return true
+}
+
+// change carries out a change on the BMCs of the host of the path
+func (ua UserAPI) change(ctx *gin.Context, logger *log.Entry, status int, fn func(host string) error) {
+	host := ctx.Param("host")
+	if err := validateHost(host); err != nil {
+		invalid(ctx, logger, "bmc", err)
+		return
+	}
+	logger = logger.WithField("ip", host)
+
+	if err := fn(host); err != nil {
+		logger.WithError(err).Error("failed to change BMC users")
+		ctx.JSON(userErrorStatus(err), newErrorResponse(err))
+		return
+	}
+
+	logger.Info("BMC users changed")
+	ctx.Status(status)
+}
+
+func userErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, credentials.ErrNotFound):
+		return http.StatusBadRequest
+	case errors.Is(err, ipmi.ErrUserNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, ipmi.ErrUserExists), errors.Is(err, ipmi.ErrUserDisabled):
+		return http.StatusConflict
+	}
+	return http.StatusPreconditionFailed
+}
@@ -0,0 +1,89 @@
+package routes
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/bmc-toolbox/actor/internal/credentials"
+	"github.com/bmc-toolbox/actor/internal/providers/ipmi"
+)
+
+type testUserManager struct {
+	userManager
+	created []string
+	err     error
+}
+
+func (m *testUserManager) CreateHostUser(host, name, passwordRef, role string) error {
+	m.created = append(m.created, strings.Join([]string{host, name, passwordRef, role}, " "))
+	return m.err
+}
+
+func TestUserAPI_CreateHostUser(t *testing.T) {
+	tests := []struct {
+		name          string
+		authorization string
+		body          string
+		err           error
+		wantCode      int
+		wantCreated   string
+	}{
+		{
+			name:          "Created",
+			authorization: testAuthorization,
+			body:          `{"name":"ops","password-ref":"bmc/ops","role":"operator"}`,
+			wantCode:      http.StatusCreated,
+			wantCreated:   "1.1.1.1 ops bmc/ops operator",
+		},
+		{
+			name:     "Unauthorized",
+			body:     `{"name":"ops","password-ref":"bmc/ops","role":"operator"}`,
+			wantCode: http.StatusForbidden,
+		},
+		{
+			name:          "Plain password",
+			authorization: testAuthorization,
+			body:          `{"name":"ops","password":"secret","role":"operator"}`,
+			wantCode:      http.StatusBadRequest,
+		},
+		{
+			name:          "Unknown role",
+			authorization: testAuthorization,
+			body:          `{"name":"ops","password-ref":"bmc/ops","role":"root"}`,
+			wantCode:      http.StatusBadRequest,
+		},
+		{
+			name:          "Unknown credential",
+			authorization: testAuthorization,
+			body:          `{"name":"ops","password-ref":"bmc/missing","role":"admin"}`,
+			err:           fmt.Errorf("%w: bmc/missing", credentials.ErrNotFound),
+			wantCode:      http.StatusBadRequest,
+			wantCreated:   "1.1.1.1 ops bmc/missing admin",
+		},
+		{
+			name:          "Existing user",
+			authorization: testAuthorization,
+			body:          `{"name":"ops","password-ref":"bmc/ops","role":"admin"}`,
+			err:           ipmi.ErrUserExists,
+			wantCode:      http.StatusConflict,
+			wantCreated:   "1.1.1.1 ops bmc/ops admin",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			manager := &testUserManager{err: tt.err}
+			recorder := testRequest{
+				method:        http.MethodPost,
+				path:          "/host/1.1.1.1/users",
+				authorization: tt.authorization,
+				body:          strings.NewReader(tt.body),
+				params:        hostParams(),
+			}.serve(NewUserAPI(manager, newTestAuthorizer()).CreateHostUser)
+
+			checkResponse(t, "CreateHostUser", recorder, tt.wantCode)
+			checkCalls(t, "CreateHostUser", manager.created, tt.wantCreated)
+		})
+	}
+}
@@ -2,11 +2,38 @@ package routes
 
 import (
 	"fmt"
+	"net/http"
 	"strconv"
+	"strings"
 
+	metrics "github.com/bmc-toolbox/gin-go-metrics"
 	"github.com/gin-gonic/gin"
+	log "github.com/sirupsen/logrus"
 )
 
+const (
+	sseEventStepStarted  = "step-started"
+	sseEventStepFinished = "step-finished"
+	sseEventDone         = "done"
+	sseEventFailed       = "failed"
+)
+
+// isStreamRequested tells whether the client asked for progress as Server-Sent Events,
+// either with ?stream=true or with an "Accept: text/event-stream" header
+func isStreamRequested(c *gin.Context) bool {
+	if stream, err := strconv.ParseBool(c.Query("stream")); err == nil {
+		return stream
+	}
+	return strings.Contains(c.GetHeader("Accept"), "text/event-stream")
+}
+
+// invalid rejects a request with a 400, counting it in the user_request_invalid errors of the metrics area
+func invalid(ctx *gin.Context, logger *log.Entry, area string, err error) {
+	logger.Warn(err)
+	metrics.IncrCounter([]string{"errors", area, "user_request_invalid"}, 1)
+	ctx.JSON(http.StatusBadRequest, newErrorResponse(err))
+}
+
 func validateHost(host string) error {
 	if host == "" {
 		return fmt.Errorf("invalid host: %q", host)
@@ -28,6 +55,13 @@ func validateBladeSerial(serial string) error {
 	return nil
 }
 
+func validateWorkflowName(name string) error {
+	if name == "" || strings.ContainsAny(name, " =") {
+		return fmt.Errorf("invalid workflow: %q", name)
+	}
+	return nil
+}
+
 func unmarshalRequest(c *gin.Context) (*request, error) {
 	req := &request{}
 	if err := c.ShouldBindJSON(req); err != nil {
@@ -35,3 +69,14 @@ func unmarshalRequest(c *gin.Context) (*request, error) {
 	}
 	return req, nil
 }
+
+func unmarshalWorkflowRequest(c *gin.Context) (*workflowRequest, error) {
+	req := &workflowRequest{}
+	if c.Request.ContentLength == 0 {
+		return req, nil
+	}
+	if err := c.ShouldBindJSON(req); err != nil {
+		return nil, err
+	}
+	return req, nil
+}
@@ -1,7 +1,11 @@
 package routes
 
 import (
+	"net/http"
+	"net/http/httptest"
 	"testing"
+
+	"github.com/gin-gonic/gin"
 )
 
 func Test_validateBladePos(t *testing.T) {
@@ -105,3 +109,28 @@ func Test_validateHost(t *testing.T) {
 		})
 	}
 }
+
+func Test_isStreamRequested(t *testing.T) {
+	tests := []struct {
+		name   string
+		url    string
+		accept string
+		want   bool
+	}{
+		{name: "Query", url: "/host/1.1.1.1?stream=true", want: true},
+		{name: "Query false wins over header", url: "/host/1.1.1.1?stream=false", accept: "text/event-stream", want: false},
+		{name: "Accept header", url: "/host/1.1.1.1", accept: "text/event-stream", want: true},
+		{name: "Neither", url: "/host/1.1.1.1", accept: "application/json", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
+			ctx.Request = httptest.NewRequest(http.MethodPost, tt.url, nil)
+			ctx.Request.Header.Set("Accept", tt.accept)
+
+			if got := isStreamRequested(ctx); got != tt.want {
+				t.Errorf("isStreamRequested() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
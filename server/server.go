@@ -19,6 +19,7 @@ type (
 		IsDebug           bool
 		Address           string
 		ScreenshotStorage string
+		FirmwareStorage   string
 	}
 
 	APIs struct {
@@ -26,6 +27,23 @@ type (
 		ChassisAPI       *routes.ChassisAPI
 		BladeByPosAPI    *routes.BladeByPosAPI
 		BladeBySerialAPI *routes.BladeBySerialAPI
+		ScreenshotAPI    *routes.ScreenshotAPI
+		SelAPI           *routes.SelAPI
+		SensorAPI        *routes.SensorAPI
+		FirmwareAPI      *routes.FirmwareAPI
+		UserAPI          *routes.UserAPI
+		RotationAPI      *routes.RotationAPI
+		ConfigAPI        *routes.ConfigAPI
+		CertificateAPI   *routes.CertificateAPI
+		BladeAPI         *routes.BladeAPI
+		DiscoveryAPI     *routes.DiscoveryAPI
+		SafeguardAPI     *routes.SafeguardAPI
+		// RegistryAPI is optional, the registry is not exposed nor enforced when it is nil
+		RegistryAPI *routes.RegistryAPI
+		// BulkAPI is optional, it needs the registry to resolve selectors
+		BulkAPI *routes.BulkAPI
+		// ScheduleAPI is optional, schedules are not exposed when it is nil
+		ScheduleAPI *routes.ScheduleAPI
 	}
 )
 
@@ -41,7 +59,7 @@ func New(config *Config, middlewares []gin.HandlerFunc, apis *APIs) (*Server, er
 		return nil, fmt.Errorf("failed to set up doc: %w", err)
 	}
 
-	if err := setupStatic(router, config.ScreenshotStorage); err != nil {
+	if err := setupStatic(router, config.ScreenshotStorage, config.FirmwareStorage); err != nil {
 		return nil, fmt.Errorf("failed to set up static: %w", err)
 	}
 
@@ -73,7 +91,7 @@ func setupDoc(router *gin.Engine) error {
 	return nil
 }
 
-func setupStatic(router *gin.Engine, screenshotStorage string) error {
+func setupStatic(router *gin.Engine, screenshotStorage, firmwareStorage string) error {
 	staticBox, err := rice.FindBox("static")
 	if err != nil {
 		return err
@@ -81,11 +99,17 @@ func setupStatic(router *gin.Engine, screenshotStorage string) error {
 
 	router.StaticFS("/static", staticBox.HTTPBox())
 	router.Static("/screenshot", screenshotStorage)
+	router.Static("/firmware/files", firmwareStorage)
 
 	return nil
 }
 
 func setupRoutes(router *gin.Engine, apis *APIs) {
+	// the policy has to be in place before the routes it guards are added
+	if apis.RegistryAPI != nil {
+		router.Use(apis.RegistryAPI.Policy)
+	}
+
 	router.GET("/", func(c *gin.Context) {
 		c.HTML(200, "doc.tmpl", gin.H{})
 	})
@@ -93,16 +117,95 @@ func setupRoutes(router *gin.Engine, apis *APIs) {
 	// Host level actions
 	router.GET("/host/:host", apis.HostAPI.HostPowerStatus)
 	router.POST("/host/:host", apis.HostAPI.HostExecuteActions)
+	router.GET("/host/:host/capabilities", apis.HostAPI.HostCapabilities)
+	router.POST("/host/:host/workflows/:name", apis.HostAPI.HostExecuteWorkflow)
+	router.GET("/host/:host/screenshots", apis.ScreenshotAPI.HostScreenshots)
+	router.DELETE("/host/:host/screenshots", apis.ScreenshotAPI.DeleteHostScreenshots)
+	router.DELETE("/host/:host/screenshots/:key", apis.ScreenshotAPI.DeleteHostScreenshot)
+	router.GET("/host/:host/sel", apis.SelAPI.HostSel)
+	router.GET("/host/:host/sensors", apis.SensorAPI.HostSensors)
+	router.GET("/host/:host/firmware", apis.FirmwareAPI.HostFirmware)
+	router.GET("/host/:host/users", apis.UserAPI.HostUsers)
+	router.POST("/host/:host/users", apis.UserAPI.CreateHostUser)
+	router.PUT("/host/:host/users/:name", apis.UserAPI.UpdateHostUser)
+	router.DELETE("/host/:host/users/:name", apis.UserAPI.DeleteHostUser)
+	router.POST("/host/:host/config", apis.ConfigAPI.ConfigureHost)
+	router.GET("/host/:host/certificate", apis.CertificateAPI.Certificate)
+	router.PUT("/host/:host/certificate", apis.CertificateAPI.UploadHostCertificate)
+	router.POST("/host/:host/certificate/csr", apis.CertificateAPI.HostCSR)
 
 	// Chassis level actions
 	router.GET("/chassis/:host", apis.ChassisAPI.ChassisPowerStatus)
 	router.POST("/chassis/:host", apis.ChassisAPI.ChassisExecuteActions)
+	router.GET("/chassis/:host/capabilities", apis.ChassisAPI.ChassisCapabilities)
+	router.POST("/chassis/:host/workflows/:name", apis.ChassisAPI.ChassisExecuteWorkflow)
+	router.GET("/chassis/:host/sensors", apis.SensorAPI.ChassisSensors)
+	router.GET("/chassis/:host/firmware", apis.FirmwareAPI.ChassisFirmware)
+	router.POST("/chassis/:host/config", apis.ConfigAPI.ConfigureChassis)
+	router.GET("/chassis/:host/certificate", apis.CertificateAPI.Certificate)
+	router.PUT("/chassis/:host/certificate", apis.CertificateAPI.UploadChassisCertificate)
+	router.POST("/chassis/:host/certificate/csr", apis.CertificateAPI.ChassisCSR)
+
+	// Users of all the blade BMCs of a chassis
+	router.POST("/chassis/:host/blades/users", apis.UserAPI.CreateBladeUsers)
+	router.PUT("/chassis/:host/blades/users/:name", apis.UserAPI.UpdateBladeUsers)
+	router.DELETE("/chassis/:host/blades/users/:name", apis.UserAPI.DeleteBladeUsers)
+
+	// Blades located by serial across all known chassis
+	router.GET("/blades/:serial", apis.BladeAPI.LocateBlade)
+
+	// BMCs discovered by scanning CIDR ranges
+	router.POST("/discover", apis.DiscoveryAPI.CreateDiscovery)
+	router.GET("/discover", apis.DiscoveryAPI.ListDiscoveries)
+	router.GET("/discover/:id", apis.DiscoveryAPI.GetDiscovery)
+
+	// Blast-radius limits of destructive actions
+	router.GET("/admin/safeguard", apis.SafeguardAPI.SafeguardStatus)
+	router.POST("/admin/safeguard/reset", apis.SafeguardAPI.ResetBreaker)
 
 	// Blade action on chassis level by position
 	router.GET("/chassis/:host/position/:pos", apis.BladeByPosAPI.ChassisBladePowerStatusByPosition)
 	router.POST("/chassis/:host/position/:pos", apis.BladeByPosAPI.ChassisBladeExecuteActionsByPosition)
+	router.GET("/chassis/:host/position/:pos/capabilities", apis.BladeByPosAPI.ChassisBladeCapabilitiesByPosition)
+	router.POST("/chassis/:host/position/:pos/workflows/:name", apis.BladeByPosAPI.ChassisBladeExecuteWorkflowByPosition)
+	router.GET("/chassis/:host/position/:pos/users", apis.UserAPI.BladeUsers)
 
 	// Blade action on chassis level by serial
 	router.GET("/chassis/:host/serial/:serial", apis.BladeBySerialAPI.ChassisBladePowerStatusBySerial)
 	router.POST("/chassis/:host/serial/:serial", apis.BladeBySerialAPI.ChassisBladeExecuteActionsBySerial)
+	router.GET("/chassis/:host/serial/:serial/capabilities", apis.BladeBySerialAPI.ChassisBladeCapabilitiesBySerial)
+	router.POST("/chassis/:host/serial/:serial/workflows/:name", apis.BladeBySerialAPI.ChassisBladeExecuteWorkflowBySerial)
+
+	// Firmware artifacts
+	router.GET("/firmware", apis.FirmwareAPI.ListArtifacts)
+	router.POST("/firmware", apis.FirmwareAPI.UploadArtifact)
+
+	// Fleet certificate expiry report
+	router.POST("/certificates/report", apis.CertificateAPI.Report)
+
+	// BMC password rotations
+	router.GET("/rotations", apis.RotationAPI.ListRotations)
+	router.POST("/rotations", apis.RotationAPI.CreateRotation)
+	router.GET("/rotations/:id", apis.RotationAPI.GetRotation)
+	router.POST("/rotations/:id/resume", apis.RotationAPI.ResumeRotation)
+
+	if apis.RegistryAPI != nil {
+		router.GET("/targets", apis.RegistryAPI.ListTargets)
+		router.GET("/targets/:target", apis.RegistryAPI.GetTarget)
+		router.PUT("/targets/:target", apis.RegistryAPI.PutTarget)
+		router.DELETE("/targets/:target", apis.RegistryAPI.DeleteTarget)
+	}
+
+	if apis.BulkAPI != nil {
+		router.POST("/targets/actions", apis.BulkAPI.ExecuteActions)
+		router.GET("/targets/actions", apis.BulkAPI.ListRuns)
+		router.GET("/targets/actions/:id", apis.BulkAPI.GetRun)
+	}
+
+	if apis.ScheduleAPI != nil {
+		router.GET("/schedules", apis.ScheduleAPI.ListSchedules)
+		router.POST("/schedules", apis.ScheduleAPI.CreateSchedule)
+		router.GET("/schedules/:id", apis.ScheduleAPI.GetSchedule)
+		router.DELETE("/schedules/:id", apis.ScheduleAPI.CancelSchedule)
+	}
 }